@@ -0,0 +1,72 @@
+// Contest helpers: list returns [{ id, slug, title, scoring, starts_at, ends_at, status, frozen, ... }],
+// a single contest adds { registered, problems: [{ label, problem_id, slug, title }] } once it started
+
+function contestHeaders() {
+    const authToken = localStorage.getItem('authToken');
+    return authToken ? { 'Authorization': `Bearer ${authToken}` } : {};
+}
+
+async function fetchContests() {
+    try {
+        const response = await fetch('/api/contests');
+        if (!response.ok) throw new Error(`Failed to load contests: ${response.status}`);
+        return await response.json();
+    } catch (error) {
+        console.error('Error fetching contests:', error);
+        throw error;
+    }
+}
+
+async function loadContest(contestId) {
+    try {
+        const response = await fetch(`/api/contests/${encodeURIComponent(contestId)}`, { headers: contestHeaders() });
+        if (!response.ok) throw new Error(`Failed to load contest: ${response.status}`);
+        return await response.json();
+    } catch (error) {
+        console.error('Error loading contest:', error);
+        throw error;
+    }
+}
+
+async function registerForContest(contestId) {
+    const response = await fetch(`/api/contests/${encodeURIComponent(contestId)}/registration`, {
+        method: 'POST',
+        headers: contestHeaders(),
+    });
+    if (!response.ok) throw new Error(await response.text());
+    return await response.json();
+}
+
+// Scoreboard: { scoring, frozen, frozen_at, problems, rows: [{ rank, username, solved, penalty, score, cells }] }
+async function loadScoreboard(contestId) {
+    const response = await fetch(`/api/contests/${encodeURIComponent(contestId)}/scoreboard`);
+    if (!response.ok) throw new Error(`Failed to load scoreboard: ${response.status}`);
+    return await response.json();
+}
+
+async function submitContestSolution(contestId, label, source, language) {
+    const response = await fetch(`/api/contests/${encodeURIComponent(contestId)}/problems/${encodeURIComponent(label)}/submissions`, {
+        method: 'POST',
+        headers: { ...contestHeaders(), 'Content-Type': 'application/json' },
+        body: JSON.stringify({ language, source }),
+    });
+    if (!response.ok) throw new Error(await response.text());
+    return await response.json();
+}
+
+// Virtual participation: { started_at, ends_at, status, rank, participants, row, problems, rows }
+async function startVirtualParticipation(contestId) {
+    const response = await fetch(`/api/contests/${encodeURIComponent(contestId)}/virtual`, {
+        method: 'POST',
+        headers: contestHeaders(),
+    });
+    if (!response.ok) throw new Error(await response.text());
+    return await response.json();
+}
+
+async function loadVirtualParticipation(contestId) {
+    const response = await fetch(`/api/contests/${encodeURIComponent(contestId)}/virtual`, { headers: contestHeaders() });
+    if (response.status === 404) return null;
+    if (!response.ok) throw new Error(`Failed to load virtual participation: ${response.status}`);
+    return await response.json();
+}
@@ -11,11 +11,12 @@ async function loadLesson(lessonId) {
             await fetchLessons();
         }
         
-        const lesson = info.find(l => l.id === String(lessonId));
+        const lesson = info.find(l => String(l.id) === String(lessonId));
         if (!lesson) throw new Error('Lesson not found');
         
-        const response = await fetch(lesson.path);
-        content = await response.text();
+        const response = await fetch(`/api/lessons/${lesson.id}`);
+        if (!response.ok) throw new Error(`Failed to load lesson: ${response.status}`);
+        content = (await response.json()).body;
 
         console.log('Lesson found:', lesson);
         console.log('Content:', content);
@@ -29,13 +30,25 @@ async function loadLesson(lessonId) {
 
 async function fetchLessons() {
     try {
-        const response = await fetch('/app/Lectii/manifest.json');
+        const response = await fetch('/api/lessons');
         const data = await response.json();
-        console.log('Fetched lessons:', data.lectii);
-        info = data.lectii; 
+        console.log('Fetched lessons:', data);
+        info = data; 
         return info;
     } catch (error) {
         console.error('Error fetching lessons:', error);
         throw error;
     }
-}
\ No newline at end of file
+}
+
+// Lesson rendered on the server: { html, toc: [{ level, id, text }], hash, ...lesson }
+async function loadRenderedLesson(lessonId) {
+    try {
+        const response = await fetch(`/api/lessons/${encodeURIComponent(lessonId)}/html`);
+        if (!response.ok) throw new Error(`Failed to load lesson: ${response.status}`);
+        return await response.json();
+    } catch (error) {
+        console.error('Error loading rendered lesson:', error);
+        throw error;
+    }
+}
@@ -0,0 +1,117 @@
+// Problem bank helpers: list returns [{ id, slug, title, difficulty, tags, ... }],
+// a single problem adds { statement_html, input_format, output_format, samples }
+
+async function fetchProblems(filters = {}) {
+    try {
+        const query = new URLSearchParams(filters).toString();
+        const response = await fetch(`/api/problems${query ? `?${query}` : ''}`);
+        if (!response.ok) throw new Error(`Failed to load problems: ${response.status}`);
+        return await response.json();
+    } catch (error) {
+        console.error('Error fetching problems:', error);
+        throw error;
+    }
+}
+
+async function loadProblem(problemId) {
+    try {
+        const response = await fetch(`/api/problems/${encodeURIComponent(problemId)}`);
+        if (!response.ok) throw new Error(`Failed to load problem: ${response.status}`);
+        return await response.json();
+    } catch (error) {
+        console.error('Error loading problem:', error);
+        throw error;
+    }
+}
+
+// Languages come back in the order the picker should offer them: [{ id, name, extension, time_multiplier, ... }]
+async function fetchLanguages() {
+    try {
+        const response = await fetch('/api/languages');
+        if (!response.ok) throw new Error(`Failed to load languages: ${response.status}`);
+        return await response.json();
+    } catch (error) {
+        console.error('Error fetching languages:', error);
+        throw error;
+    }
+}
+
+async function submitSolution(problemId, source, language = 'cpp') {
+    const authToken = localStorage.getItem('authToken');
+    const response = await fetch(`/api/problems/${encodeURIComponent(problemId)}/submissions`, {
+        method: 'POST',
+        headers: {
+            'Authorization': `Bearer ${authToken}`,
+            'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ language, source }),
+    });
+    if (!response.ok) throw new Error(await response.text());
+    return await response.json();
+}
+
+// Runs code once on custom input, returns { verdict, compile_output, stdout, stderr, exit_code, time_ms, memory_kb }
+async function runCode(source, language, stdin, problem = '') {
+    const authToken = localStorage.getItem('authToken');
+    const response = await fetch('/api/run', {
+        method: 'POST',
+        headers: {
+            'Authorization': `Bearer ${authToken}`,
+            'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ language, source, stdin, problem }),
+    });
+    if (!response.ok) throw new Error(await response.text());
+    return await response.json();
+}
+
+async function loadSubmission(submissionId) {
+    const authToken = localStorage.getItem('authToken');
+    const response = await fetch(`/api/submissions/${encodeURIComponent(submissionId)}`, {
+        headers: { 'Authorization': `Bearer ${authToken}` },
+    });
+    if (!response.ok) throw new Error(`Failed to load submission: ${response.status}`);
+    return await response.json();
+}
+
+// Follows /api/submissions/{id}/events, calls onEvent(name, data) for status, compile, test and done events.
+// EventSource cannot send the Authorization header, so the stream is read through fetch.
+async function streamSubmission(submissionId, onEvent) {
+    const authToken = localStorage.getItem('authToken');
+    const response = await fetch(`/api/submissions/${encodeURIComponent(submissionId)}/events`, {
+        headers: { 'Authorization': `Bearer ${authToken}` },
+    });
+    if (!response.ok) throw new Error(`Failed to follow submission: ${response.status}`);
+
+    const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+    let buffer = '';
+    for (;;) {
+        const { value, done } = await reader.read();
+        if (done) return;
+        buffer += value;
+        let end;
+        while ((end = buffer.indexOf('\n\n')) !== -1) {
+            const block = buffer.slice(0, end);
+            buffer = buffer.slice(end + 2);
+            let name = 'message';
+            let data = '';
+            block.split('\n').forEach(line => {
+                if (line.startsWith('event: ')) name = line.slice(7);
+                if (line.startsWith('data: ')) data += line.slice(6);
+            });
+            if (data) onEvent(name, JSON.parse(data));
+        }
+    }
+}
+
+// Plagiarism report for teachers: { compared, pairs: [{ a, b, similarity, matches: [{ a, b, tokens }] }], submissions }
+// where a match spans { start_line, end_line } in each source and submissions maps IDs to { username, source, ... }
+async function checkPlagiarism(problemId, filters = {}) {
+    const authToken = localStorage.getItem('authToken');
+    const query = new URLSearchParams(filters).toString();
+    const response = await fetch(`/api/problems/${encodeURIComponent(problemId)}/plagiarism${query ? `?${query}` : ''}`, {
+        headers: { 'Authorization': `Bearer ${authToken}` },
+    });
+    if (!response.ok) throw new Error(await response.text());
+    return await response.json();
+}
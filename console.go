@@ -1,10 +1,16 @@
 package main
 
 import (
+	"Codium/internal/database"
+	"Codium/internal/plagiarism"
 	"bufio"
+	"context"
+	"database/sql"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -84,7 +90,200 @@ func (cfg *ApiCfg) StartConsole() {
 			}
 			fmt.Println("Users:")
 			for _, user := range users {
-				fmt.Printf(" - ID: %s, Email: %s, CreatedAt: %s\n", user.ID, user.Email, user.CreatedAt)
+				fmt.Printf(" - ID: %s, Email: %s, CreatedAt: %s\n", user.ID, user.Email, user.CreatedAt.Time)
+			}
+			return nil
+		})
+		cfg.RegisterCommand("set_teacher", func(args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("usage: set_teacher <user_id> <true|false>")
+			}
+			cfg.logger.Printf("Received set_teacher command via console for user ID %s", args[0])
+			if !cfg.dbLoaded {
+				return fmt.Errorf("database not connected")
+			}
+
+			userId, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user ID format")
+			}
+			isTeacher, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid value %q, expected true or false", args[1])
+			}
+
+			_, err = cfg.db.UpdateUserTeacher(context.Background(), database.UpdateUserTeacherParams{
+				ID:        userId,
+				IsTeacher: isTeacher,
+				UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("User %s teacher rights set to %t.\n", userId, isTeacher)
+			return nil
+		})
+		cfg.RegisterCommand("rescan_lessons", func(args []string) error {
+			cfg.logger.Print("Received rescan_lessons command via console")
+			count, err := cfg.RescanLessons()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Lesson catalog rebuilt with %d lessons.\n", count)
+			return nil
+		})
+		cfg.RegisterCommand("judge_file", func(args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("usage: judge_file <problem_id|slug> <source file>")
+			}
+			cfg.logger.Printf("Received judge_file command via console for problem %s", args[0])
+			if !cfg.dbLoaded {
+				return fmt.Errorf("database not connected")
+			}
+			result, err := cfg.JudgeFile(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if result.CompileOutput != "" {
+				fmt.Println(result.CompileOutput)
+			}
+			for _, test := range result.Tests {
+				fmt.Printf(" - Test %s: %s (%d ms, %d KB) %s\n", test.Name, test.Verdict, test.TimeMs, test.MemoryKb, test.Message)
+			}
+			fmt.Printf("Verdict: %s\n", result.Verdict)
+			return nil
+		})
+		cfg.RegisterCommand("rejudge_problem", func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: rejudge_problem <problem_id|slug>")
+			}
+			cfg.logger.Printf("Received rejudge_problem command via console for problem %s", args[0])
+			if !cfg.dbLoaded {
+				return fmt.Errorf("database not connected")
+			}
+			problem, err := cfg.GetProblem(context.Background(), args[0])
+			if err != nil {
+				return fmt.Errorf("problem %s not found: %v", args[0], err)
+			}
+			rejudge, count, err := cfg.RejudgeProblem(context.Background(), problem, uuid.NullUUID{})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Rejudge %s queued %d submissions, check it with rejudge_status %s\n", rejudge.ID, count, rejudge.ID)
+			return nil
+		})
+		cfg.RegisterCommand("rejudge_submission", func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: rejudge_submission <submission_id>")
+			}
+			cfg.logger.Printf("Received rejudge_submission command via console for submission %s", args[0])
+			if !cfg.dbLoaded {
+				return fmt.Errorf("database not connected")
+			}
+			submissionID, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid submission ID format")
+			}
+			submission, err := cfg.db.GetSubmissionByID(context.Background(), submissionID)
+			if err != nil {
+				return fmt.Errorf("submission %s not found: %v", args[0], err)
+			}
+			rejudge, err := cfg.RejudgeSubmission(context.Background(), submission, uuid.NullUUID{})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Rejudge %s queued submission %s, was %s (%d points)\n", rejudge.ID, submission.ID, submission.Verdict, submission.Score)
+			return nil
+		})
+		cfg.RegisterCommand("rejudge_status", func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: rejudge_status <rejudge_id>")
+			}
+			if !cfg.dbLoaded {
+				return fmt.Errorf("database not connected")
+			}
+			rejudgeID, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid rejudge ID format")
+			}
+			rejudge, err := cfg.db.GetRejudgeByID(context.Background(), rejudgeID)
+			if err != nil {
+				return fmt.Errorf("rejudge %s not found: %v", args[0], err)
+			}
+			report, err := cfg.RejudgeReport(context.Background(), rejudge)
+			if err != nil {
+				return err
+			}
+			for _, submission := range report.Submissions {
+				if submission.Changed {
+					fmt.Printf(" - Submission %s: %s (%d) -> %s (%d)\n", submission.SubmissionID, submission.OldVerdict, submission.OldScore, submission.Verdict, submission.Score)
+				}
+			}
+			fmt.Printf("Rejudged %d of %d submissions, %d verdicts changed.\n", report.Total-report.Pending, report.Total, report.Changed)
+			return nil
+		})
+		cfg.RegisterCommand("plagiarism", func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: plagiarism <problem_id|slug> [min_similarity]")
+			}
+			cfg.logger.Printf("Received plagiarism command via console for problem %s", args[0])
+			if !cfg.dbLoaded {
+				return fmt.Errorf("database not connected")
+			}
+			opts := plagiarism.Options{MinSimilarity: defaultPlagiarismSimilarity, Limit: defaultPlagiarismPairs}
+			if len(args) > 1 {
+				similarity, err := strconv.ParseFloat(args[1], 64)
+				if err != nil || similarity < 0 || similarity > 1 {
+					return fmt.Errorf("invalid similarity %q, expected a number between 0 and 1", args[1])
+				}
+				opts.MinSimilarity = similarity
+			}
+			problem, err := cfg.GetProblem(context.Background(), args[0])
+			if err != nil {
+				return fmt.Errorf("problem %s not found: %v", args[0], err)
+			}
+			report, err := cfg.CheckPlagiarism(context.Background(), problem, uuid.NullUUID{}, opts)
+			if err != nil {
+				return err
+			}
+			for _, pair := range report.Pairs {
+				a, b := report.Submissions[pair.A], report.Submissions[pair.B]
+				fmt.Printf(" - %.0f%% %s (%s) / %s (%s)\n", pair.Similarity*100, a.Username, a.ID, b.Username, b.ID)
+				for _, match := range pair.Matches {
+					fmt.Printf("     lines %d-%d ~ %d-%d (%d tokens)\n", match.A.StartLine, match.A.EndLine, match.B.StartLine, match.B.EndLine, match.Tokens)
+				}
+			}
+			fmt.Printf("Compared %d submissions, %d suspicious pairs.\n", report.Compared, len(report.Pairs))
+			return nil
+		})
+		cfg.RegisterCommand("import_polygon", func(args []string) error {
+			if len(args) < 2 {
+				return fmt.Errorf("usage: import_polygon <package.zip> <owner_user_id>")
+			}
+			cfg.logger.Printf("Received import_polygon command via console for package %s", args[0])
+			if !cfg.dbLoaded {
+				return fmt.Errorf("database not connected")
+			}
+			owner, err := uuid.Parse(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid user ID %q: %v", args[1], err)
+			}
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open package: %v", err)
+			}
+			defer file.Close()
+			info, err := file.Stat()
+			if err != nil {
+				return fmt.Errorf("failed to read package: %v", err)
+			}
+			problem, pkg, err := cfg.ImportPolygonPackage(context.Background(), file, info.Size(), owner)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Imported problem %s (%s) with %d tests and %d subtasks.\n", problem.Slug, problem.ID, len(pkg.TestNames()), len(pkg.Subtasks))
+			for _, feature := range pkg.Unsupported {
+				fmt.Printf(" - unsupported: %s\n", feature)
 			}
 			return nil
 		})
@@ -0,0 +1,822 @@
+package main
+
+import (
+	"Codium/internal/contests"
+	"Codium/internal/database"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Contests
+
+===========================================
+*/
+
+var (
+	ErrContestSlugTaken      = errors.New("slug is already used by another contest")
+	ErrInvalidContestProblem = errors.New("invalid contest problem")
+)
+
+type ContestProblemResponse struct {
+	Label         string    `json:"label"`
+	ProblemID     uuid.UUID `json:"problem_id"`
+	Slug          string    `json:"slug"`
+	Title         string    `json:"title"`
+	TimeLimitMs   int32     `json:"time_limit_ms"`
+	MemoryLimitMb int32     `json:"memory_limit_mb"`
+}
+
+type ContestResponse struct {
+	ID            uuid.UUID                `json:"id"`
+	Slug          string                   `json:"slug"`
+	Title         string                   `json:"title"`
+	Description   string                   `json:"description"`
+	Scoring       string                   `json:"scoring"`
+	StartsAt      time.Time                `json:"starts_at"`
+	EndsAt        time.Time                `json:"ends_at"`
+	FreezeMinutes int32                    `json:"freeze_minutes"`
+	Frozen        bool                     `json:"frozen"`
+	UnfrozenAt    *time.Time               `json:"unfrozen_at"`
+	Status        string                   `json:"status"`
+	AuthorID      uuid.NullUUID            `json:"author_id"`
+	Registered    *bool                    `json:"registered,omitempty"`
+	Problems      []ContestProblemResponse `json:"problems,omitempty"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+}
+
+func ContestWindow(contest database.Contest) contests.Window {
+	return contests.Window{
+		StartsAt:      contest.StartsAt,
+		EndsAt:        contest.EndsAt,
+		FreezeMinutes: contest.FreezeMinutes,
+		Unfrozen:      contest.UnfrozenAt.Valid,
+	}
+}
+
+// ContestStatus is upcoming, running or ended at now
+func ContestStatus(contest database.Contest, now time.Time) string {
+	switch {
+	case now.Before(contest.StartsAt):
+		return "upcoming"
+	case now.Before(contest.EndsAt):
+		return "running"
+	default:
+		return "ended"
+	}
+}
+
+func ContestToResponse(contest database.Contest) ContestResponse {
+	now := time.Now()
+	return ContestResponse{
+		ID:            contest.ID,
+		Slug:          contest.Slug,
+		Title:         contest.Title,
+		Description:   contest.Description,
+		Scoring:       contest.Scoring,
+		StartsAt:      contest.StartsAt,
+		EndsAt:        contest.EndsAt,
+		FreezeMinutes: contest.FreezeMinutes,
+		Frozen:        ContestWindow(contest).Frozen(now),
+		UnfrozenAt:    nullTimeToPtr(contest.UnfrozenAt),
+		Status:        ContestStatus(contest, now),
+		AuthorID:      contest.AuthorID,
+		CreatedAt:     contest.CreatedAt,
+		UpdatedAt:     contest.UpdatedAt,
+	}
+}
+
+func ContestProblemToResponse(problem database.GetContestProblemsRow) ContestProblemResponse {
+	return ContestProblemResponse{
+		Label:         problem.Label,
+		ProblemID:     problem.ID,
+		Slug:          problem.Slug,
+		Title:         problem.Title,
+		TimeLimitMs:   problem.TimeLimitMs,
+		MemoryLimitMb: problem.MemoryLimitMb,
+	}
+}
+
+// GetContest looks a contest up by its ID or, failing that, by its slug
+func (cfg *ApiCfg) GetContest(ctx context.Context, key string) (database.Contest, error) {
+	id, err := uuid.Parse(key)
+	if err == nil {
+		return cfg.db.GetContestByID(ctx, id)
+	}
+	return cfg.db.GetContestBySlug(ctx, key)
+}
+
+// contestFromRequest loads the contest named in the path, writing the error if there is none
+func (cfg *ApiCfg) contestFromRequest(w http.ResponseWriter, r *http.Request) (database.Contest, bool) {
+	key := r.PathValue("contestID")
+	contest, err := cfg.GetContest(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Contest not found: %v", key)
+			http.Error(w, "Contest not found", http.StatusNotFound)
+			return database.Contest{}, false
+		}
+		cfg.logger.Printf("Failed to retrieve contest: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return database.Contest{}, false
+	}
+	return contest, true
+}
+
+// SaveContest creates a contest when existing is nil or replaces it otherwise, problem set included
+func (cfg *ApiCfg) SaveContest(ctx context.Context, existing *database.Contest, spec contests.Spec, authorID uuid.NullUUID) (database.Contest, error) {
+	other, err := cfg.db.GetContestBySlug(ctx, spec.Slug)
+	if err == nil && (existing == nil || other.ID != existing.ID) {
+		return database.Contest{}, ErrContestSlugTaken
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return database.Contest{}, fmt.Errorf("failed to check slug: %v", err)
+	}
+
+	problemIDs := make([]uuid.UUID, 0, len(spec.Problems))
+	seen := make(map[uuid.UUID]bool)
+	for _, key := range spec.Problems {
+		problem, err := cfg.GetProblem(ctx, key)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return database.Contest{}, fmt.Errorf("%w: unknown problem %v", ErrInvalidContestProblem, key)
+			}
+			return database.Contest{}, fmt.Errorf("failed to retrieve problem %v: %v", key, err)
+		}
+		if seen[problem.ID] {
+			return database.Contest{}, fmt.Errorf("%w: %v is listed twice", ErrInvalidContestProblem, key)
+		}
+		seen[problem.ID] = true
+		problemIDs = append(problemIDs, problem.ID)
+	}
+
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Contest{}, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	var contest database.Contest
+	if existing == nil {
+		contest, err = qtx.CreateContest(ctx, database.CreateContestParams{
+			ID:            uuid.New(),
+			Slug:          spec.Slug,
+			Title:         spec.Title,
+			Description:   spec.Description,
+			Scoring:       string(spec.Scoring),
+			StartsAt:      spec.StartsAt,
+			EndsAt:        spec.EndsAt,
+			FreezeMinutes: spec.FreezeMinutes,
+			AuthorID:      authorID,
+			CreatedAt:     time.Now(),
+		})
+	} else {
+		contest, err = qtx.UpdateContest(ctx, database.UpdateContestParams{
+			ID:            existing.ID,
+			Slug:          spec.Slug,
+			Title:         spec.Title,
+			Description:   spec.Description,
+			Scoring:       string(spec.Scoring),
+			StartsAt:      spec.StartsAt,
+			EndsAt:        spec.EndsAt,
+			FreezeMinutes: spec.FreezeMinutes,
+			UpdatedAt:     time.Now(),
+		})
+	}
+	if err != nil {
+		return database.Contest{}, fmt.Errorf("failed to store contest: %v", err)
+	}
+
+	err = qtx.DeleteContestProblems(ctx, contest.ID)
+	if err != nil {
+		return database.Contest{}, fmt.Errorf("failed to clear problems: %v", err)
+	}
+	for i, problemID := range problemIDs {
+		err = qtx.AddContestProblem(ctx, database.AddContestProblemParams{
+			ContestID: contest.ID,
+			ProblemID: problemID,
+			Label:     contests.Label(i),
+			Position:  int32(i + 1),
+		})
+		if err != nil {
+			return database.Contest{}, fmt.Errorf("failed to store problem %v: %v", contests.Label(i), err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return database.Contest{}, fmt.Errorf("failed to commit contest: %v", err)
+	}
+	return contest, nil
+}
+
+// contestDetail adds the problem set to a contest, students only see it once the contest started
+func (cfg *ApiCfg) contestDetail(ctx context.Context, contest database.Contest, user *database.User) (ContestResponse, error) {
+	res := ContestToResponse(contest)
+	if user != nil {
+		registered, err := cfg.db.IsRegisteredForContest(ctx, database.IsRegisteredForContestParams{ContestID: contest.ID, UserID: user.ID})
+		if err != nil {
+			return ContestResponse{}, fmt.Errorf("failed to check registration: %v", err)
+		}
+		res.Registered = &registered
+	}
+	if res.Status == "upcoming" && (user == nil || !user.IsAdmin) {
+		return res, nil
+	}
+
+	list, err := cfg.db.GetContestProblems(ctx, contest.ID)
+	if err != nil {
+		return ContestResponse{}, fmt.Errorf("failed to retrieve problems: %v", err)
+	}
+	res.Problems = make([]ContestProblemResponse, 0, len(list))
+	for _, problem := range list {
+		res.Problems = append(res.Problems, ContestProblemToResponse(problem))
+	}
+	return res, nil
+}
+
+// decodeContestSpec reads and validates a contest from the request body, writing the error on failure
+func (cfg *ApiCfg) decodeContestSpec(w http.ResponseWriter, r *http.Request) (contests.Spec, bool) {
+	decoder := json.NewDecoder(r.Body)
+	var spec contests.Spec
+	err := decoder.Decode(&spec)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return spec, false
+	}
+	err = spec.Normalize()
+	if err != nil {
+		cfg.logger.Printf("Invalid contest: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return spec, false
+	}
+	return spec, true
+}
+
+// respondContestSaveError maps the errors of SaveContest to responses
+func (cfg *ApiCfg) respondContestSaveError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrContestSlugTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrInvalidContestProblem):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		cfg.logger.Printf("Failed to save contest: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (cfg *ApiCfg) GetContestsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	limit, offset := 50, 0
+	var err error
+	if q.Get("limit") != "" {
+		limit, err = strconv.Atoi(q.Get("limit"))
+		if err != nil || limit <= 0 || limit > 200 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	if q.Get("offset") != "" {
+		offset, err = strconv.Atoi(q.Get("offset"))
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := cfg.db.GetContests(r.Context(), database.GetContestsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve contests: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]ContestResponse, 0, len(list))
+	for _, contest := range list {
+		res = append(res, ContestToResponse(contest))
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) GetContestHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var user *database.User
+	if u, err := cfg.AuthenticateUser(r); err == nil {
+		user = &u
+	}
+	res, err := cfg.contestDetail(r.Context(), contest, user)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) CreateContestHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized contest creation by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	spec, ok := cfg.decodeContestSpec(w, r)
+	if !ok {
+		return
+	}
+
+	cfg.logger.Printf("Received contest creation request: %v", spec.Slug)
+
+	contest, err := cfg.SaveContest(r.Context(), nil, spec, uuid.NullUUID{UUID: adminUser.ID, Valid: true})
+	if err != nil {
+		cfg.respondContestSaveError(w, err)
+		return
+	}
+
+	res, err := cfg.contestDetail(r.Context(), contest, &adminUser)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusCreated, res)
+}
+
+func (cfg *ApiCfg) UpdateContestHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized contest update by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	existing, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+	spec, ok := cfg.decodeContestSpec(w, r)
+	if !ok {
+		return
+	}
+
+	cfg.logger.Printf("Received contest update request for: %v", existing.ID)
+
+	contest, err := cfg.SaveContest(r.Context(), &existing, spec, existing.AuthorID)
+	if err != nil {
+		cfg.respondContestSaveError(w, err)
+		return
+	}
+
+	res, err := cfg.contestDetail(r.Context(), contest, &adminUser)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) DeleteContestHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized contest deletion by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Submissions outlive the contest as practice submissions
+	err = cfg.db.DeleteContest(r.Context(), contest.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to delete contest: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.logger.Printf("Contest %v deleted by admin %v", contest.ID, adminUser.ID)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Contest deleted successfully."))
+	if err != nil {
+		cfg.logger.Printf("Failed to write response: %v", err)
+	}
+}
+
+// RegisterContestHandler signs the user up for a contest, late registration is allowed until the end
+func (cfg *ApiCfg) RegisterContestHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if ContestStatus(contest, time.Now()) == "ended" {
+		http.Error(w, "Contest has ended", http.StatusConflict)
+		return
+	}
+
+	err = cfg.db.RegisterForContest(r.Context(), database.RegisterForContestParams{
+		ContestID:    contest.ID,
+		UserID:       user.ID,
+		RegisteredAt: time.Now(),
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to register for contest: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.logger.Printf("User %v registered for contest %v", user.ID, contest.ID)
+
+	res, err := cfg.contestDetail(r.Context(), contest, &user)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// UnregisterContestHandler withdraws a registration, only possible before the contest starts
+func (cfg *ApiCfg) UnregisterContestHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if ContestStatus(contest, time.Now()) != "upcoming" {
+		http.Error(w, "Contest has already started", http.StatusConflict)
+		return
+	}
+
+	removed, err := cfg.db.UnregisterFromContest(r.Context(), database.UnregisterFromContestParams{
+		ContestID: contest.ID,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to unregister from contest: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if removed == 0 {
+		http.Error(w, "Not registered", http.StatusNotFound)
+		return
+	}
+	cfg.logger.Printf("User %v unregistered from contest %v", user.ID, contest.ID)
+
+	res, err := cfg.contestDetail(r.Context(), contest, &user)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// CreateContestSubmissionHandler accepts a submission from a registered participant while the contest runs or
+// from a virtual participant within their own window, the problem is named by its label, ID or slug
+func (cfg *ApiCfg) CreateContestSubmissionHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+	// Once the contest is over submissions are only accepted within a running virtual participation
+	now := time.Now()
+	virtual := !ContestWindow(contest).Running(now)
+	if virtual {
+		participation, err := cfg.db.GetVirtualParticipation(r.Context(), database.GetVirtualParticipationParams{ContestID: contest.ID, UserID: user.ID})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Failed to retrieve virtual participation: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if err != nil || !ContestWindow(contest).Virtual(participation.StartedAt).Running(now) {
+			http.Error(w, "Contest is not running", http.StatusForbidden)
+			return
+		}
+	} else {
+		registered, err := cfg.db.IsRegisteredForContest(r.Context(), database.IsRegisteredForContestParams{ContestID: contest.ID, UserID: user.ID})
+		if err != nil {
+			cfg.logger.Printf("Failed to check registration: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if !registered {
+			http.Error(w, "Not registered for this contest", http.StatusForbidden)
+			return
+		}
+	}
+
+	list, err := cfg.db.GetContestProblems(r.Context(), contest.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve contest problems: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	key := r.PathValue("problemID")
+	var problemID uuid.UUID
+	for _, problem := range list {
+		if problem.Label == key || problem.Slug == key || problem.ID.String() == key {
+			problemID = problem.ID
+			break
+		}
+	}
+	if problemID == uuid.Nil {
+		http.Error(w, "Problem not found", http.StatusNotFound)
+		return
+	}
+	problem, err := cfg.db.GetProblemByID(r.Context(), problemID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p, ok := cfg.decodeSubmission(w, r)
+	if !ok {
+		return
+	}
+
+	submission, err := cfg.Submit(r.Context(), user, problem, p, uuid.NullUUID{UUID: contest.ID, Valid: true}, virtual)
+	if err != nil {
+		cfg.logger.Printf("Failed to store submission: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := SubmissionToResponse(submission, nil, nil)
+	res.Source = submission.Source
+	cfg.RespondWithJSON(w, http.StatusAccepted, res)
+}
+
+// contestBoard is what the official scoreboard of a contest is computed from
+type contestBoard struct {
+	problemIDs   []uuid.UUID
+	problems     []ContestProblemResponse
+	participants []contests.Participant
+	attempts     []contests.Attempt
+}
+
+func attemptFromRow(row database.GetContestAttemptsRow) contests.Attempt {
+	return contests.Attempt{
+		UserID:    row.UserID,
+		ProblemID: row.ProblemID,
+		Done:      row.Status == "done",
+		Verdict:   row.Verdict,
+		Score:     row.Score,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// loadContestBoard gathers the registered participants and their submissions, the problems only if withProblems is set
+func (cfg *ApiCfg) loadContestBoard(ctx context.Context, contest database.Contest, withProblems bool) (contestBoard, error) {
+	board := contestBoard{problemIDs: []uuid.UUID{}, problems: []ContestProblemResponse{}}
+	if withProblems {
+		list, err := cfg.db.GetContestProblems(ctx, contest.ID)
+		if err != nil {
+			return contestBoard{}, fmt.Errorf("failed to retrieve problems: %v", err)
+		}
+		for _, problem := range list {
+			board.problemIDs = append(board.problemIDs, problem.ID)
+			board.problems = append(board.problems, ContestProblemToResponse(problem))
+		}
+	}
+
+	registrations, err := cfg.db.GetContestParticipants(ctx, contest.ID)
+	if err != nil {
+		return contestBoard{}, fmt.Errorf("failed to retrieve participants: %v", err)
+	}
+	for _, registration := range registrations {
+		board.participants = append(board.participants, contests.Participant{
+			UserID:   registration.UserID,
+			Username: registration.Username,
+			StartsAt: contest.StartsAt,
+		})
+	}
+
+	rows, err := cfg.db.GetContestAttempts(ctx, uuid.NullUUID{UUID: contest.ID, Valid: true})
+	if err != nil {
+		return contestBoard{}, fmt.Errorf("failed to retrieve submissions: %v", err)
+	}
+	for _, row := range rows {
+		board.attempts = append(board.attempts, attemptFromRow(row))
+	}
+	return board, nil
+}
+
+// GetContestScoreboardHandler ranks the participants. During the freeze results submitted in the last minutes
+// show as pending, admins can pass ?full=true to see the live standings
+func (cfg *ApiCfg) GetContestScoreboardHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	window := ContestWindow(contest)
+	freezeAfter := time.Duration(0)
+	if window.Frozen(now) {
+		freezeAfter = window.FreezeAfter()
+	}
+	if r.URL.Query().Get("full") == "true" {
+		user, err := cfg.AuthenticateUser(r)
+		if err != nil || !user.IsAdmin {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		freezeAfter = 0
+	}
+
+	board, err := cfg.loadContestBoard(r.Context(), contest, ContestStatus(contest, now) != "upcoming")
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	type response struct {
+		ContestID uuid.UUID                `json:"contest_id"`
+		Scoring   string                   `json:"scoring"`
+		Frozen    bool                     `json:"frozen"`
+		FrozenAt  *time.Time               `json:"frozen_at"`
+		Problems  []ContestProblemResponse `json:"problems"`
+		Rows      []contests.Row           `json:"rows"`
+	}
+	res := response{
+		ContestID: contest.ID,
+		Scoring:   contest.Scoring,
+		Frozen:    freezeAfter > 0,
+		Problems:  board.problems,
+		Rows:      contests.Standings(contests.Scoring(contest.Scoring), board.problemIDs, board.participants, board.attempts, freezeAfter),
+	}
+	if res.Frozen {
+		frozenAt := contest.StartsAt.Add(freezeAfter)
+		res.FrozenAt = &frozenAt
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// UnfreezeContestHandler lifts the scoreboard freeze for good, usually once the contest is over
+func (cfg *ApiCfg) UnfreezeContestHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized scoreboard unfreeze by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if contest.FreezeMinutes == 0 {
+		http.Error(w, "Contest has no scoreboard freeze", http.StatusBadRequest)
+		return
+	}
+	if !contest.UnfrozenAt.Valid {
+		contest, err = cfg.db.UnfreezeContest(r.Context(), database.UnfreezeContestParams{
+			ID:         contest.ID,
+			UnfrozenAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			cfg.logger.Printf("Failed to unfreeze contest: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		cfg.logger.Printf("Scoreboard of contest %v unfrozen by admin %v", contest.ID, adminUser.ID)
+	}
+
+	res, err := cfg.contestDetail(r.Context(), contest, &adminUser)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"Codium/internal/judge"
+	"Codium/internal/problems"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Submission Events
+
+===========================================
+*/
+
+const (
+	// Workers on another instance cannot notify this one, streams fall back to polling the database
+	submissionEventsPoll     = time.Second
+	submissionEventsKeepOpen = 15 * time.Second
+)
+
+// SubmissionNotifier wakes the event streams of a submission whenever its judging state changes
+type SubmissionNotifier struct {
+	mu        sync.Mutex
+	listeners map[uuid.UUID]map[chan struct{}]bool
+}
+
+func NewSubmissionNotifier() *SubmissionNotifier {
+	return &SubmissionNotifier{listeners: make(map[uuid.UUID]map[chan struct{}]bool)}
+}
+
+// Subscribe returns a channel signalled after every change to the submission and a function to stop listening
+func (n *SubmissionNotifier) Subscribe(submissionID uuid.UUID) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.listeners[submissionID] == nil {
+		n.listeners[submissionID] = make(map[chan struct{}]bool)
+	}
+	n.listeners[submissionID][ch] = true
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.listeners[submissionID], ch)
+		if len(n.listeners[submissionID]) == 0 {
+			delete(n.listeners, submissionID)
+		}
+	}
+}
+
+func (n *SubmissionNotifier) Notify(submissionID uuid.UUID) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.listeners[submissionID] {
+		// A pending signal already makes the stream reload everything
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	if err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// SubmissionEventsHandler streams the judging of a submission: status changes, the compile result,
+// every test verdict as it is recorded and a final done event, after which the stream is closed
+func (cfg *ApiCfg) SubmissionEventsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	submissionID, err := uuid.Parse(r.PathValue("submissionID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid submission ID: %v", err)
+		http.Error(w, "Invalid submission ID", http.StatusBadRequest)
+		return
+	}
+
+	submission, err := cfg.db.GetSubmissionByID(r.Context(), submissionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Submission not found: %v", submissionID)
+			http.Error(w, "Submission not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve submission: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if submission.UserID != user.ID && !user.IsAdmin {
+		cfg.logger.Printf("Submission %v events are not visible to user %v", submissionID, user.ID)
+		http.Error(w, "Submission not found", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		cfg.logger.Println("Streaming is not supported by the response writer")
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before the first read so no change can slip in between
+	changes, unsubscribe := cfg.submissionEvents.Subscribe(submissionID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	cfg.logger.Printf("Streaming events of submission %v to user %v", submissionID, user.ID)
+
+	type statusEvent struct {
+		Status string `json:"status"`
+	}
+	type compileEvent struct {
+		Status string `json:"status"`
+		Output string `json:"output,omitempty"`
+	}
+	type doneEvent struct {
+		Verdict  string                  `json:"verdict"`
+		Score    int32                   `json:"score"`
+		MaxScore int32                   `json:"max_score"`
+		Subtasks []problems.SubtaskScore `json:"subtasks"`
+		TimeMs   int64                   `json:"time_ms"`
+		MemoryKb int64                   `json:"memory_kb"`
+	}
+
+	sentStatus, sentCompile, sentTests := "", "", 0
+	poll := time.NewTicker(submissionEventsPoll)
+	defer poll.Stop()
+	keepOpen := time.NewTicker(submissionEventsKeepOpen)
+	defer keepOpen.Stop()
+
+	for {
+		submission, err = cfg.db.GetSubmissionByID(r.Context(), submissionID)
+		if err != nil {
+			cfg.logger.Printf("Failed to reload submission %v: %v", submissionID, err)
+			return
+		}
+
+		if submission.Status != sentStatus {
+			// A rejudge starts over
+			if submission.Status == "pending" || submission.Status == "compiling" {
+				sentCompile, sentTests = "", 0
+			}
+			sentStatus = submission.Status
+			err = writeEvent(w, flusher, "status", statusEvent{Status: submission.Status})
+			if err != nil {
+				return
+			}
+		}
+
+		var tests []SubmissionTestResponse
+		if submission.Status == "running" || submission.Status == "done" {
+			rows, err := cfg.db.GetSubmissionTests(r.Context(), submissionID)
+			if err != nil {
+				cfg.logger.Printf("Failed to reload tests of submission %v: %v", submissionID, err)
+				return
+			}
+			tests = SubmissionToResponse(submission, rows, nil).Tests
+		}
+
+		compile := ""
+		switch {
+		case submission.Status == "compiling":
+			compile = "compiling"
+		case submission.Status == "done" && submission.Verdict == string(judge.VerdictCompilationError):
+			compile = "error"
+		case submission.Status == "running" || len(tests) > 0:
+			compile = "ok"
+		}
+		if compile != "" && compile != sentCompile {
+			sentCompile = compile
+			event := compileEvent{Status: compile}
+			if compile == "error" {
+				event.Output = submission.CompileOutput
+			}
+			err = writeEvent(w, flusher, "compile", event)
+			if err != nil {
+				return
+			}
+		}
+
+		for _, test := range tests {
+			if int(test.Position) <= sentTests {
+				continue
+			}
+			sentTests = int(test.Position)
+			err = writeEvent(w, flusher, "test", test)
+			if err != nil {
+				return
+			}
+		}
+
+		if submission.Status == "done" {
+			subtasks, err := cfg.db.GetSubmissionSubtasks(r.Context(), submissionID)
+			if err != nil {
+				cfg.logger.Printf("Failed to reload subtasks of submission %v: %v", submissionID, err)
+				return
+			}
+			err = writeEvent(w, flusher, "done", doneEvent{
+				Verdict:  submission.Verdict,
+				Score:    submission.Score,
+				MaxScore: submission.MaxScore,
+				Subtasks: SubmissionToResponse(submission, nil, subtasks).Subtasks,
+				TimeMs:   submission.TimeMs,
+				MemoryKb: submission.MemoryKb,
+			})
+			if err != nil {
+				cfg.logger.Printf("Failed to write final event of submission %v: %v", submissionID, err)
+			}
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+		case <-poll.C:
+		case <-keepOpen.C:
+			// Comments keep proxies from closing an idle stream
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -3,6 +3,9 @@ package main
 import (
 	"Codium/internal/auth"
 	"Codium/internal/database"
+	"Codium/internal/lessons"
+	"Codium/internal/markdown"
+	"Codium/internal/text"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -11,10 +14,14 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -81,8 +88,8 @@ func (cfg *ApiCfg) ResetAll() error {
 	return nil
 }
 
-// Upload local upload
-func (cfg *ApiCfg) Upload(multipart multipart.File, location string, fileType string, user database.User, fileExtensions string) (string, string, error) {
+// Upload local upload, form carries the extra fields some locations need (e.g. lesson grade, ID and title)
+func (cfg *ApiCfg) Upload(multipart multipart.File, location string, fileType string, user database.User, fileExtensions string, form url.Values) (string, string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get current working directory: %v", err)
@@ -93,6 +100,8 @@ func (cfg *ApiCfg) Upload(multipart multipart.File, location string, fileType st
 
 	var filePath string
 	fileId := uuid.New()
+	fileName := fileId.String() + "." + fileExtensions
+	var fileSize int64
 
 	switch location {
 	case "images":
@@ -119,7 +128,7 @@ func (cfg *ApiCfg) Upload(multipart multipart.File, location string, fileType st
 		}(dst)
 
 		//copy the uploaded file to the destination file
-		_, err = io.Copy(dst, multipart)
+		fileSize, err = io.Copy(dst, multipart)
 		if err != nil {
 			return "", "", fmt.Errorf("failed to save file: %v", err)
 		}
@@ -129,27 +138,149 @@ func (cfg *ApiCfg) Upload(multipart multipart.File, location string, fileType st
 		cfg.logger.Printf("Image accessible at path: %s", filePath)
 
 	case "lessons":
-		// Check if file is markdown
-		if strings.HasPrefix(fileType, "markdown/") == false {
-			return "", "", fmt.Errorf("invalid file type for lessons: %v", fileType)
-		}
 		// Lessons are privileged uploads only
-		if !user.IsAdmin {
+		if !user.IsAdmin && !user.IsTeacher {
 			return "", "", fmt.Errorf("unauthorized upload attempt to lessons")
 		}
+		// Check if file is markdown, browsers detect it as plain text
+		if fileExtensions != "md" || strings.HasPrefix(fileType, "text/plain") == false {
+			return "", "", fmt.Errorf("invalid file type for lessons: %v", fileType)
+		}
+
+		body, err := io.ReadAll(multipart)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read lesson: %v", err)
+		}
+		if len(body) == 0 || !utf8.Valid(body) {
+			return "", "", fmt.Errorf("lesson must be a non-empty UTF-8 markdown file")
+		}
+
+		meta, markdownBody, err := lessons.ParseContent(body)
+		if err != nil {
+			return "", "", err
+		}
+
+		// Form fields take precedence over the front matter
+		if form.Get("grade") != "" {
+			meta.Grade = strings.TrimSpace(form.Get("grade"))
+		}
+		if form.Get("lesson_id") != "" {
+			lessonID, err := strconv.Atoi(form.Get("lesson_id"))
+			if err != nil {
+				return "", "", fmt.Errorf("invalid lesson ID: %q", form.Get("lesson_id"))
+			}
+			meta.ID = int32(lessonID)
+		}
+		if form.Get("title") != "" {
+			meta.Title = strings.TrimSpace(form.Get("title"))
+		}
+
+		grade := meta.Grade
+		if !lessonGradeRegex.MatchString(grade) {
+			return "", "", fmt.Errorf("invalid lesson grade: %q", grade)
+		}
+		lessonID := meta.ID
+		if lessonID <= 0 {
+			return "", "", fmt.Errorf("invalid lesson ID: %v", lessonID)
+		}
+		title := meta.Title
+		if len(title) == 0 || len(title) > 100 || strings.ContainsAny(title, "/\\[]") {
+			return "", "", fmt.Errorf("invalid lesson title: %q", title)
+		}
+
+		// Keep the watcher from recording the new file as an anonymous revision
+		cfg.lessonScanMu.Lock()
+		defer cfg.lessonScanMu.Unlock()
 
-		// Handle lesson upload
-		return "", "", fmt.Errorf("lesson uploads are not yet implemented")
+		// Teachers may only change their own lessons, and only before they are published
+		previous, err := cfg.db.GetLessonByID(context.Background(), lessonID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return "", "", fmt.Errorf("failed to retrieve lesson: %v", err)
+		}
+		exists := err == nil
+		if exists && !user.IsAdmin {
+			if !previous.AuthorID.Valid || previous.AuthorID.UUID != user.ID {
+				return "", "", fmt.Errorf("unauthorized upload attempt to lesson %v", lessonID)
+			}
+			if previous.Status != string(lessons.StatusDraft) {
+				return "", "", fmt.Errorf("lesson %v is %v, only drafts can be replaced", lessonID, previous.Status)
+			}
+		}
+
+		lessonDir := appDir + "Lectii/" + grade
+		err = os.MkdirAll(lessonDir, os.ModePerm)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create lesson directory: %v", err)
+		}
+
+		// Lesson files follow the "<id>_[<title>].md" naming convention
+		fileName = fmt.Sprintf("%d_[%s].md", lessonID, title)
+		filePath = lessonDir + "/" + fileName
+		fileSize = int64(len(body))
+
+		// The lesson is written aside and only moved in place once the catalog has it, the watcher would
+		// otherwise publish a file the catalog refused
+		upload, err := os.CreateTemp(lessonDir, ".upload-*")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to save lesson: %v", err)
+		}
+		defer os.Remove(upload.Name())
+		_, err = upload.Write(body)
+		if closeErr := upload.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Chmod(upload.Name(), 0644)
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to save lesson: %v", err)
+		}
+		relativePath := strings.TrimPrefix(filePath, cwd+"/")
+
+		// New lessons start as drafts of the uploader, existing ones keep their status and author
+		_, err = cfg.StoreLesson(context.Background(), meta, relativePath, markdownBody, lessons.StatusDraft, uuid.NullUUID{UUID: user.ID, Valid: true})
+		if err != nil {
+			return "", "", fmt.Errorf("failed to record lesson in catalog: %v", err)
+		}
+		err = os.Rename(upload.Name(), filePath)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to save lesson: %v", err)
+		}
+		filePath = relativePath
+		err = cfg.RecordLessonRevision(context.Background(), lessonID, uuid.NullUUID{UUID: user.ID, Valid: true}, body)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to record lesson revision: %v", err)
+		}
+
+		// Remove the previous file if the lesson was renamed or moved to another grade, once the catalog points to the new one
+		if exists && previous.Filepath != filePath {
+			err = os.Remove(cwd + "/" + previous.Filepath)
+			if err != nil && !os.IsNotExist(err) {
+				cfg.logger.Printf("Failed to remove previous lesson file: %v", err)
+			}
+		}
+		// A lesson file has one row, replacing it replaces the row of the previous upload
+		replaced := []string{filePath}
+		if exists {
+			replaced = append(replaced, previous.Filepath)
+		}
+		for _, path := range replaced {
+			err = cfg.db.DeleteFilesByPath(context.Background(), path)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to remove previous lesson record: %v", err)
+			}
+		}
+		cfg.logger.Printf("Lesson %d uploaded successfully: %s", lessonID, filePath)
 	default:
 		return "", "", fmt.Errorf("invalid location: %v", location)
 	}
 
 	_, err = cfg.db.CreateFile(context.Background(), database.CreateFileParams{
 		ID:       fileId,
-		UserID:   user.ID,
-		Filename: fileId.String() + "." + fileExtensions,
+		UserID:   uuid.NullUUID{UUID: user.ID, Valid: true},
+		Filename: fileName,
 		Filepath: filePath,
-		Filesize: 0, // TODO: get actual file size
+		Filesize: fileSize,
 		UploadedAt: sql.NullTime{
 			Time:  time.Now(),
 			Valid: true,
@@ -164,11 +295,7 @@ func (cfg *ApiCfg) Upload(multipart multipart.File, location string, fileType st
 }
 
 func (cfg *ApiCfg) DeleteUser(userID uuid.UUID) error {
-	uploadedFiles, err := cfg.db.GetFilesByUserID(context.Background(), database.GetFilesByUserIDParams{
-		UserID: userID,
-		Limit:  2000,
-		Offset: 0,
-	})
+	uploadedFiles, err := cfg.db.GetPersonalFilesByUserID(context.Background(), uuid.NullUUID{UUID: userID, Valid: true})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			cfg.logger.Printf("No files found for user: %v", userID)
@@ -183,13 +310,18 @@ func (cfg *ApiCfg) DeleteUser(userID uuid.UUID) error {
 	}
 	cfg.logger.Printf("Current working directory: %v", cwd)
 
-	// Delete files from filesystem
+	// Delete files from filesystem, a file that is already gone is no reason to keep the user.
+	// The files of problem tests and lessons stay, their rows lose the uploader along with the user
 	for _, file := range uploadedFiles {
 		err = os.Remove(cwd + "/" + file.Filepath)
-		if err != nil {
+		if err != nil && !os.IsNotExist(err) {
 			cfg.logger.Printf("Failed to delete file from filesystem: %v", err)
 			return fmt.Errorf("failed to delete file from filesystem: %v", err)
 		}
+		err = cfg.db.DeleteFile(context.Background(), file.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete file record: %v", err)
+		}
 	}
 
 	err = cfg.db.DeleteUserById(context.Background(), userID)
@@ -213,6 +345,29 @@ func (cfg *ApiCfg) ListUsers() ([]database.User, error) {
 	return users, nil
 }
 
+// StoreLesson creates or updates the catalog entry of a lesson stored at filePath.
+// status and authorID only apply to new lessons, updates never change them.
+func (cfg *ApiCfg) StoreLesson(ctx context.Context, meta lessons.Metadata, filePath string, body []byte, status lessons.Status, authorID uuid.NullUUID) (database.Lesson, error) {
+	tags := meta.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return cfg.db.UpsertLesson(ctx, database.UpsertLessonParams{
+		ID:               meta.ID,
+		Grade:            meta.Grade,
+		Title:            meta.Title,
+		Slug:             LessonSlug(meta.ID, meta.Title),
+		Filepath:         filePath,
+		Body:             string(body),
+		Tags:             tags,
+		Author:           meta.Author,
+		EstimatedMinutes: meta.EstimatedMinutes,
+		Status:           string(status),
+		AuthorID:         authorID,
+		CreatedAt:        time.Now(),
+	})
+}
+
 /*
 ===========================================
 
@@ -301,6 +456,89 @@ func (cfg *ApiCfg) AuthenticateUser(r *http.Request) (database.User, error) {
 	return targetUser, nil
 }
 
+// CanViewLesson reports whether the requester may see a lesson, anything but published lessons is limited to its author and admins
+func (cfg *ApiCfg) CanViewLesson(r *http.Request, lesson database.Lesson) bool {
+	if lesson.Status == string(lessons.StatusPublished) {
+		return true
+	}
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		return false
+	}
+	return user.IsAdmin || (lesson.AuthorID.Valid && lesson.AuthorID.UUID == user.ID)
+}
+
+type LessonResponse struct {
+	ID               int32         `json:"id"`
+	Grade            string        `json:"grade"`
+	Title            string        `json:"title"`
+	Slug             string        `json:"slug"`
+	Tags             []string      `json:"tags"`
+	Author           string        `json:"author"`
+	EstimatedMinutes int32         `json:"estimated_minutes"`
+	Status           string        `json:"status"`
+	AuthorID         uuid.NullUUID `json:"author_id"`
+	Body             string        `json:"body,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+func LessonToResponse(lesson database.Lesson, withBody bool) LessonResponse {
+	res := LessonResponse{
+		ID:               lesson.ID,
+		Grade:            lesson.Grade,
+		Title:            lesson.Title,
+		Slug:             lesson.Slug,
+		Tags:             lesson.Tags,
+		Author:           lesson.Author,
+		EstimatedMinutes: lesson.EstimatedMinutes,
+		Status:           lesson.Status,
+		AuthorID:         lesson.AuthorID,
+		CreatedAt:        lesson.CreatedAt,
+		UpdatedAt:        lesson.UpdatedAt,
+	}
+	if withBody {
+		res.Body = lesson.Body
+	}
+	return res
+}
+
+var lessonGradeRegex = regexp.MustCompile(`^Clasa a [IVX]+-a$`)
+
+// LessonSlug builds a URL friendly identifier such as "123-introducere-in-struct"
+func LessonSlug(id int32, title string) string {
+	title = text.StripDiacritics(strings.ToLower(title))
+	slug := strconv.Itoa(int(id))
+	dash := true
+	for _, c := range title {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			if dash {
+				slug += "-"
+				dash = false
+			}
+			slug += string(c)
+		} else {
+			dash = true
+		}
+	}
+	return slug
+}
+
+func (cfg *ApiCfg) RespondWithJSON(w http.ResponseWriter, status int, payload interface{}) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		cfg.logger.Printf("Failed to marshal response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(jsonData)
+	if err != nil {
+		cfg.logger.Printf("Failed to write response: %v", err)
+	}
+}
+
 /*
 ===========================================
 
@@ -842,7 +1080,7 @@ func (cfg *ApiCfg) UploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	uploadPath, uploadID, err := cfg.Upload(file, location, fileType, targetUser, handler.Filename[strings.LastIndex(handler.Filename, ".")+1:])
+	uploadPath, uploadID, err := cfg.Upload(file, location, fileType, targetUser, handler.Filename[strings.LastIndex(handler.Filename, ".")+1:], r.Form)
 	if err != nil {
 		cfg.logger.Printf("Failed to upload file: %v", err)
 		http.Error(w, "Failed to upload file ", http.StatusInternalServerError)
@@ -895,6 +1133,13 @@ func (cfg *ApiCfg) GetFileHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only files under App/ are public, hidden test data is stored elsewhere
+	if !strings.HasPrefix(filepath.ToSlash(filepath.Clean(file.Filepath)), "App/") {
+		cfg.logger.Printf("Refusing to serve private file: %v", fileID)
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
 	// Serve the file
 	http.ServeFile(w, r, file.Filepath)
 }
@@ -1238,3 +1483,146 @@ func (cfg *ApiCfg) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (cfg *ApiCfg) GetLessonsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	grade := q.Get("grade")
+	status := lessons.StatusPublished
+	if q.Get("status") != "" {
+		status = lessons.Status(q.Get("status"))
+	}
+	cfg.logger.Printf("Received get lessons request for grade: %q, status: %q", grade, status)
+
+	// Only published lessons are public, other states require an account
+	var requester database.User
+	if status != lessons.StatusPublished {
+		var err error
+		requester, err = cfg.AuthenticateUser(r)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var found []database.Lesson
+	var err error
+	if grade != "" {
+		found, err = cfg.db.GetLessonsByGradeAndStatus(r.Context(), database.GetLessonsByGradeAndStatusParams{
+			Grade:  grade,
+			Status: string(status),
+		})
+	} else {
+		found, err = cfg.db.GetLessonsByStatus(r.Context(), string(status))
+	}
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve lessons: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]LessonResponse, 0, len(found))
+	for _, lesson := range found {
+		if status != lessons.StatusPublished && !requester.IsAdmin && (!lesson.AuthorID.Valid || lesson.AuthorID.UUID != requester.ID) {
+			continue
+		}
+		res = append(res, LessonToResponse(lesson, false))
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) GetLessonHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	cfg.logger.Printf("Received get lesson request for lesson ID: %v", lessonID)
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !cfg.CanViewLesson(r, lesson) {
+		cfg.logger.Printf("Lesson %v is not visible to the requester", lessonID)
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		return
+	}
+
+	cfg.RespondWithJSON(w, http.StatusOK, LessonToResponse(lesson, true))
+}
+
+func (cfg *ApiCfg) GetLessonHtmlHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	cfg.logger.Printf("Received rendered lesson request for lesson ID: %v", lessonID)
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !cfg.CanViewLesson(r, lesson) {
+		cfg.logger.Printf("Lesson %v is not visible to the requester", lessonID)
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		return
+	}
+
+	rendered, err := cfg.renderer.Render([]byte(lesson.Body))
+	if err != nil {
+		cfg.logger.Printf("Failed to render lesson %v: %v", lessonID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	type response struct {
+		LessonResponse
+		markdown.Rendered
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, response{
+		LessonResponse: LessonToResponse(lesson, false),
+		Rendered:       rendered,
+	})
+}
@@ -0,0 +1,149 @@
+package contests
+
+import (
+	"Codium/internal/problems"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Scoring string
+
+const (
+	// ScoringICPC ranks by problems solved, ties broken by penalty time
+	ScoringICPC Scoring = "icpc"
+	// ScoringIOI ranks by the sum of the best score on each problem
+	ScoringIOI Scoring = "ioi"
+)
+
+const (
+	MaxProblems   = 26
+	MaxDuration   = 14 * 24 * time.Hour
+	MaxTitleRunes = 200
+	// PenaltyMinutes is added for each rejected attempt before a problem is solved
+	PenaltyMinutes = 20
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Spec is everything an admin provides when creating or replacing a contest
+type Spec struct {
+	Slug        string    `json:"slug"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Scoring     Scoring   `json:"scoring"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	// FreezeMinutes hides the results of the last minutes of the contest from the scoreboard
+	FreezeMinutes int32 `json:"freeze_minutes"`
+	// Problems are problem IDs or slugs, labelled A, B, C... in this order
+	Problems []string `json:"problems"`
+}
+
+func ValidScoring(s Scoring) bool {
+	return s == ScoringICPC || s == ScoringIOI
+}
+
+// Label names the problem at index i of a contest
+func Label(i int) string {
+	return string(rune('A' + i))
+}
+
+// Normalize fills in the defaults of a spec and checks that it describes a usable contest
+func (s *Spec) Normalize() error {
+	s.Title = strings.TrimSpace(s.Title)
+	if s.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len([]rune(s.Title)) > MaxTitleRunes {
+		return fmt.Errorf("title is longer than %v characters", MaxTitleRunes)
+	}
+
+	s.Slug = strings.TrimSpace(s.Slug)
+	if s.Slug == "" {
+		s.Slug = problems.Slug(s.Title)
+	}
+	if !slugRegex.MatchString(s.Slug) {
+		return fmt.Errorf("invalid slug %q", s.Slug)
+	}
+
+	if s.Scoring == "" {
+		s.Scoring = ScoringICPC
+	}
+	if !ValidScoring(s.Scoring) {
+		return fmt.Errorf("unknown scoring %q", s.Scoring)
+	}
+
+	if s.StartsAt.IsZero() || s.EndsAt.IsZero() {
+		return fmt.Errorf("start and end times are required")
+	}
+	duration := s.EndsAt.Sub(s.StartsAt)
+	if duration <= 0 {
+		return fmt.Errorf("contest must end after it starts")
+	}
+	if duration > MaxDuration {
+		return fmt.Errorf("contest may last at most %v days", int(MaxDuration.Hours()/24))
+	}
+	// A freeze as long as the contest would start at zero, which FreezeAfter reads as no freeze at all
+	if s.FreezeMinutes < 0 || time.Duration(s.FreezeMinutes)*time.Minute >= duration {
+		return fmt.Errorf("freeze must be at least 0 and shorter than the %v minutes of the contest", int(duration.Minutes()))
+	}
+
+	if len(s.Problems) == 0 || len(s.Problems) > MaxProblems {
+		return fmt.Errorf("a contest has between 1 and %v problems", MaxProblems)
+	}
+	seen := make(map[string]bool)
+	for i, key := range s.Problems {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return fmt.Errorf("problem %v is empty", Label(i))
+		}
+		if seen[key] {
+			return fmt.Errorf("problem %v is listed twice", key)
+		}
+		seen[key] = true
+		s.Problems[i] = key
+	}
+	return nil
+}
+
+// Window is the schedule of a contest
+type Window struct {
+	StartsAt      time.Time
+	EndsAt        time.Time
+	FreezeMinutes int32
+	// Unfrozen is set once an admin lifts the freeze
+	Unfrozen bool
+}
+
+// Running reports whether submissions are accepted at now
+func (w Window) Running(now time.Time) bool {
+	return !now.Before(w.StartsAt) && now.Before(w.EndsAt)
+}
+
+// FreezeAfter is how long after the start the scoreboard stops updating, zero if it never freezes
+func (w Window) FreezeAfter() time.Duration {
+	if w.FreezeMinutes == 0 || w.Unfrozen {
+		return 0
+	}
+	return w.Duration() - time.Duration(w.FreezeMinutes)*time.Minute
+}
+
+// Frozen reports whether the scoreboard hides new results at now
+func (w Window) Frozen(now time.Time) bool {
+	after := w.FreezeAfter()
+	return after > 0 && !now.Before(w.StartsAt.Add(after))
+}
+
+// Duration is the length of the contest
+func (w Window) Duration() time.Duration {
+	return w.EndsAt.Sub(w.StartsAt)
+}
+
+// Virtual is the personal window of a participant who starts the contest later, the freeze moves along with it
+func (w Window) Virtual(startedAt time.Time) Window {
+	w.EndsAt = startedAt.Add(w.Duration())
+	w.StartsAt = startedAt
+	return w
+}
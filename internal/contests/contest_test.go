@@ -0,0 +1,72 @@
+package contests
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	valid := func() Spec {
+		return Spec{Title: "Runda de antrenament 3", StartsAt: start, EndsAt: start.Add(2 * time.Hour), FreezeMinutes: 30, Problems: []string{"suma", " produs "}}
+	}
+
+	spec := valid()
+	if err := spec.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Slug != "runda-de-antrenament-3" || spec.Scoring != ScoringICPC || spec.Problems[1] != "produs" {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+
+	invalid := map[string]func(*Spec){
+		"no title":          func(s *Spec) { s.Title = " " },
+		"bad scoring":       func(s *Spec) { s.Scoring = "codeforces" },
+		"ends before start": func(s *Spec) { s.EndsAt = s.StartsAt.Add(-time.Minute) },
+		"too long":          func(s *Spec) { s.EndsAt = s.StartsAt.Add(MaxDuration + time.Hour) },
+		"long freeze":       func(s *Spec) { s.FreezeMinutes = 121 },
+		"whole freeze":      func(s *Spec) { s.FreezeMinutes = 120 },
+		"no problems":       func(s *Spec) { s.Problems = nil },
+		"duplicate problem": func(s *Spec) { s.Problems = []string{"suma", "suma"} },
+	}
+	for name, change := range invalid {
+		spec := valid()
+		change(&spec)
+		if err := spec.Normalize(); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	w := Window{StartsAt: start, EndsAt: start.Add(2 * time.Hour), FreezeMinutes: 30}
+
+	if w.Running(start.Add(-time.Second)) || !w.Running(start) || w.Running(w.EndsAt) {
+		t.Error("contest should run from its start up to, not including, its end")
+	}
+	if w.FreezeAfter() != 90*time.Minute {
+		t.Errorf("FreezeAfter = %v", w.FreezeAfter())
+	}
+	if w.Frozen(start.Add(89*time.Minute)) || !w.Frozen(start.Add(90*time.Minute)) || !w.Frozen(w.EndsAt.Add(time.Hour)) {
+		t.Error("scoreboard should freeze 30 minutes before the end and stay frozen")
+	}
+	w.Unfrozen = true
+	if w.Frozen(w.EndsAt) {
+		t.Error("lifting the freeze should show everything")
+	}
+}
+
+func TestVirtualWindow(t *testing.T) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	w := Window{StartsAt: start, EndsAt: start.Add(2 * time.Hour), FreezeMinutes: 30}
+	later := start.Add(72 * time.Hour)
+
+	v := w.Virtual(later)
+	if !v.StartsAt.Equal(later) || v.Duration() != w.Duration() || !v.Running(later.Add(time.Hour)) || v.Running(later.Add(2*time.Hour)) {
+		t.Errorf("unexpected virtual window %+v", v)
+	}
+	if v.FreezeAfter() != w.FreezeAfter() || !v.Frozen(later.Add(100*time.Minute)) {
+		t.Error("the freeze should move with the virtual window")
+	}
+}
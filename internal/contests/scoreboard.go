@@ -0,0 +1,143 @@
+package contests
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Verdicts that never count as an attempt, the contestant is not at fault or nothing ran
+var ignoredVerdicts = map[string]bool{"CE": true, "IE": true}
+
+// Participant is one row of the scoreboard, StartsAt is when their contest time began
+type Participant struct {
+	UserID   uuid.UUID
+	Username string
+	StartsAt time.Time
+	// Virtual participants took the contest after it ended, the freeze does not hide their own results
+	Virtual bool
+}
+
+// Attempt is one submission of a participant
+type Attempt struct {
+	UserID    uuid.UUID
+	ProblemID uuid.UUID
+	// Done is false while the submission is still being judged
+	Done      bool
+	Verdict   string
+	Score     int32
+	CreatedAt time.Time
+}
+
+type Cell struct {
+	Label  string `json:"label"`
+	Solved bool   `json:"solved"`
+	// Attempts counts the submissions that were rejected, before the accepted one in ICPC mode
+	Attempts int `json:"attempts"`
+	// Minutes is when the problem was solved in ICPC mode, counted from the start of the participant
+	Minutes int64 `json:"minutes,omitempty"`
+	Score   int32 `json:"score"`
+	// Pending counts the submissions hidden by the freeze or still being judged
+	Pending int `json:"pending"`
+}
+
+type Row struct {
+	Rank     int       `json:"rank"`
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Solved   int       `json:"solved"`
+	Penalty  int64     `json:"penalty"`
+	Score    int64     `json:"score"`
+	Virtual  bool      `json:"virtual,omitempty"`
+	Cells    []Cell    `json:"cells"`
+}
+
+// Standings ranks participants by their attempts on problems, given in label order. Attempts made
+// freezeAfter or later into a participant's contest count as pending, zero shows everything
+func Standings(scoring Scoring, problemIDs []uuid.UUID, participants []Participant, attempts []Attempt, freezeAfter time.Duration) []Row {
+	column := make(map[uuid.UUID]int, len(problemIDs))
+	for i, id := range problemIDs {
+		column[id] = i
+	}
+
+	rows := make([]Row, 0, len(participants))
+	index := make(map[uuid.UUID]int, len(participants))
+	for _, p := range participants {
+		index[p.UserID] = len(rows)
+		row := Row{UserID: p.UserID, Username: p.Username, Virtual: p.Virtual, Cells: make([]Cell, len(problemIDs))}
+		for i := range row.Cells {
+			row.Cells[i].Label = Label(i)
+		}
+		rows = append(rows, row)
+	}
+
+	sorted := slices.Clone(attempts)
+	slices.SortStableFunc(sorted, func(a, b Attempt) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	for _, a := range sorted {
+		r, ok := index[a.UserID]
+		c, known := column[a.ProblemID]
+		if !ok || !known {
+			continue
+		}
+		cell := &rows[r].Cells[c]
+		elapsed := a.CreatedAt.Sub(participants[r].StartsAt)
+		switch {
+		case scoring == ScoringICPC && cell.Solved:
+			// Later submissions on a solved problem change nothing
+		case !a.Done || (freezeAfter > 0 && !participants[r].Virtual && elapsed >= freezeAfter):
+			cell.Pending++
+		case ignoredVerdicts[a.Verdict]:
+		case scoring == ScoringIOI:
+			cell.Score = max(cell.Score, a.Score)
+			if a.Verdict == "AC" {
+				cell.Solved = true
+			} else {
+				cell.Attempts++
+			}
+		case a.Verdict == "AC":
+			cell.Solved = true
+			cell.Minutes = int64(max(elapsed, 0) / time.Minute)
+		default:
+			cell.Attempts++
+		}
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		for _, cell := range row.Cells {
+			row.Score += int64(cell.Score)
+			if cell.Solved {
+				row.Solved++
+				if scoring == ScoringICPC {
+					row.Penalty += cell.Minutes + int64(cell.Attempts)*PenaltyMinutes
+				}
+			}
+		}
+	}
+
+	better := func(a, b Row) int {
+		if scoring == ScoringIOI {
+			return cmp.Compare(b.Score, a.Score)
+		}
+		if a.Solved != b.Solved {
+			return cmp.Compare(b.Solved, a.Solved)
+		}
+		return cmp.Compare(a.Penalty, b.Penalty)
+	}
+	slices.SortStableFunc(rows, func(a, b Row) int {
+		if c := better(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.Username), strings.ToLower(b.Username))
+	})
+	for i := range rows {
+		rows[i].Rank = i + 1
+		if i > 0 && better(rows[i-1], rows[i]) == 0 {
+			rows[i].Rank = rows[i-1].Rank
+		}
+	}
+	return rows
+}
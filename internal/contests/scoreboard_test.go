@@ -0,0 +1,111 @@
+package contests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	start    = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	problemA = uuid.New()
+	problemB = uuid.New()
+	ana      = Participant{UserID: uuid.New(), Username: "ana", StartsAt: start}
+	bogdan   = Participant{UserID: uuid.New(), Username: "bogdan", StartsAt: start}
+	carmen   = Participant{UserID: uuid.New(), Username: "Carmen", StartsAt: start}
+)
+
+func attempt(p Participant, problem uuid.UUID, minute int, verdict string, score int32) Attempt {
+	return Attempt{UserID: p.UserID, ProblemID: problem, Done: true, Verdict: verdict, Score: score, CreatedAt: start.Add(time.Duration(minute) * time.Minute)}
+}
+
+func TestStandingsICPC(t *testing.T) {
+	attempts := []Attempt{
+		attempt(ana, problemA, 10, "WA", 0),
+		attempt(ana, problemA, 15, "AC", 100),
+		attempt(ana, problemA, 20, "WA", 0),
+		attempt(ana, problemB, 50, "CE", 0),
+		attempt(ana, problemB, 60, "AC", 100),
+		attempt(bogdan, problemB, 5, "AC", 100),
+		attempt(bogdan, problemA, 100, "AC", 100),
+		attempt(carmen, problemA, 30, "TLE", 0),
+		{UserID: carmen.UserID, ProblemID: problemB, CreatedAt: start.Add(40 * time.Minute)},
+	}
+	rows := Standings(ScoringICPC, []uuid.UUID{problemA, problemB}, []Participant{carmen, bogdan, ana}, attempts, 0)
+
+	// ana: A at 15 with one rejection (35), B at 60 with the compile error ignored (60)
+	// bogdan: B at 5, A at 100 = 105
+	if rows[0].Username != "ana" || rows[0].Solved != 2 || rows[0].Penalty != 95 || rows[0].Rank != 1 {
+		t.Errorf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].Username != "bogdan" || rows[1].Penalty != 105 || rows[1].Rank != 2 {
+		t.Errorf("unexpected second row %+v", rows[1])
+	}
+	carmenRow := rows[2]
+	if carmenRow.Solved != 0 || carmenRow.Cells[0].Attempts != 1 || carmenRow.Cells[1].Pending != 1 {
+		t.Errorf("unexpected last row %+v", carmenRow)
+	}
+	if cell := rows[0].Cells[0]; cell.Label != "A" || cell.Attempts != 1 || cell.Minutes != 15 {
+		t.Errorf("unexpected cell %+v", cell)
+	}
+}
+
+func TestStandingsFrozen(t *testing.T) {
+	attempts := []Attempt{
+		attempt(ana, problemA, 10, "AC", 100),
+		attempt(bogdan, problemA, 5, "WA", 0),
+		attempt(bogdan, problemA, 95, "AC", 100),
+	}
+	rows := Standings(ScoringICPC, []uuid.UUID{problemA}, []Participant{ana, bogdan}, attempts, 90*time.Minute)
+	if rows[1].Username != "bogdan" || rows[1].Solved != 0 || rows[1].Cells[0].Attempts != 1 || rows[1].Cells[0].Pending != 1 {
+		t.Errorf("frozen attempt should be pending: %+v", rows[1])
+	}
+
+	rows = Standings(ScoringICPC, []uuid.UUID{problemA}, []Participant{ana, bogdan}, attempts, 0)
+	if rows[0].Solved != 1 || rows[1].Solved != 1 || rows[1].Penalty != 115 {
+		t.Errorf("unfrozen board should count everything: %+v", rows)
+	}
+}
+
+func TestStandingsIOI(t *testing.T) {
+	attempts := []Attempt{
+		attempt(ana, problemA, 10, "WA", 40),
+		attempt(ana, problemA, 20, "WA", 30),
+		attempt(ana, problemB, 30, "AC", 100),
+		attempt(bogdan, problemA, 5, "AC", 100),
+		attempt(bogdan, problemB, 50, "WA", 40),
+		attempt(carmen, problemA, 50, "TLE", 20),
+	}
+	rows := Standings(ScoringIOI, []uuid.UUID{problemA, problemB}, []Participant{carmen, bogdan, ana}, attempts, 0)
+
+	// ana and bogdan tie on 140 points and share the first place
+	if rows[0].Score != 140 || rows[1].Score != 140 || rows[0].Rank != 1 || rows[1].Rank != 1 || rows[0].Username != "ana" {
+		t.Errorf("unexpected leaders %+v %+v", rows[0], rows[1])
+	}
+	if rows[0].Cells[0].Score != 40 || rows[0].Cells[0].Attempts != 2 {
+		t.Errorf("best score should be kept: %+v", rows[0].Cells[0])
+	}
+	if rows[2].Rank != 3 || rows[2].Score != 20 {
+		t.Errorf("unexpected last row %+v", rows[2])
+	}
+}
+
+func TestStandingsVirtual(t *testing.T) {
+	// dana takes the contest two days later, her attempts are timed from her own start
+	dana := Participant{UserID: uuid.New(), Username: "dana", StartsAt: start.Add(48 * time.Hour), Virtual: true}
+	attempts := []Attempt{
+		attempt(ana, problemA, 30, "AC", 100),
+		attempt(dana, problemA, 48*60+10, "AC", 100),
+		attempt(dana, problemB, 48*60+100, "AC", 100),
+		attempt(bogdan, problemB, 100, "AC", 100),
+	}
+	rows := Standings(ScoringICPC, []uuid.UUID{problemA, problemB}, []Participant{ana, bogdan, dana}, attempts, 90*time.Minute)
+
+	if rows[0].Username != "dana" || !rows[0].Virtual || rows[0].Solved != 2 || rows[0].Penalty != 110 {
+		t.Errorf("virtual participant should see all her results: %+v", rows[0])
+	}
+	if rows[1].Username != "ana" || rows[2].Username != "bogdan" || rows[2].Cells[1].Pending != 1 {
+		t.Errorf("official results after the freeze should stay hidden: %+v", rows[1:])
+	}
+}
@@ -0,0 +1,605 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: contests.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addContestProblem = `-- name: AddContestProblem :exec
+INSERT INTO contest_problems (contest_id, problem_id, label, position)
+VALUES ($1, $2, $3, $4)
+`
+
+type AddContestProblemParams struct {
+	ContestID uuid.UUID
+	ProblemID uuid.UUID
+	Label     string
+	Position  int32
+}
+
+func (q *Queries) AddContestProblem(ctx context.Context, arg AddContestProblemParams) error {
+	_, err := q.db.ExecContext(ctx, addContestProblem,
+		arg.ContestID,
+		arg.ProblemID,
+		arg.Label,
+		arg.Position,
+	)
+	return err
+}
+
+const createContest = `-- name: CreateContest :one
+INSERT INTO contests (id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, author_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+RETURNING id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, unfrozen_at, author_id, created_at, updated_at
+`
+
+type CreateContestParams struct {
+	ID            uuid.UUID
+	Slug          string
+	Title         string
+	Description   string
+	Scoring       string
+	StartsAt      time.Time
+	EndsAt        time.Time
+	FreezeMinutes int32
+	AuthorID      uuid.NullUUID
+	CreatedAt     time.Time
+}
+
+func (q *Queries) CreateContest(ctx context.Context, arg CreateContestParams) (Contest, error) {
+	row := q.db.QueryRowContext(ctx, createContest,
+		arg.ID,
+		arg.Slug,
+		arg.Title,
+		arg.Description,
+		arg.Scoring,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.FreezeMinutes,
+		arg.AuthorID,
+		arg.CreatedAt,
+	)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.Scoring,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.FreezeMinutes,
+		&i.UnfrozenAt,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteContest = `-- name: DeleteContest :exec
+DELETE FROM contests
+WHERE id = $1
+`
+
+func (q *Queries) DeleteContest(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteContest, id)
+	return err
+}
+
+const deleteContestProblems = `-- name: DeleteContestProblems :exec
+DELETE FROM contest_problems
+WHERE contest_id = $1
+`
+
+func (q *Queries) DeleteContestProblems(ctx context.Context, contestID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteContestProblems, contestID)
+	return err
+}
+
+const getContestAttempts = `-- name: GetContestAttempts :many
+SELECT user_id, problem_id, status, verdict, score, created_at
+FROM submissions
+WHERE contest_id = $1 AND NOT virtual
+ORDER BY created_at
+`
+
+type GetContestAttemptsRow struct {
+	UserID    uuid.UUID
+	ProblemID uuid.UUID
+	Status    string
+	Verdict   string
+	Score     int32
+	CreatedAt time.Time
+}
+
+func (q *Queries) GetContestAttempts(ctx context.Context, contestID uuid.NullUUID) ([]GetContestAttemptsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getContestAttempts, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetContestAttemptsRow
+	for rows.Next() {
+		var i GetContestAttemptsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ProblemID,
+			&i.Status,
+			&i.Verdict,
+			&i.Score,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContestByID = `-- name: GetContestByID :one
+SELECT id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, unfrozen_at, author_id, created_at, updated_at FROM contests
+WHERE id = $1
+`
+
+func (q *Queries) GetContestByID(ctx context.Context, id uuid.UUID) (Contest, error) {
+	row := q.db.QueryRowContext(ctx, getContestByID, id)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.Scoring,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.FreezeMinutes,
+		&i.UnfrozenAt,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getContestBySlug = `-- name: GetContestBySlug :one
+SELECT id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, unfrozen_at, author_id, created_at, updated_at FROM contests
+WHERE slug = $1
+`
+
+func (q *Queries) GetContestBySlug(ctx context.Context, slug string) (Contest, error) {
+	row := q.db.QueryRowContext(ctx, getContestBySlug, slug)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.Scoring,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.FreezeMinutes,
+		&i.UnfrozenAt,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getContestParticipants = `-- name: GetContestParticipants :many
+SELECT contest_registrations.user_id, users.username, contest_registrations.registered_at
+FROM contest_registrations
+JOIN users ON users.id = contest_registrations.user_id
+WHERE contest_registrations.contest_id = $1
+ORDER BY users.username
+`
+
+type GetContestParticipantsRow struct {
+	UserID       uuid.UUID
+	Username     string
+	RegisteredAt time.Time
+}
+
+func (q *Queries) GetContestParticipants(ctx context.Context, contestID uuid.UUID) ([]GetContestParticipantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getContestParticipants, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetContestParticipantsRow
+	for rows.Next() {
+		var i GetContestParticipantsRow
+		if err := rows.Scan(&i.UserID, &i.Username, &i.RegisteredAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContestProblems = `-- name: GetContestProblems :many
+SELECT contest_problems.label, contest_problems.position, problems.id, problems.slug, problems.title,
+    problems.time_limit_ms, problems.memory_limit_mb
+FROM contest_problems
+JOIN problems ON problems.id = contest_problems.problem_id
+WHERE contest_problems.contest_id = $1
+ORDER BY contest_problems.position
+`
+
+type GetContestProblemsRow struct {
+	Label         string
+	Position      int32
+	ID            uuid.UUID
+	Slug          string
+	Title         string
+	TimeLimitMs   int32
+	MemoryLimitMb int32
+}
+
+func (q *Queries) GetContestProblems(ctx context.Context, contestID uuid.UUID) ([]GetContestProblemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getContestProblems, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetContestProblemsRow
+	for rows.Next() {
+		var i GetContestProblemsRow
+		if err := rows.Scan(
+			&i.Label,
+			&i.Position,
+			&i.ID,
+			&i.Slug,
+			&i.Title,
+			&i.TimeLimitMs,
+			&i.MemoryLimitMb,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContests = `-- name: GetContests :many
+SELECT id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, unfrozen_at, author_id, created_at, updated_at FROM contests
+ORDER BY starts_at DESC, id
+LIMIT $1 OFFSET $2
+`
+
+type GetContestsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) GetContests(ctx context.Context, arg GetContestsParams) ([]Contest, error) {
+	rows, err := q.db.QueryContext(ctx, getContests, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Contest
+	for rows.Next() {
+		var i Contest
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Title,
+			&i.Description,
+			&i.Scoring,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.FreezeMinutes,
+			&i.UnfrozenAt,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVirtualAttempts = `-- name: GetVirtualAttempts :many
+SELECT user_id, problem_id, status, verdict, score, created_at
+FROM submissions
+WHERE contest_id = $1 AND user_id = $2 AND virtual
+ORDER BY created_at
+`
+
+type GetVirtualAttemptsParams struct {
+	ContestID uuid.NullUUID
+	UserID    uuid.UUID
+}
+
+type GetVirtualAttemptsRow struct {
+	UserID    uuid.UUID
+	ProblemID uuid.UUID
+	Status    string
+	Verdict   string
+	Score     int32
+	CreatedAt time.Time
+}
+
+func (q *Queries) GetVirtualAttempts(ctx context.Context, arg GetVirtualAttemptsParams) ([]GetVirtualAttemptsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getVirtualAttempts, arg.ContestID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVirtualAttemptsRow
+	for rows.Next() {
+		var i GetVirtualAttemptsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ProblemID,
+			&i.Status,
+			&i.Verdict,
+			&i.Score,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVirtualParticipation = `-- name: GetVirtualParticipation :one
+SELECT contest_id, user_id, started_at FROM contest_virtual_participations
+WHERE contest_id = $1 AND user_id = $2
+`
+
+type GetVirtualParticipationParams struct {
+	ContestID uuid.UUID
+	UserID    uuid.UUID
+}
+
+func (q *Queries) GetVirtualParticipation(ctx context.Context, arg GetVirtualParticipationParams) (ContestVirtualParticipation, error) {
+	row := q.db.QueryRowContext(ctx, getVirtualParticipation, arg.ContestID, arg.UserID)
+	var i ContestVirtualParticipation
+	err := row.Scan(&i.ContestID, &i.UserID, &i.StartedAt)
+	return i, err
+}
+
+const hasContestSubmissions = `-- name: HasContestSubmissions :one
+SELECT EXISTS (
+    SELECT 1 FROM submissions
+    WHERE contest_id = $1 AND user_id = $2 AND NOT virtual
+)
+`
+
+type HasContestSubmissionsParams struct {
+	ContestID uuid.NullUUID
+	UserID    uuid.UUID
+}
+
+func (q *Queries) HasContestSubmissions(ctx context.Context, arg HasContestSubmissionsParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasContestSubmissions, arg.ContestID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isProblemInUpcomingContest = `-- name: IsProblemInUpcomingContest :one
+SELECT EXISTS (
+    SELECT 1 FROM contest_problems
+    JOIN contests ON contests.id = contest_problems.contest_id
+    WHERE contest_problems.problem_id = $1 AND contests.starts_at > $2
+)
+`
+
+type IsProblemInUpcomingContestParams struct {
+	ProblemID uuid.UUID
+	StartsAt  time.Time
+}
+
+func (q *Queries) IsProblemInUpcomingContest(ctx context.Context, arg IsProblemInUpcomingContestParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isProblemInUpcomingContest, arg.ProblemID, arg.StartsAt)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isRegisteredForContest = `-- name: IsRegisteredForContest :one
+SELECT EXISTS (
+    SELECT 1 FROM contest_registrations
+    WHERE contest_id = $1 AND user_id = $2
+)
+`
+
+type IsRegisteredForContestParams struct {
+	ContestID uuid.UUID
+	UserID    uuid.UUID
+}
+
+func (q *Queries) IsRegisteredForContest(ctx context.Context, arg IsRegisteredForContestParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isRegisteredForContest, arg.ContestID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const registerForContest = `-- name: RegisterForContest :exec
+INSERT INTO contest_registrations (contest_id, user_id, registered_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id, user_id) DO NOTHING
+`
+
+type RegisterForContestParams struct {
+	ContestID    uuid.UUID
+	UserID       uuid.UUID
+	RegisteredAt time.Time
+}
+
+func (q *Queries) RegisterForContest(ctx context.Context, arg RegisterForContestParams) error {
+	_, err := q.db.ExecContext(ctx, registerForContest, arg.ContestID, arg.UserID, arg.RegisteredAt)
+	return err
+}
+
+const startVirtualParticipation = `-- name: StartVirtualParticipation :one
+INSERT INTO contest_virtual_participations (contest_id, user_id, started_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id, user_id) DO NOTHING
+RETURNING contest_id, user_id, started_at
+`
+
+type StartVirtualParticipationParams struct {
+	ContestID uuid.UUID
+	UserID    uuid.UUID
+	StartedAt time.Time
+}
+
+func (q *Queries) StartVirtualParticipation(ctx context.Context, arg StartVirtualParticipationParams) (ContestVirtualParticipation, error) {
+	row := q.db.QueryRowContext(ctx, startVirtualParticipation, arg.ContestID, arg.UserID, arg.StartedAt)
+	var i ContestVirtualParticipation
+	err := row.Scan(&i.ContestID, &i.UserID, &i.StartedAt)
+	return i, err
+}
+
+const unfreezeContest = `-- name: UnfreezeContest :one
+UPDATE contests
+SET unfrozen_at = $2
+WHERE id = $1
+RETURNING id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, unfrozen_at, author_id, created_at, updated_at
+`
+
+type UnfreezeContestParams struct {
+	ID         uuid.UUID
+	UnfrozenAt sql.NullTime
+}
+
+func (q *Queries) UnfreezeContest(ctx context.Context, arg UnfreezeContestParams) (Contest, error) {
+	row := q.db.QueryRowContext(ctx, unfreezeContest, arg.ID, arg.UnfrozenAt)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.Scoring,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.FreezeMinutes,
+		&i.UnfrozenAt,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const unregisterFromContest = `-- name: UnregisterFromContest :execrows
+DELETE FROM contest_registrations
+WHERE contest_id = $1 AND user_id = $2
+`
+
+type UnregisterFromContestParams struct {
+	ContestID uuid.UUID
+	UserID    uuid.UUID
+}
+
+func (q *Queries) UnregisterFromContest(ctx context.Context, arg UnregisterFromContestParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unregisterFromContest, arg.ContestID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateContest = `-- name: UpdateContest :one
+UPDATE contests
+SET slug = $2,
+    title = $3,
+    description = $4,
+    scoring = $5,
+    starts_at = $6,
+    ends_at = $7,
+    freeze_minutes = $8,
+    updated_at = $9
+WHERE id = $1
+RETURNING id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, unfrozen_at, author_id, created_at, updated_at
+`
+
+type UpdateContestParams struct {
+	ID            uuid.UUID
+	Slug          string
+	Title         string
+	Description   string
+	Scoring       string
+	StartsAt      time.Time
+	EndsAt        time.Time
+	FreezeMinutes int32
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpdateContest(ctx context.Context, arg UpdateContestParams) (Contest, error) {
+	row := q.db.QueryRowContext(ctx, updateContest,
+		arg.ID,
+		arg.Slug,
+		arg.Title,
+		arg.Description,
+		arg.Scoring,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.FreezeMinutes,
+		arg.UpdatedAt,
+	)
+	var i Contest
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.Scoring,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.FreezeMinutes,
+		&i.UnfrozenAt,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -20,7 +20,7 @@ RETURNING id, user_id, filename, filepath, filesize, uploaded_at
 
 type CreateFileParams struct {
 	ID         uuid.UUID
-	UserID     uuid.UUID
+	UserID     uuid.NullUUID
 	Filename   string
 	Filepath   string
 	Filesize   int64
@@ -48,6 +48,26 @@ func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, e
 	return i, err
 }
 
+const deleteFile = `-- name: DeleteFile :exec
+DELETE FROM files
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFile(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFile, id)
+	return err
+}
+
+const deleteFilesByPath = `-- name: DeleteFilesByPath :exec
+DELETE FROM files
+WHERE filepath = $1
+`
+
+func (q *Queries) DeleteFilesByPath(ctx context.Context, filepath string) error {
+	_, err := q.db.ExecContext(ctx, deleteFilesByPath, filepath)
+	return err
+}
+
 const getFileByID = `-- name: GetFileByID :one
 SELECT id, user_id, filename, filepath, filesize, uploaded_at FROM files
 WHERE id = $1
@@ -75,7 +95,7 @@ LIMIT $2 OFFSET $3
 `
 
 type GetFilesByUserIDParams struct {
-	UserID uuid.UUID
+	UserID uuid.NullUUID
 	Limit  int32
 	Offset int32
 }
@@ -109,3 +129,48 @@ func (q *Queries) GetFilesByUserID(ctx context.Context, arg GetFilesByUserIDPara
 	}
 	return items, nil
 }
+
+const getPersonalFilesByUserID = `-- name: GetPersonalFilesByUserID :many
+SELECT id, user_id, filename, filepath, filesize, uploaded_at FROM files
+WHERE user_id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM problem_tests
+        WHERE problem_tests.input_file_id = files.id
+            OR problem_tests.output_file_id = files.id
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM lessons
+        WHERE lessons.filepath = files.filepath
+    )
+`
+
+// Files that go away with their uploader, the tests of problems and lessons stay in the catalog
+func (q *Queries) GetPersonalFilesByUserID(ctx context.Context, userID uuid.NullUUID) ([]File, error) {
+	rows, err := q.db.QueryContext(ctx, getPersonalFilesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Filename,
+			&i.Filepath,
+			&i.Filesize,
+			&i.UploadedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
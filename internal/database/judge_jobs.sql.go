@@ -0,0 +1,187 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: judge_jobs.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimJudgeJob = `-- name: ClaimJudgeJob :one
+UPDATE judge_jobs
+SET status = 'running', attempts = attempts + 1, locked_by = $1, locked_at = $2, updated_at = $2
+WHERE id = (
+    SELECT id FROM judge_jobs
+    WHERE status = 'queued' AND run_after <= $2
+    ORDER BY run_after, created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING id, submission_id, status, attempts, last_error, run_after, locked_by, locked_at, created_at, updated_at
+`
+
+type ClaimJudgeJobParams struct {
+	LockedBy sql.NullString
+	LockedAt sql.NullTime
+}
+
+func (q *Queries) ClaimJudgeJob(ctx context.Context, arg ClaimJudgeJobParams) (JudgeJob, error) {
+	row := q.db.QueryRowContext(ctx, claimJudgeJob, arg.LockedBy, arg.LockedAt)
+	var i JudgeJob
+	err := row.Scan(
+		&i.ID,
+		&i.SubmissionID,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.RunAfter,
+		&i.LockedBy,
+		&i.LockedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const completeJudgeJob = `-- name: CompleteJudgeJob :exec
+UPDATE judge_jobs
+SET status = 'done', locked_by = NULL, locked_at = NULL, updated_at = $2
+WHERE id = $1
+`
+
+type CompleteJudgeJobParams struct {
+	ID        uuid.UUID
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CompleteJudgeJob(ctx context.Context, arg CompleteJudgeJobParams) error {
+	_, err := q.db.ExecContext(ctx, completeJudgeJob, arg.ID, arg.UpdatedAt)
+	return err
+}
+
+const enqueueJudgeJob = `-- name: EnqueueJudgeJob :one
+INSERT INTO judge_jobs (id, submission_id, run_after, created_at, updated_at)
+VALUES ($1, $2, $3, $3, $3)
+RETURNING id, submission_id, status, attempts, last_error, run_after, locked_by, locked_at, created_at, updated_at
+`
+
+type EnqueueJudgeJobParams struct {
+	ID           uuid.UUID
+	SubmissionID uuid.UUID
+	RunAfter     time.Time
+}
+
+func (q *Queries) EnqueueJudgeJob(ctx context.Context, arg EnqueueJudgeJobParams) (JudgeJob, error) {
+	row := q.db.QueryRowContext(ctx, enqueueJudgeJob, arg.ID, arg.SubmissionID, arg.RunAfter)
+	var i JudgeJob
+	err := row.Scan(
+		&i.ID,
+		&i.SubmissionID,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.RunAfter,
+		&i.LockedBy,
+		&i.LockedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const failJudgeJob = `-- name: FailJudgeJob :exec
+UPDATE judge_jobs
+SET status = 'failed', last_error = $2, locked_by = NULL, locked_at = NULL, updated_at = $3
+WHERE id = $1
+`
+
+type FailJudgeJobParams struct {
+	ID        uuid.UUID
+	LastError string
+	UpdatedAt time.Time
+}
+
+func (q *Queries) FailJudgeJob(ctx context.Context, arg FailJudgeJobParams) error {
+	_, err := q.db.ExecContext(ctx, failJudgeJob, arg.ID, arg.LastError, arg.UpdatedAt)
+	return err
+}
+
+const heartbeatJudgeJob = `-- name: HeartbeatJudgeJob :exec
+UPDATE judge_jobs
+SET locked_at = $2
+WHERE id = $1 AND status = 'running'
+`
+
+type HeartbeatJudgeJobParams struct {
+	ID       uuid.UUID
+	LockedAt sql.NullTime
+}
+
+func (q *Queries) HeartbeatJudgeJob(ctx context.Context, arg HeartbeatJudgeJobParams) error {
+	_, err := q.db.ExecContext(ctx, heartbeatJudgeJob, arg.ID, arg.LockedAt)
+	return err
+}
+
+const requeueStaleJudgeJobs = `-- name: RequeueStaleJudgeJobs :many
+UPDATE judge_jobs
+SET status = 'queued', locked_by = NULL, locked_at = NULL, run_after = $1, updated_at = $1
+WHERE status = 'running' AND locked_at < $2
+RETURNING submission_id
+`
+
+type RequeueStaleJudgeJobsParams struct {
+	RunAfter time.Time
+	LockedAt sql.NullTime
+}
+
+func (q *Queries) RequeueStaleJudgeJobs(ctx context.Context, arg RequeueStaleJudgeJobsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, requeueStaleJudgeJobs, arg.RunAfter, arg.LockedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var submission_id uuid.UUID
+		if err := rows.Scan(&submission_id); err != nil {
+			return nil, err
+		}
+		items = append(items, submission_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const retryJudgeJob = `-- name: RetryJudgeJob :exec
+UPDATE judge_jobs
+SET status = 'queued', last_error = $2, run_after = $3, locked_by = NULL, locked_at = NULL, updated_at = $4
+WHERE id = $1
+`
+
+type RetryJudgeJobParams struct {
+	ID        uuid.UUID
+	LastError string
+	RunAfter  time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) RetryJudgeJob(ctx context.Context, arg RetryJudgeJobParams) error {
+	_, err := q.db.ExecContext(ctx, retryJudgeJob,
+		arg.ID,
+		arg.LastError,
+		arg.RunAfter,
+		arg.UpdatedAt,
+	)
+	return err
+}
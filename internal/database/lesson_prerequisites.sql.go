@@ -0,0 +1,93 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: lesson_prerequisites.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addLessonPrerequisite = `-- name: AddLessonPrerequisite :exec
+INSERT INTO lesson_prerequisites (lesson_id, prerequisite_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddLessonPrerequisiteParams struct {
+	LessonID       int32
+	PrerequisiteID int32
+}
+
+func (q *Queries) AddLessonPrerequisite(ctx context.Context, arg AddLessonPrerequisiteParams) error {
+	_, err := q.db.ExecContext(ctx, addLessonPrerequisite, arg.LessonID, arg.PrerequisiteID)
+	return err
+}
+
+const deleteLessonPrerequisites = `-- name: DeleteLessonPrerequisites :exec
+DELETE FROM lesson_prerequisites
+WHERE lesson_id = $1
+`
+
+func (q *Queries) DeleteLessonPrerequisites(ctx context.Context, lessonID int32) error {
+	_, err := q.db.ExecContext(ctx, deleteLessonPrerequisites, lessonID)
+	return err
+}
+
+const getAllLessonPrerequisites = `-- name: GetAllLessonPrerequisites :many
+SELECT lesson_id, prerequisite_id FROM lesson_prerequisites
+ORDER BY lesson_id, prerequisite_id
+`
+
+func (q *Queries) GetAllLessonPrerequisites(ctx context.Context) ([]LessonPrerequisite, error) {
+	rows, err := q.db.QueryContext(ctx, getAllLessonPrerequisites)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LessonPrerequisite
+	for rows.Next() {
+		var i LessonPrerequisite
+		if err := rows.Scan(&i.LessonID, &i.PrerequisiteID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLessonPrerequisites = `-- name: GetLessonPrerequisites :many
+SELECT prerequisite_id FROM lesson_prerequisites
+WHERE lesson_id = $1
+ORDER BY prerequisite_id
+`
+
+func (q *Queries) GetLessonPrerequisites(ctx context.Context, lessonID int32) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, getLessonPrerequisites, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var prerequisite_id int32
+		if err := rows.Scan(&prerequisite_id); err != nil {
+			return nil, err
+		}
+		items = append(items, prerequisite_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,230 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: lesson_progress.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getCompletedLessonIDs = `-- name: GetCompletedLessonIDs :many
+SELECT lesson_id FROM lesson_progress
+WHERE user_id = $1 AND completed_at IS NOT NULL
+`
+
+func (q *Queries) GetCompletedLessonIDs(ctx context.Context, userID uuid.UUID) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, getCompletedLessonIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var lesson_id int32
+		if err := rows.Scan(&lesson_id); err != nil {
+			return nil, err
+		}
+		items = append(items, lesson_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLessonProgress = `-- name: GetLessonProgress :one
+SELECT user_id, lesson_id, started_at, completed_at, last_section, updated_at FROM lesson_progress
+WHERE user_id = $1 AND lesson_id = $2
+`
+
+type GetLessonProgressParams struct {
+	UserID   uuid.UUID
+	LessonID int32
+}
+
+func (q *Queries) GetLessonProgress(ctx context.Context, arg GetLessonProgressParams) (LessonProgress, error) {
+	row := q.db.QueryRowContext(ctx, getLessonProgress, arg.UserID, arg.LessonID)
+	var i LessonProgress
+	err := row.Scan(
+		&i.UserID,
+		&i.LessonID,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.LastSection,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStartedLessonIDs = `-- name: GetStartedLessonIDs :many
+SELECT lesson_id FROM lesson_progress
+WHERE user_id = $1 AND completed_at IS NULL
+`
+
+func (q *Queries) GetStartedLessonIDs(ctx context.Context, userID uuid.UUID) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, getStartedLessonIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var lesson_id int32
+		if err := rows.Scan(&lesson_id); err != nil {
+			return nil, err
+		}
+		items = append(items, lesson_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserProgress = `-- name: GetUserProgress :many
+SELECT lesson_progress.user_id, lesson_progress.lesson_id, lesson_progress.started_at, lesson_progress.completed_at, lesson_progress.last_section, lesson_progress.updated_at, lessons.grade, lessons.title
+FROM lesson_progress
+JOIN lessons ON lessons.id = lesson_progress.lesson_id
+WHERE lesson_progress.user_id = $1 AND lessons.status = 'published'
+ORDER BY lessons.grade, lessons.id
+`
+
+type GetUserProgressRow struct {
+	UserID      uuid.UUID
+	LessonID    int32
+	StartedAt   time.Time
+	CompletedAt sql.NullTime
+	LastSection string
+	UpdatedAt   time.Time
+	Grade       string
+	Title       string
+}
+
+func (q *Queries) GetUserProgress(ctx context.Context, userID uuid.UUID) ([]GetUserProgressRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserProgress, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserProgressRow
+	for rows.Next() {
+		var i GetUserProgressRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.LessonID,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.LastSection,
+			&i.UpdatedAt,
+			&i.Grade,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserProgressSummary = `-- name: GetUserProgressSummary :many
+SELECT lessons.grade,
+    COUNT(lessons.id) AS total,
+    COUNT(lesson_progress.started_at) AS started,
+    COUNT(lesson_progress.completed_at) AS completed
+FROM lessons
+LEFT JOIN lesson_progress ON lesson_progress.lesson_id = lessons.id AND lesson_progress.user_id = $1
+WHERE lessons.status = 'published'
+GROUP BY lessons.grade
+ORDER BY lessons.grade
+`
+
+type GetUserProgressSummaryRow struct {
+	Grade     string
+	Total     int64
+	Started   int64
+	Completed int64
+}
+
+func (q *Queries) GetUserProgressSummary(ctx context.Context, userID uuid.UUID) ([]GetUserProgressSummaryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserProgressSummary, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserProgressSummaryRow
+	for rows.Next() {
+		var i GetUserProgressSummaryRow
+		if err := rows.Scan(
+			&i.Grade,
+			&i.Total,
+			&i.Started,
+			&i.Completed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertLessonProgress = `-- name: UpsertLessonProgress :one
+INSERT INTO lesson_progress (user_id, lesson_id, started_at, completed_at, last_section, updated_at)
+VALUES ($1, $2, $3, $4, $5, $3)
+ON CONFLICT (user_id, lesson_id) DO UPDATE
+SET last_section = CASE WHEN EXCLUDED.last_section <> '' THEN EXCLUDED.last_section ELSE lesson_progress.last_section END,
+    completed_at = COALESCE(lesson_progress.completed_at, EXCLUDED.completed_at),
+    updated_at = EXCLUDED.updated_at
+RETURNING user_id, lesson_id, started_at, completed_at, last_section, updated_at
+`
+
+type UpsertLessonProgressParams struct {
+	UserID      uuid.UUID
+	LessonID    int32
+	StartedAt   time.Time
+	CompletedAt sql.NullTime
+	LastSection string
+}
+
+func (q *Queries) UpsertLessonProgress(ctx context.Context, arg UpsertLessonProgressParams) (LessonProgress, error) {
+	row := q.db.QueryRowContext(ctx, upsertLessonProgress,
+		arg.UserID,
+		arg.LessonID,
+		arg.StartedAt,
+		arg.CompletedAt,
+		arg.LastSection,
+	)
+	var i LessonProgress
+	err := row.Scan(
+		&i.UserID,
+		&i.LessonID,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.LastSection,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
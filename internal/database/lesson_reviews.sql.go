@@ -0,0 +1,93 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: lesson_reviews.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createLessonReview = `-- name: CreateLessonReview :one
+INSERT INTO lesson_reviews (id, lesson_id, reviewer_id, action, from_status, to_status, comment, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, lesson_id, reviewer_id, action, from_status, to_status, comment, created_at
+`
+
+type CreateLessonReviewParams struct {
+	ID         uuid.UUID
+	LessonID   int32
+	ReviewerID uuid.NullUUID
+	Action     string
+	FromStatus string
+	ToStatus   string
+	Comment    string
+	CreatedAt  time.Time
+}
+
+func (q *Queries) CreateLessonReview(ctx context.Context, arg CreateLessonReviewParams) (LessonReview, error) {
+	row := q.db.QueryRowContext(ctx, createLessonReview,
+		arg.ID,
+		arg.LessonID,
+		arg.ReviewerID,
+		arg.Action,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Comment,
+		arg.CreatedAt,
+	)
+	var i LessonReview
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.ReviewerID,
+		&i.Action,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.Comment,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLessonReviews = `-- name: GetLessonReviews :many
+SELECT id, lesson_id, reviewer_id, action, from_status, to_status, comment, created_at FROM lesson_reviews
+WHERE lesson_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetLessonReviews(ctx context.Context, lessonID int32) ([]LessonReview, error) {
+	rows, err := q.db.QueryContext(ctx, getLessonReviews, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LessonReview
+	for rows.Next() {
+		var i LessonReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.LessonID,
+			&i.ReviewerID,
+			&i.Action,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Comment,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
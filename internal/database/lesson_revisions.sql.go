@@ -0,0 +1,139 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: lesson_revisions.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createLessonRevision = `-- name: CreateLessonRevision :one
+INSERT INTO lesson_revisions (id, lesson_id, author_id, content, content_hash, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, lesson_id, author_id, content, content_hash, created_at
+`
+
+type CreateLessonRevisionParams struct {
+	ID          uuid.UUID
+	LessonID    int32
+	AuthorID    uuid.NullUUID
+	Content     string
+	ContentHash string
+	CreatedAt   time.Time
+}
+
+func (q *Queries) CreateLessonRevision(ctx context.Context, arg CreateLessonRevisionParams) (LessonRevision, error) {
+	row := q.db.QueryRowContext(ctx, createLessonRevision,
+		arg.ID,
+		arg.LessonID,
+		arg.AuthorID,
+		arg.Content,
+		arg.ContentHash,
+		arg.CreatedAt,
+	)
+	var i LessonRevision
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.AuthorID,
+		&i.Content,
+		&i.ContentHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestLessonRevision = `-- name: GetLatestLessonRevision :one
+SELECT id, lesson_id, author_id, content, content_hash, created_at FROM lesson_revisions
+WHERE lesson_id = $1
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestLessonRevision(ctx context.Context, lessonID int32) (LessonRevision, error) {
+	row := q.db.QueryRowContext(ctx, getLatestLessonRevision, lessonID)
+	var i LessonRevision
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.AuthorID,
+		&i.Content,
+		&i.ContentHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLessonRevisionByID = `-- name: GetLessonRevisionByID :one
+SELECT id, lesson_id, author_id, content, content_hash, created_at FROM lesson_revisions
+WHERE id = $1
+`
+
+func (q *Queries) GetLessonRevisionByID(ctx context.Context, id uuid.UUID) (LessonRevision, error) {
+	row := q.db.QueryRowContext(ctx, getLessonRevisionByID, id)
+	var i LessonRevision
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.AuthorID,
+		&i.Content,
+		&i.ContentHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLessonRevisions = `-- name: GetLessonRevisions :many
+SELECT id, lesson_id, author_id, content_hash, created_at FROM lesson_revisions
+WHERE lesson_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetLessonRevisionsParams struct {
+	LessonID int32
+	Limit    int32
+	Offset   int32
+}
+
+type GetLessonRevisionsRow struct {
+	ID          uuid.UUID
+	LessonID    int32
+	AuthorID    uuid.NullUUID
+	ContentHash string
+	CreatedAt   time.Time
+}
+
+func (q *Queries) GetLessonRevisions(ctx context.Context, arg GetLessonRevisionsParams) ([]GetLessonRevisionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLessonRevisions, arg.LessonID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLessonRevisionsRow
+	for rows.Next() {
+		var i GetLessonRevisionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.LessonID,
+			&i.AuthorID,
+			&i.ContentHash,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
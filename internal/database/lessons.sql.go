@@ -0,0 +1,331 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: lessons.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const getLessonByID = `-- name: GetLessonByID :one
+SELECT id, grade, title, slug, filepath, body, created_at, updated_at, tags, author, estimated_minutes, status, author_id FROM lessons
+WHERE id = $1
+`
+
+func (q *Queries) GetLessonByID(ctx context.Context, id int32) (Lesson, error) {
+	row := q.db.QueryRowContext(ctx, getLessonByID, id)
+	var i Lesson
+	err := row.Scan(
+		&i.ID,
+		&i.Grade,
+		&i.Title,
+		&i.Slug,
+		&i.Filepath,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Tags),
+		&i.Author,
+		&i.EstimatedMinutes,
+		&i.Status,
+		&i.AuthorID,
+	)
+	return i, err
+}
+
+const getLessons = `-- name: GetLessons :many
+SELECT id, grade, title, slug, filepath, body, created_at, updated_at, tags, author, estimated_minutes, status, author_id FROM lessons
+ORDER BY grade, id
+`
+
+func (q *Queries) GetLessons(ctx context.Context) ([]Lesson, error) {
+	rows, err := q.db.QueryContext(ctx, getLessons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Lesson
+	for rows.Next() {
+		var i Lesson
+		if err := rows.Scan(
+			&i.ID,
+			&i.Grade,
+			&i.Title,
+			&i.Slug,
+			&i.Filepath,
+			&i.Body,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			pq.Array(&i.Tags),
+			&i.Author,
+			&i.EstimatedMinutes,
+			&i.Status,
+			&i.AuthorID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLessonsByGradeAndStatus = `-- name: GetLessonsByGradeAndStatus :many
+SELECT id, grade, title, slug, filepath, body, created_at, updated_at, tags, author, estimated_minutes, status, author_id FROM lessons
+WHERE grade = $1 AND status = $2
+ORDER BY id
+`
+
+type GetLessonsByGradeAndStatusParams struct {
+	Grade  string
+	Status string
+}
+
+func (q *Queries) GetLessonsByGradeAndStatus(ctx context.Context, arg GetLessonsByGradeAndStatusParams) ([]Lesson, error) {
+	rows, err := q.db.QueryContext(ctx, getLessonsByGradeAndStatus, arg.Grade, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Lesson
+	for rows.Next() {
+		var i Lesson
+		if err := rows.Scan(
+			&i.ID,
+			&i.Grade,
+			&i.Title,
+			&i.Slug,
+			&i.Filepath,
+			&i.Body,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			pq.Array(&i.Tags),
+			&i.Author,
+			&i.EstimatedMinutes,
+			&i.Status,
+			&i.AuthorID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLessonsByStatus = `-- name: GetLessonsByStatus :many
+SELECT id, grade, title, slug, filepath, body, created_at, updated_at, tags, author, estimated_minutes, status, author_id FROM lessons
+WHERE status = $1
+ORDER BY grade, id
+`
+
+func (q *Queries) GetLessonsByStatus(ctx context.Context, status string) ([]Lesson, error) {
+	rows, err := q.db.QueryContext(ctx, getLessonsByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Lesson
+	for rows.Next() {
+		var i Lesson
+		if err := rows.Scan(
+			&i.ID,
+			&i.Grade,
+			&i.Title,
+			&i.Slug,
+			&i.Filepath,
+			&i.Body,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			pq.Array(&i.Tags),
+			&i.Author,
+			&i.EstimatedMinutes,
+			&i.Status,
+			&i.AuthorID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchLessons = `-- name: SearchLessons :many
+SELECT id, grade, title, slug,
+    ts_rank(
+        setweight(to_tsvector('codium_ro', title), 'A') || setweight(to_tsvector('codium_ro', body), 'B'),
+        websearch_to_tsquery('codium_ro', $1)
+    )::real AS rank,
+    ts_headline('codium_ro', body, websearch_to_tsquery('codium_ro', $1), $2)::text AS snippet
+FROM lessons
+WHERE status = 'published'
+    AND (setweight(to_tsvector('codium_ro', title), 'A') || setweight(to_tsvector('codium_ro', body), 'B')) @@ websearch_to_tsquery('codium_ro', $1)
+ORDER BY rank DESC, id
+LIMIT $3
+`
+
+type SearchLessonsParams struct {
+	Query           string
+	HeadlineOptions string
+	MaxResults      int32
+}
+
+type SearchLessonsRow struct {
+	ID      int32
+	Grade   string
+	Title   string
+	Slug    string
+	Rank    float32
+	Snippet string
+}
+
+func (q *Queries) SearchLessons(ctx context.Context, arg SearchLessonsParams) ([]SearchLessonsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchLessons, arg.Query, arg.HeadlineOptions, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchLessonsRow
+	for rows.Next() {
+		var i SearchLessonsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Grade,
+			&i.Title,
+			&i.Slug,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateLessonStatus = `-- name: UpdateLessonStatus :one
+UPDATE lessons
+SET status = $2, updated_at = $3
+WHERE id = $1
+RETURNING id, grade, title, slug, filepath, body, created_at, updated_at, tags, author, estimated_minutes, status, author_id
+`
+
+type UpdateLessonStatusParams struct {
+	ID        int32
+	Status    string
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpdateLessonStatus(ctx context.Context, arg UpdateLessonStatusParams) (Lesson, error) {
+	row := q.db.QueryRowContext(ctx, updateLessonStatus, arg.ID, arg.Status, arg.UpdatedAt)
+	var i Lesson
+	err := row.Scan(
+		&i.ID,
+		&i.Grade,
+		&i.Title,
+		&i.Slug,
+		&i.Filepath,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Tags),
+		&i.Author,
+		&i.EstimatedMinutes,
+		&i.Status,
+		&i.AuthorID,
+	)
+	return i, err
+}
+
+const upsertLesson = `-- name: UpsertLesson :one
+INSERT INTO lessons (id, grade, title, slug, filepath, body, tags, author, estimated_minutes, status, author_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+ON CONFLICT (id) DO UPDATE
+SET grade = EXCLUDED.grade,
+    title = EXCLUDED.title,
+    slug = EXCLUDED.slug,
+    filepath = EXCLUDED.filepath,
+    body = EXCLUDED.body,
+    tags = EXCLUDED.tags,
+    author = EXCLUDED.author,
+    estimated_minutes = EXCLUDED.estimated_minutes,
+    updated_at = EXCLUDED.updated_at
+RETURNING id, grade, title, slug, filepath, body, created_at, updated_at, tags, author, estimated_minutes, status, author_id
+`
+
+type UpsertLessonParams struct {
+	ID               int32
+	Grade            string
+	Title            string
+	Slug             string
+	Filepath         string
+	Body             string
+	Tags             []string
+	Author           string
+	EstimatedMinutes int32
+	Status           string
+	AuthorID         uuid.NullUUID
+	CreatedAt        time.Time
+}
+
+func (q *Queries) UpsertLesson(ctx context.Context, arg UpsertLessonParams) (Lesson, error) {
+	row := q.db.QueryRowContext(ctx, upsertLesson,
+		arg.ID,
+		arg.Grade,
+		arg.Title,
+		arg.Slug,
+		arg.Filepath,
+		arg.Body,
+		pq.Array(arg.Tags),
+		arg.Author,
+		arg.EstimatedMinutes,
+		arg.Status,
+		arg.AuthorID,
+		arg.CreatedAt,
+	)
+	var i Lesson
+	err := row.Scan(
+		&i.ID,
+		&i.Grade,
+		&i.Title,
+		&i.Slug,
+		&i.Filepath,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Tags),
+		&i.Author,
+		&i.EstimatedMinutes,
+		&i.Status,
+		&i.AuthorID,
+	)
+	return i, err
+}
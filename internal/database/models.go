@@ -11,15 +11,155 @@ import (
 	"github.com/google/uuid"
 )
 
+type Contest struct {
+	ID            uuid.UUID
+	Slug          string
+	Title         string
+	Description   string
+	Scoring       string
+	StartsAt      time.Time
+	EndsAt        time.Time
+	FreezeMinutes int32
+	UnfrozenAt    sql.NullTime
+	AuthorID      uuid.NullUUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type ContestProblem struct {
+	ContestID uuid.UUID
+	ProblemID uuid.UUID
+	Label     string
+	Position  int32
+}
+
+type ContestRegistration struct {
+	ContestID    uuid.UUID
+	UserID       uuid.UUID
+	RegisteredAt time.Time
+}
+
+type ContestVirtualParticipation struct {
+	ContestID uuid.UUID
+	UserID    uuid.UUID
+	StartedAt time.Time
+}
+
 type File struct {
 	ID         uuid.UUID
-	UserID     uuid.UUID
+	UserID     uuid.NullUUID
 	Filename   string
 	Filepath   string
 	Filesize   int64
 	UploadedAt sql.NullTime
 }
 
+type JudgeJob struct {
+	ID           uuid.UUID
+	SubmissionID uuid.UUID
+	Status       string
+	Attempts     int32
+	LastError    string
+	RunAfter     time.Time
+	LockedBy     sql.NullString
+	LockedAt     sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Lesson struct {
+	ID               int32
+	Grade            string
+	Title            string
+	Slug             string
+	Filepath         string
+	Body             string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Tags             []string
+	Author           string
+	EstimatedMinutes int32
+	Status           string
+	AuthorID         uuid.NullUUID
+}
+
+type LessonPrerequisite struct {
+	LessonID       int32
+	PrerequisiteID int32
+}
+
+type LessonProgress struct {
+	UserID      uuid.UUID
+	LessonID    int32
+	StartedAt   time.Time
+	CompletedAt sql.NullTime
+	LastSection string
+	UpdatedAt   time.Time
+}
+
+type LessonReview struct {
+	ID         uuid.UUID
+	LessonID   int32
+	ReviewerID uuid.NullUUID
+	Action     string
+	FromStatus string
+	ToStatus   string
+	Comment    string
+	CreatedAt  time.Time
+}
+
+type LessonRevision struct {
+	ID          uuid.UUID
+	LessonID    int32
+	AuthorID    uuid.NullUUID
+	Content     string
+	ContentHash string
+	CreatedAt   time.Time
+}
+
+type Problem struct {
+	ID             uuid.UUID
+	Slug           string
+	Title          string
+	Statement      string
+	InputFormat    string
+	OutputFormat   string
+	TimeLimitMs    int32
+	MemoryLimitMb  int32
+	Difficulty     string
+	Tags           []string
+	AuthorID       uuid.NullUUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	CheckerMode    string
+	CheckerEpsilon float64
+	CheckerSource  string
+}
+
+type ProblemSample struct {
+	ProblemID   uuid.UUID
+	Position    int32
+	Input       string
+	Output      string
+	Explanation string
+}
+
+type ProblemSubtask struct {
+	ProblemID uuid.UUID
+	Number    int32
+	Points    int32
+	Tests     []string
+	DependsOn []int32
+}
+
+type ProblemTest struct {
+	ProblemID    uuid.UUID
+	Position     int32
+	Name         string
+	InputFileID  uuid.UUID
+	OutputFileID uuid.UUID
+}
+
 type RefreshToken struct {
 	Token     string
 	CreatedAt time.Time
@@ -29,6 +169,58 @@ type RefreshToken struct {
 	RevokedAt sql.NullTime
 }
 
+type Rejudge struct {
+	ID          uuid.UUID
+	ProblemID   uuid.UUID
+	Scope       string
+	RequestedBy uuid.NullUUID
+	CreatedAt   time.Time
+}
+
+type RejudgeSubmission struct {
+	RejudgeID    uuid.UUID
+	SubmissionID uuid.UUID
+	OldVerdict   string
+	OldScore     int32
+}
+
+type Submission struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	ProblemID     uuid.UUID
+	Language      string
+	Source        string
+	Status        string
+	Verdict       string
+	CompileOutput string
+	TimeMs        int64
+	MemoryKb      int64
+	CreatedAt     time.Time
+	JudgedAt      sql.NullTime
+	Score         int32
+	MaxScore      int32
+	ContestID     uuid.NullUUID
+	Virtual       bool
+}
+
+type SubmissionSubtask struct {
+	SubmissionID uuid.UUID
+	Number       int32
+	Points       int32
+	Score        int32
+	Verdict      string
+}
+
+type SubmissionTest struct {
+	SubmissionID uuid.UUID
+	Position     int32
+	Name         string
+	Verdict      string
+	TimeMs       int64
+	MemoryKb     int64
+	Message      string
+}
+
 type User struct {
 	ID             uuid.UUID
 	Username       string
@@ -39,4 +231,5 @@ type User struct {
 	IsAdmin        bool
 	ProfilePicID   uuid.NullUUID
 	EmailValidated bool
+	IsTeacher      bool
 }
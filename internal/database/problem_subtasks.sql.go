@@ -0,0 +1,82 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: problem_subtasks.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addProblemSubtask = `-- name: AddProblemSubtask :exec
+INSERT INTO problem_subtasks (problem_id, number, points, tests, depends_on)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type AddProblemSubtaskParams struct {
+	ProblemID uuid.UUID
+	Number    int32
+	Points    int32
+	Tests     []string
+	DependsOn []int32
+}
+
+func (q *Queries) AddProblemSubtask(ctx context.Context, arg AddProblemSubtaskParams) error {
+	_, err := q.db.ExecContext(ctx, addProblemSubtask,
+		arg.ProblemID,
+		arg.Number,
+		arg.Points,
+		pq.Array(arg.Tests),
+		pq.Array(arg.DependsOn),
+	)
+	return err
+}
+
+const deleteProblemSubtasks = `-- name: DeleteProblemSubtasks :exec
+DELETE FROM problem_subtasks
+WHERE problem_id = $1
+`
+
+func (q *Queries) DeleteProblemSubtasks(ctx context.Context, problemID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProblemSubtasks, problemID)
+	return err
+}
+
+const getProblemSubtasks = `-- name: GetProblemSubtasks :many
+SELECT problem_id, number, points, tests, depends_on FROM problem_subtasks
+WHERE problem_id = $1
+ORDER BY number
+`
+
+func (q *Queries) GetProblemSubtasks(ctx context.Context, problemID uuid.UUID) ([]ProblemSubtask, error) {
+	rows, err := q.db.QueryContext(ctx, getProblemSubtasks, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProblemSubtask
+	for rows.Next() {
+		var i ProblemSubtask
+		if err := rows.Scan(
+			&i.ProblemID,
+			&i.Number,
+			&i.Points,
+			pq.Array(&i.Tests),
+			pq.Array(&i.DependsOn),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
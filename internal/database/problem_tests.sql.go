@@ -0,0 +1,100 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: problem_tests.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const addProblemTest = `-- name: AddProblemTest :exec
+INSERT INTO problem_tests (problem_id, position, name, input_file_id, output_file_id)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type AddProblemTestParams struct {
+	ProblemID    uuid.UUID
+	Position     int32
+	Name         string
+	InputFileID  uuid.UUID
+	OutputFileID uuid.UUID
+}
+
+func (q *Queries) AddProblemTest(ctx context.Context, arg AddProblemTestParams) error {
+	_, err := q.db.ExecContext(ctx, addProblemTest,
+		arg.ProblemID,
+		arg.Position,
+		arg.Name,
+		arg.InputFileID,
+		arg.OutputFileID,
+	)
+	return err
+}
+
+const deleteProblemTests = `-- name: DeleteProblemTests :exec
+DELETE FROM problem_tests
+WHERE problem_id = $1
+`
+
+func (q *Queries) DeleteProblemTests(ctx context.Context, problemID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProblemTests, problemID)
+	return err
+}
+
+const getProblemTests = `-- name: GetProblemTests :many
+SELECT problem_tests.position, problem_tests.name,
+    problem_tests.input_file_id, input_file.filepath AS input_path, input_file.filesize AS input_size,
+    problem_tests.output_file_id, output_file.filepath AS output_path, output_file.filesize AS output_size
+FROM problem_tests
+JOIN files AS input_file ON input_file.id = problem_tests.input_file_id
+JOIN files AS output_file ON output_file.id = problem_tests.output_file_id
+WHERE problem_tests.problem_id = $1
+ORDER BY problem_tests.position
+`
+
+type GetProblemTestsRow struct {
+	Position     int32
+	Name         string
+	InputFileID  uuid.UUID
+	InputPath    string
+	InputSize    int64
+	OutputFileID uuid.UUID
+	OutputPath   string
+	OutputSize   int64
+}
+
+func (q *Queries) GetProblemTests(ctx context.Context, problemID uuid.UUID) ([]GetProblemTestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProblemTests, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProblemTestsRow
+	for rows.Next() {
+		var i GetProblemTestsRow
+		if err := rows.Scan(
+			&i.Position,
+			&i.Name,
+			&i.InputFileID,
+			&i.InputPath,
+			&i.InputSize,
+			&i.OutputFileID,
+			&i.OutputPath,
+			&i.OutputSize,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,358 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: problems.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addProblemSample = `-- name: AddProblemSample :exec
+INSERT INTO problem_samples (problem_id, position, input, output, explanation)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type AddProblemSampleParams struct {
+	ProblemID   uuid.UUID
+	Position    int32
+	Input       string
+	Output      string
+	Explanation string
+}
+
+func (q *Queries) AddProblemSample(ctx context.Context, arg AddProblemSampleParams) error {
+	_, err := q.db.ExecContext(ctx, addProblemSample,
+		arg.ProblemID,
+		arg.Position,
+		arg.Input,
+		arg.Output,
+		arg.Explanation,
+	)
+	return err
+}
+
+const createProblem = `-- name: CreateProblem :one
+INSERT INTO problems (id, slug, title, statement, input_format, output_format, time_limit_ms, memory_limit_mb, difficulty, tags, author_id, checker_mode, checker_epsilon, checker_source, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $15)
+RETURNING id, slug, title, statement, input_format, output_format, time_limit_ms, memory_limit_mb, difficulty, tags, author_id, created_at, updated_at, checker_mode, checker_epsilon, checker_source
+`
+
+type CreateProblemParams struct {
+	ID             uuid.UUID
+	Slug           string
+	Title          string
+	Statement      string
+	InputFormat    string
+	OutputFormat   string
+	TimeLimitMs    int32
+	MemoryLimitMb  int32
+	Difficulty     string
+	Tags           []string
+	AuthorID       uuid.NullUUID
+	CheckerMode    string
+	CheckerEpsilon float64
+	CheckerSource  string
+	CreatedAt      time.Time
+}
+
+func (q *Queries) CreateProblem(ctx context.Context, arg CreateProblemParams) (Problem, error) {
+	row := q.db.QueryRowContext(ctx, createProblem,
+		arg.ID,
+		arg.Slug,
+		arg.Title,
+		arg.Statement,
+		arg.InputFormat,
+		arg.OutputFormat,
+		arg.TimeLimitMs,
+		arg.MemoryLimitMb,
+		arg.Difficulty,
+		pq.Array(arg.Tags),
+		arg.AuthorID,
+		arg.CheckerMode,
+		arg.CheckerEpsilon,
+		arg.CheckerSource,
+		arg.CreatedAt,
+	)
+	var i Problem
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Statement,
+		&i.InputFormat,
+		&i.OutputFormat,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMb,
+		&i.Difficulty,
+		pq.Array(&i.Tags),
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckerMode,
+		&i.CheckerEpsilon,
+		&i.CheckerSource,
+	)
+	return i, err
+}
+
+const deleteProblem = `-- name: DeleteProblem :exec
+DELETE FROM problems
+WHERE id = $1
+`
+
+func (q *Queries) DeleteProblem(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProblem, id)
+	return err
+}
+
+const deleteProblemSamples = `-- name: DeleteProblemSamples :exec
+DELETE FROM problem_samples
+WHERE problem_id = $1
+`
+
+func (q *Queries) DeleteProblemSamples(ctx context.Context, problemID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProblemSamples, problemID)
+	return err
+}
+
+const getProblemByID = `-- name: GetProblemByID :one
+SELECT id, slug, title, statement, input_format, output_format, time_limit_ms, memory_limit_mb, difficulty, tags, author_id, created_at, updated_at, checker_mode, checker_epsilon, checker_source FROM problems
+WHERE id = $1
+`
+
+func (q *Queries) GetProblemByID(ctx context.Context, id uuid.UUID) (Problem, error) {
+	row := q.db.QueryRowContext(ctx, getProblemByID, id)
+	var i Problem
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Statement,
+		&i.InputFormat,
+		&i.OutputFormat,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMb,
+		&i.Difficulty,
+		pq.Array(&i.Tags),
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckerMode,
+		&i.CheckerEpsilon,
+		&i.CheckerSource,
+	)
+	return i, err
+}
+
+const getProblemBySlug = `-- name: GetProblemBySlug :one
+SELECT id, slug, title, statement, input_format, output_format, time_limit_ms, memory_limit_mb, difficulty, tags, author_id, created_at, updated_at, checker_mode, checker_epsilon, checker_source FROM problems
+WHERE slug = $1
+`
+
+func (q *Queries) GetProblemBySlug(ctx context.Context, slug string) (Problem, error) {
+	row := q.db.QueryRowContext(ctx, getProblemBySlug, slug)
+	var i Problem
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Statement,
+		&i.InputFormat,
+		&i.OutputFormat,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMb,
+		&i.Difficulty,
+		pq.Array(&i.Tags),
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckerMode,
+		&i.CheckerEpsilon,
+		&i.CheckerSource,
+	)
+	return i, err
+}
+
+const getProblemSamples = `-- name: GetProblemSamples :many
+SELECT problem_id, position, input, output, explanation FROM problem_samples
+WHERE problem_id = $1
+ORDER BY position
+`
+
+func (q *Queries) GetProblemSamples(ctx context.Context, problemID uuid.UUID) ([]ProblemSample, error) {
+	rows, err := q.db.QueryContext(ctx, getProblemSamples, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProblemSample
+	for rows.Next() {
+		var i ProblemSample
+		if err := rows.Scan(
+			&i.ProblemID,
+			&i.Position,
+			&i.Input,
+			&i.Output,
+			&i.Explanation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProblems = `-- name: GetProblems :many
+SELECT id, slug, title, statement, input_format, output_format, time_limit_ms, memory_limit_mb, difficulty, tags, author_id, created_at, updated_at, checker_mode, checker_epsilon, checker_source FROM problems
+WHERE ($3::text IS NULL OR difficulty = $3::text)
+  AND ($4::text IS NULL OR $4::text = ANY(tags))
+  AND ($5::timestamptz IS NULL OR NOT EXISTS (
+    SELECT 1 FROM contest_problems
+    JOIN contests ON contests.id = contest_problems.contest_id
+    WHERE contest_problems.problem_id = problems.id AND contests.starts_at > $5::timestamptz
+  ))
+ORDER BY created_at, id
+LIMIT $1 OFFSET $2
+`
+
+type GetProblemsParams struct {
+	Limit      int32
+	Offset     int32
+	Difficulty sql.NullString
+	Tag        sql.NullString
+	VisibleAt  sql.NullTime
+}
+
+func (q *Queries) GetProblems(ctx context.Context, arg GetProblemsParams) ([]Problem, error) {
+	rows, err := q.db.QueryContext(ctx, getProblems,
+		arg.Limit,
+		arg.Offset,
+		arg.Difficulty,
+		arg.Tag,
+		arg.VisibleAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Problem
+	for rows.Next() {
+		var i Problem
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Title,
+			&i.Statement,
+			&i.InputFormat,
+			&i.OutputFormat,
+			&i.TimeLimitMs,
+			&i.MemoryLimitMb,
+			&i.Difficulty,
+			pq.Array(&i.Tags),
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CheckerMode,
+			&i.CheckerEpsilon,
+			&i.CheckerSource,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateProblem = `-- name: UpdateProblem :one
+UPDATE problems
+SET slug = $2,
+    title = $3,
+    statement = $4,
+    input_format = $5,
+    output_format = $6,
+    time_limit_ms = $7,
+    memory_limit_mb = $8,
+    difficulty = $9,
+    tags = $10,
+    checker_mode = $11,
+    checker_epsilon = $12,
+    checker_source = $13,
+    updated_at = $14
+WHERE id = $1
+RETURNING id, slug, title, statement, input_format, output_format, time_limit_ms, memory_limit_mb, difficulty, tags, author_id, created_at, updated_at, checker_mode, checker_epsilon, checker_source
+`
+
+type UpdateProblemParams struct {
+	ID             uuid.UUID
+	Slug           string
+	Title          string
+	Statement      string
+	InputFormat    string
+	OutputFormat   string
+	TimeLimitMs    int32
+	MemoryLimitMb  int32
+	Difficulty     string
+	Tags           []string
+	CheckerMode    string
+	CheckerEpsilon float64
+	CheckerSource  string
+	UpdatedAt      time.Time
+}
+
+func (q *Queries) UpdateProblem(ctx context.Context, arg UpdateProblemParams) (Problem, error) {
+	row := q.db.QueryRowContext(ctx, updateProblem,
+		arg.ID,
+		arg.Slug,
+		arg.Title,
+		arg.Statement,
+		arg.InputFormat,
+		arg.OutputFormat,
+		arg.TimeLimitMs,
+		arg.MemoryLimitMb,
+		arg.Difficulty,
+		pq.Array(arg.Tags),
+		arg.CheckerMode,
+		arg.CheckerEpsilon,
+		arg.CheckerSource,
+		arg.UpdatedAt,
+	)
+	var i Problem
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Title,
+		&i.Statement,
+		&i.InputFormat,
+		&i.OutputFormat,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMb,
+		&i.Difficulty,
+		pq.Array(&i.Tags),
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckerMode,
+		&i.CheckerEpsilon,
+		&i.CheckerSource,
+	)
+	return i, err
+}
@@ -0,0 +1,196 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: rejudges.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addProblemRejudgeSubmissions = `-- name: AddProblemRejudgeSubmissions :many
+
+INSERT INTO rejudge_submissions (rejudge_id, submission_id, old_verdict, old_score)
+SELECT $1, submissions.id, submissions.verdict, submissions.score
+FROM submissions
+WHERE submissions.problem_id = $2
+  AND NOT EXISTS (
+    SELECT 1 FROM judge_jobs
+    WHERE judge_jobs.submission_id = submissions.id AND judge_jobs.status IN ('queued', 'running')
+  )
+RETURNING submission_id
+`
+
+type AddProblemRejudgeSubmissionsParams struct {
+	RejudgeID uuid.UUID
+	ProblemID uuid.UUID
+}
+
+// Submissions that are already waiting for a judge are left alone, they will see the new tests anyway
+func (q *Queries) AddProblemRejudgeSubmissions(ctx context.Context, arg AddProblemRejudgeSubmissionsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, addProblemRejudgeSubmissions, arg.RejudgeID, arg.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var submission_id uuid.UUID
+		if err := rows.Scan(&submission_id); err != nil {
+			return nil, err
+		}
+		items = append(items, submission_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const addRejudgeSubmission = `-- name: AddRejudgeSubmission :many
+INSERT INTO rejudge_submissions (rejudge_id, submission_id, old_verdict, old_score)
+SELECT $1, submissions.id, submissions.verdict, submissions.score
+FROM submissions
+WHERE submissions.id = $2
+  AND NOT EXISTS (
+    SELECT 1 FROM judge_jobs
+    WHERE judge_jobs.submission_id = submissions.id AND judge_jobs.status IN ('queued', 'running')
+  )
+RETURNING submission_id
+`
+
+type AddRejudgeSubmissionParams struct {
+	RejudgeID uuid.UUID
+	ID        uuid.UUID
+}
+
+func (q *Queries) AddRejudgeSubmission(ctx context.Context, arg AddRejudgeSubmissionParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, addRejudgeSubmission, arg.RejudgeID, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var submission_id uuid.UUID
+		if err := rows.Scan(&submission_id); err != nil {
+			return nil, err
+		}
+		items = append(items, submission_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createRejudge = `-- name: CreateRejudge :one
+INSERT INTO rejudges (id, problem_id, scope, requested_by, created_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, problem_id, scope, requested_by, created_at
+`
+
+type CreateRejudgeParams struct {
+	ID          uuid.UUID
+	ProblemID   uuid.UUID
+	Scope       string
+	RequestedBy uuid.NullUUID
+	CreatedAt   time.Time
+}
+
+func (q *Queries) CreateRejudge(ctx context.Context, arg CreateRejudgeParams) (Rejudge, error) {
+	row := q.db.QueryRowContext(ctx, createRejudge,
+		arg.ID,
+		arg.ProblemID,
+		arg.Scope,
+		arg.RequestedBy,
+		arg.CreatedAt,
+	)
+	var i Rejudge
+	err := row.Scan(
+		&i.ID,
+		&i.ProblemID,
+		&i.Scope,
+		&i.RequestedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRejudgeByID = `-- name: GetRejudgeByID :one
+SELECT id, problem_id, scope, requested_by, created_at FROM rejudges
+WHERE id = $1
+`
+
+func (q *Queries) GetRejudgeByID(ctx context.Context, id uuid.UUID) (Rejudge, error) {
+	row := q.db.QueryRowContext(ctx, getRejudgeByID, id)
+	var i Rejudge
+	err := row.Scan(
+		&i.ID,
+		&i.ProblemID,
+		&i.Scope,
+		&i.RequestedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRejudgeSubmissions = `-- name: GetRejudgeSubmissions :many
+SELECT rejudge_submissions.submission_id, submissions.user_id, rejudge_submissions.old_verdict, rejudge_submissions.old_score,
+    submissions.status, submissions.verdict, submissions.score
+FROM rejudge_submissions
+JOIN submissions ON submissions.id = rejudge_submissions.submission_id
+WHERE rejudge_submissions.rejudge_id = $1
+ORDER BY submissions.created_at
+`
+
+type GetRejudgeSubmissionsRow struct {
+	SubmissionID uuid.UUID
+	UserID       uuid.UUID
+	OldVerdict   string
+	OldScore     int32
+	Status       string
+	Verdict      string
+	Score        int32
+}
+
+func (q *Queries) GetRejudgeSubmissions(ctx context.Context, rejudgeID uuid.UUID) ([]GetRejudgeSubmissionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRejudgeSubmissions, rejudgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRejudgeSubmissionsRow
+	for rows.Next() {
+		var i GetRejudgeSubmissionsRow
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.UserID,
+			&i.OldVerdict,
+			&i.OldScore,
+			&i.Status,
+			&i.Verdict,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,466 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: submissions.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addSubmissionSubtask = `-- name: AddSubmissionSubtask :exec
+INSERT INTO submission_subtasks (submission_id, number, points, score, verdict)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type AddSubmissionSubtaskParams struct {
+	SubmissionID uuid.UUID
+	Number       int32
+	Points       int32
+	Score        int32
+	Verdict      string
+}
+
+func (q *Queries) AddSubmissionSubtask(ctx context.Context, arg AddSubmissionSubtaskParams) error {
+	_, err := q.db.ExecContext(ctx, addSubmissionSubtask,
+		arg.SubmissionID,
+		arg.Number,
+		arg.Points,
+		arg.Score,
+		arg.Verdict,
+	)
+	return err
+}
+
+const addSubmissionTest = `-- name: AddSubmissionTest :exec
+INSERT INTO submission_tests (submission_id, position, name, verdict, time_ms, memory_kb, message)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type AddSubmissionTestParams struct {
+	SubmissionID uuid.UUID
+	Position     int32
+	Name         string
+	Verdict      string
+	TimeMs       int64
+	MemoryKb     int64
+	Message      string
+}
+
+func (q *Queries) AddSubmissionTest(ctx context.Context, arg AddSubmissionTestParams) error {
+	_, err := q.db.ExecContext(ctx, addSubmissionTest,
+		arg.SubmissionID,
+		arg.Position,
+		arg.Name,
+		arg.Verdict,
+		arg.TimeMs,
+		arg.MemoryKb,
+		arg.Message,
+	)
+	return err
+}
+
+const createSubmission = `-- name: CreateSubmission :one
+INSERT INTO submissions (id, user_id, problem_id, language, source, contest_id, virtual, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, user_id, problem_id, language, source, status, verdict, compile_output, time_ms, memory_kb, created_at, judged_at, score, max_score, contest_id, virtual
+`
+
+type CreateSubmissionParams struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	ProblemID uuid.UUID
+	Language  string
+	Source    string
+	ContestID uuid.NullUUID
+	Virtual   bool
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (Submission, error) {
+	row := q.db.QueryRowContext(ctx, createSubmission,
+		arg.ID,
+		arg.UserID,
+		arg.ProblemID,
+		arg.Language,
+		arg.Source,
+		arg.ContestID,
+		arg.Virtual,
+		arg.CreatedAt,
+	)
+	var i Submission
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProblemID,
+		&i.Language,
+		&i.Source,
+		&i.Status,
+		&i.Verdict,
+		&i.CompileOutput,
+		&i.TimeMs,
+		&i.MemoryKb,
+		&i.CreatedAt,
+		&i.JudgedAt,
+		&i.Score,
+		&i.MaxScore,
+		&i.ContestID,
+		&i.Virtual,
+	)
+	return i, err
+}
+
+const deleteSubmissionSubtasks = `-- name: DeleteSubmissionSubtasks :exec
+DELETE FROM submission_subtasks
+WHERE submission_id = $1
+`
+
+func (q *Queries) DeleteSubmissionSubtasks(ctx context.Context, submissionID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteSubmissionSubtasks, submissionID)
+	return err
+}
+
+const deleteSubmissionTests = `-- name: DeleteSubmissionTests :exec
+DELETE FROM submission_tests
+WHERE submission_id = $1
+`
+
+func (q *Queries) DeleteSubmissionTests(ctx context.Context, submissionID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteSubmissionTests, submissionID)
+	return err
+}
+
+const finishSubmission = `-- name: FinishSubmission :one
+UPDATE submissions
+SET status = 'done', verdict = $2, compile_output = $3, time_ms = $4, memory_kb = $5, score = $6, max_score = $7, judged_at = $8
+WHERE id = $1
+RETURNING id, user_id, problem_id, language, source, status, verdict, compile_output, time_ms, memory_kb, created_at, judged_at, score, max_score, contest_id, virtual
+`
+
+type FinishSubmissionParams struct {
+	ID            uuid.UUID
+	Verdict       string
+	CompileOutput string
+	TimeMs        int64
+	MemoryKb      int64
+	Score         int32
+	MaxScore      int32
+	JudgedAt      sql.NullTime
+}
+
+func (q *Queries) FinishSubmission(ctx context.Context, arg FinishSubmissionParams) (Submission, error) {
+	row := q.db.QueryRowContext(ctx, finishSubmission,
+		arg.ID,
+		arg.Verdict,
+		arg.CompileOutput,
+		arg.TimeMs,
+		arg.MemoryKb,
+		arg.Score,
+		arg.MaxScore,
+		arg.JudgedAt,
+	)
+	var i Submission
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProblemID,
+		&i.Language,
+		&i.Source,
+		&i.Status,
+		&i.Verdict,
+		&i.CompileOutput,
+		&i.TimeMs,
+		&i.MemoryKb,
+		&i.CreatedAt,
+		&i.JudgedAt,
+		&i.Score,
+		&i.MaxScore,
+		&i.ContestID,
+		&i.Virtual,
+	)
+	return i, err
+}
+
+const getLatestProblemSubmissions = `-- name: GetLatestProblemSubmissions :many
+SELECT DISTINCT ON (submissions.user_id) submissions.id, submissions.user_id, users.username, submissions.language,
+    submissions.source, submissions.verdict, submissions.created_at
+FROM submissions
+JOIN users ON users.id = submissions.user_id
+WHERE submissions.problem_id = $1
+  AND ($2::uuid IS NULL OR submissions.contest_id = $2)
+ORDER BY submissions.user_id, submissions.created_at DESC
+`
+
+type GetLatestProblemSubmissionsParams struct {
+	ProblemID uuid.UUID
+	ContestID uuid.NullUUID
+}
+
+type GetLatestProblemSubmissionsRow struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Username  string
+	Language  string
+	Source    string
+	Verdict   string
+	CreatedAt time.Time
+}
+
+func (q *Queries) GetLatestProblemSubmissions(ctx context.Context, arg GetLatestProblemSubmissionsParams) ([]GetLatestProblemSubmissionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestProblemSubmissions, arg.ProblemID, arg.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLatestProblemSubmissionsRow
+	for rows.Next() {
+		var i GetLatestProblemSubmissionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Username,
+			&i.Language,
+			&i.Source,
+			&i.Verdict,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubmissionByID = `-- name: GetSubmissionByID :one
+SELECT id, user_id, problem_id, language, source, status, verdict, compile_output, time_ms, memory_kb, created_at, judged_at, score, max_score, contest_id, virtual FROM submissions
+WHERE id = $1
+`
+
+func (q *Queries) GetSubmissionByID(ctx context.Context, id uuid.UUID) (Submission, error) {
+	row := q.db.QueryRowContext(ctx, getSubmissionByID, id)
+	var i Submission
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProblemID,
+		&i.Language,
+		&i.Source,
+		&i.Status,
+		&i.Verdict,
+		&i.CompileOutput,
+		&i.TimeMs,
+		&i.MemoryKb,
+		&i.CreatedAt,
+		&i.JudgedAt,
+		&i.Score,
+		&i.MaxScore,
+		&i.ContestID,
+		&i.Virtual,
+	)
+	return i, err
+}
+
+const getSubmissionSubtasks = `-- name: GetSubmissionSubtasks :many
+SELECT submission_id, number, points, score, verdict FROM submission_subtasks
+WHERE submission_id = $1
+ORDER BY number
+`
+
+func (q *Queries) GetSubmissionSubtasks(ctx context.Context, submissionID uuid.UUID) ([]SubmissionSubtask, error) {
+	rows, err := q.db.QueryContext(ctx, getSubmissionSubtasks, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubmissionSubtask
+	for rows.Next() {
+		var i SubmissionSubtask
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.Number,
+			&i.Points,
+			&i.Score,
+			&i.Verdict,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubmissionTests = `-- name: GetSubmissionTests :many
+SELECT submission_id, position, name, verdict, time_ms, memory_kb, message FROM submission_tests
+WHERE submission_id = $1
+ORDER BY position
+`
+
+func (q *Queries) GetSubmissionTests(ctx context.Context, submissionID uuid.UUID) ([]SubmissionTest, error) {
+	rows, err := q.db.QueryContext(ctx, getSubmissionTests, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubmissionTest
+	for rows.Next() {
+		var i SubmissionTest
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.Position,
+			&i.Name,
+			&i.Verdict,
+			&i.TimeMs,
+			&i.MemoryKb,
+			&i.Message,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSubmissions = `-- name: GetUserSubmissions :many
+SELECT submissions.id, submissions.problem_id, problems.slug AS problem_slug, problems.title AS problem_title,
+    submissions.language, submissions.status, submissions.verdict, submissions.score, submissions.max_score,
+    submissions.time_ms, submissions.memory_kb,
+    submissions.created_at, submissions.judged_at
+FROM submissions
+JOIN problems ON problems.id = submissions.problem_id
+WHERE submissions.user_id = $1
+  AND ($4::uuid IS NULL OR submissions.problem_id = $4::uuid)
+ORDER BY submissions.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetUserSubmissionsParams struct {
+	UserID    uuid.UUID
+	Limit     int32
+	Offset    int32
+	ProblemID uuid.NullUUID
+}
+
+type GetUserSubmissionsRow struct {
+	ID           uuid.UUID
+	ProblemID    uuid.UUID
+	ProblemSlug  string
+	ProblemTitle string
+	Language     string
+	Status       string
+	Verdict      string
+	Score        int32
+	MaxScore     int32
+	TimeMs       int64
+	MemoryKb     int64
+	CreatedAt    time.Time
+	JudgedAt     sql.NullTime
+}
+
+func (q *Queries) GetUserSubmissions(ctx context.Context, arg GetUserSubmissionsParams) ([]GetUserSubmissionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSubmissions,
+		arg.UserID,
+		arg.Limit,
+		arg.Offset,
+		arg.ProblemID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserSubmissionsRow
+	for rows.Next() {
+		var i GetUserSubmissionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProblemID,
+			&i.ProblemSlug,
+			&i.ProblemTitle,
+			&i.Language,
+			&i.Status,
+			&i.Verdict,
+			&i.Score,
+			&i.MaxScore,
+			&i.TimeMs,
+			&i.MemoryKb,
+			&i.CreatedAt,
+			&i.JudgedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markSubmissionPending = `-- name: MarkSubmissionPending :exec
+UPDATE submissions
+SET status = 'pending'
+WHERE id = $1 AND status <> 'done'
+`
+
+func (q *Queries) MarkSubmissionPending(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markSubmissionPending, id)
+	return err
+}
+
+const markSubmissionRunning = `-- name: MarkSubmissionRunning :exec
+UPDATE submissions
+SET status = 'running'
+WHERE id = $1
+`
+
+func (q *Queries) MarkSubmissionRunning(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markSubmissionRunning, id)
+	return err
+}
+
+const resetSubmissionStatus = `-- name: ResetSubmissionStatus :exec
+UPDATE submissions
+SET status = 'pending'
+WHERE id = $1
+`
+
+func (q *Queries) ResetSubmissionStatus(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, resetSubmissionStatus, id)
+	return err
+}
+
+const startSubmission = `-- name: StartSubmission :exec
+UPDATE submissions
+SET status = 'compiling', verdict = '', compile_output = '', time_ms = 0, memory_kb = 0, score = 0, max_score = 0, judged_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) StartSubmission(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, startSubmission, id)
+	return err
+}
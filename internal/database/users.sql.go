@@ -15,7 +15,7 @@ import (
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, email, password_hash, username, created_at, updated_at, is_admin)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
 `
 
 type CreateUserParams struct {
@@ -49,6 +49,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
@@ -73,7 +74,7 @@ func (q *Queries) DeleteUsers(ctx context.Context) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated FROM users WHERE email = $1
+SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -89,12 +90,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated FROM users WHERE id = $1
+SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
@@ -110,12 +112,13 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated FROM users WHERE username = $1
+SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher FROM users WHERE username = $1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -131,12 +134,13 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2
+SELECT id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2
 `
 
 type GetUsersParams struct {
@@ -163,6 +167,7 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 			&i.IsAdmin,
 			&i.ProfilePicID,
 			&i.EmailValidated,
+			&i.IsTeacher,
 		); err != nil {
 			return nil, err
 		}
@@ -181,7 +186,7 @@ const unvalidateEmailForId = `-- name: UnvalidateEmailForId :one
 UPDATE users
 SET email_validated = FALSE, updated_at = $2
 WHERE id = $1
-RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
 `
 
 type UnvalidateEmailForIdParams struct {
@@ -202,6 +207,7 @@ func (q *Queries) UnvalidateEmailForId(ctx context.Context, arg UnvalidateEmailF
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
@@ -210,7 +216,7 @@ const updateUserEmail = `-- name: UpdateUserEmail :one
 UPDATE users
 SET email = $2, updated_at = $3
 WHERE id = $1
-RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
 `
 
 type UpdateUserEmailParams struct {
@@ -232,6 +238,7 @@ func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
@@ -240,7 +247,7 @@ const updateUserPassword = `-- name: UpdateUserPassword :one
 UPDATE users
 SET password_hash = $2, updated_at = $3
 WHERE id = $1
-RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
 `
 
 type UpdateUserPasswordParams struct {
@@ -262,6 +269,7 @@ func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPassword
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
@@ -270,7 +278,7 @@ const updateUserPfp = `-- name: UpdateUserPfp :one
 UPDATE users
 SET profile_pic_id = $2, updated_at = $3
 WHERE id = $1
-RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
 `
 
 type UpdateUserPfpParams struct {
@@ -292,6 +300,38 @@ func (q *Queries) UpdateUserPfp(ctx context.Context, arg UpdateUserPfpParams) (U
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
+	)
+	return i, err
+}
+
+const updateUserTeacher = `-- name: UpdateUserTeacher :one
+UPDATE users
+SET is_teacher = $2, updated_at = $3
+WHERE id = $1
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
+`
+
+type UpdateUserTeacherParams struct {
+	ID        uuid.UUID
+	IsTeacher bool
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) UpdateUserTeacher(ctx context.Context, arg UpdateUserTeacherParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTeacher, arg.ID, arg.IsTeacher, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.ProfilePicID,
+		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
@@ -300,7 +340,7 @@ const updateUserUsername = `-- name: UpdateUserUsername :one
 UPDATE users
 SET username = $2, updated_at = $3
 WHERE id = $1
-RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
 `
 
 type UpdateUserUsernameParams struct {
@@ -322,6 +362,7 @@ func (q *Queries) UpdateUserUsername(ctx context.Context, arg UpdateUserUsername
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
@@ -330,7 +371,7 @@ const validateEmailForId = `-- name: ValidateEmailForId :one
 UPDATE users
 SET email_validated = TRUE, updated_at = $2
 WHERE id = $1
-RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated
+RETURNING id, username, email, password_hash, created_at, updated_at, is_admin, profile_pic_id, email_validated, is_teacher
 `
 
 type ValidateEmailForIdParams struct {
@@ -351,6 +392,7 @@ func (q *Queries) ValidateEmailForId(ctx context.Context, arg ValidateEmailForId
 		&i.IsAdmin,
 		&i.ProfilePicID,
 		&i.EmailValidated,
+		&i.IsTeacher,
 	)
 	return i, err
 }
@@ -0,0 +1,263 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	// Line indexes in the old and new text, only the ones relevant to kind are meaningful
+	a, b int
+}
+
+// Texts beyond these limits are not diffed, the running time grows with their size times the number of changes
+const (
+	MaxLines = 10000
+	MaxBytes = 2 << 20
+)
+
+var ErrTooLarge = fmt.Errorf("texts are too large to diff, the limit is %d lines or %d bytes", MaxLines, MaxBytes)
+
+// Unified returns a unified diff between two texts, with the given number of context lines around changes.
+// An empty string means the texts are identical.
+func Unified(fromName, toName, from, to string, context int) (string, error) {
+	if len(from) > MaxBytes || len(to) > MaxBytes {
+		return "", ErrTooLarge
+	}
+	a := splitLines(from)
+	b := splitLines(to)
+	if len(a) > MaxLines || len(b) > MaxLines {
+		return "", ErrTooLarge
+	}
+	ops := lineOps(a, b)
+
+	var sb strings.Builder
+	hunks := groupHunks(ops, context)
+	if len(hunks) == 0 {
+		return "", nil
+	}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for _, hunk := range hunks {
+		writeHunk(&sb, hunk, a, b)
+	}
+	return sb.String(), nil
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// differ builds the edit script between a and b in order
+type differ struct {
+	a, b []string
+	ops  []op
+}
+
+// lineOps computes the shortest edit script between a and b with the linear space variant of Myers' O(ND)
+// algorithm: the middle snake of an optimal path splits the texts and both halves are solved recursively
+func lineOps(a, b []string) []op {
+	d := &differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	return deletesFirst(d.ops)
+}
+
+// deletesFirst orders every run of changes so the removed lines come before the added ones, the
+// recursion may emit them interleaved
+func deletesFirst(ops []op) []op {
+	sorted := make([]op, 0, len(ops))
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			sorted = append(sorted, ops[i])
+			i++
+			continue
+		}
+		start := i
+		for i < len(ops) && ops[i].kind != opEqual {
+			i++
+		}
+		aStart, bStart := ops[start].a, ops[start].b
+		aEnd := aStart
+		for _, o := range ops[start:i] {
+			if o.kind == opDelete {
+				sorted = append(sorted, op{kind: opDelete, a: o.a, b: bStart})
+				aEnd = o.a + 1
+			}
+		}
+		for _, o := range ops[start:i] {
+			if o.kind == opInsert {
+				sorted = append(sorted, op{kind: opInsert, a: aEnd, b: o.b})
+			}
+		}
+	}
+	return sorted
+}
+
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, op{kind: opEqual, a: aLo, b: bLo})
+		aLo++
+		bLo++
+	}
+	aEnd, bEnd := aHi, bHi
+	for aLo < aEnd && bLo < bEnd && d.a[aEnd-1] == d.b[bEnd-1] {
+		aEnd--
+		bEnd--
+	}
+
+	switch {
+	case aLo == aEnd:
+		for y := bLo; y < bEnd; y++ {
+			d.ops = append(d.ops, op{kind: opInsert, a: aLo, b: y})
+		}
+	case bLo == bEnd:
+		for x := aLo; x < aEnd; x++ {
+			d.ops = append(d.ops, op{kind: opDelete, a: x, b: bLo})
+		}
+	default:
+		// Both sides still differ at their ends, so the script has at least two edits and both halves are shorter
+		x, y, u, v := d.middleSnake(aLo, aEnd, bLo, bEnd)
+		d.compare(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.ops = append(d.ops, op{kind: opEqual, a: x, b: y})
+		}
+		d.compare(u, aEnd, v, bEnd)
+	}
+
+	for x, y := aEnd, bEnd; x < aHi; x, y = x+1, y+1 {
+		d.ops = append(d.ops, op{kind: opEqual, a: x, b: y})
+	}
+}
+
+// middleSnake runs the search from both ends at once until the paths overlap, it returns the snake
+// (x, y) to (u, v) where they met, which lies on a shortest edit script
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (int, int, int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	// forward[k] is the furthest x reached on diagonal k from the start, backward[k] the furthest
+	// distance from the end on diagonal k of the reversed texts
+	forward := make([]int, 2*maxD+3)
+	backward := make([]int, 2*maxD+3)
+
+	for step := 0; step <= maxD; step++ {
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if reversed := delta - k; odd && reversed >= -(step-1) && reversed <= step-1 && x+backward[offset+reversed] >= n {
+				return aLo + startX, bLo + startY, aLo + x, bLo + y
+			}
+		}
+
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || (k != step && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if ahead := delta - k; !odd && ahead >= -step && ahead <= step && x+forward[offset+ahead] >= n {
+				return aHi - x, bHi - y, aHi - startX, bHi - startY
+			}
+		}
+	}
+	// The paths always meet within maxD steps
+	panic("diff: no middle snake")
+}
+
+// groupHunks splits the edit script into runs of changes surrounded by at most context equal lines
+func groupHunks(ops []op, context int) [][]op {
+	var hunks [][]op
+	start := -1
+	lastChange := -1
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+		if start != -1 && i-lastChange > 2*context+1 {
+			hunks = append(hunks, ops[start:min(lastChange+context+1, len(ops))])
+			start = -1
+		}
+		if start == -1 {
+			start = max(i-context, 0)
+		}
+		lastChange = i
+	}
+	if start != -1 {
+		hunks = append(hunks, ops[start:min(lastChange+context+1, len(ops))])
+	}
+	return hunks
+}
+
+func writeHunk(sb *strings.Builder, hunk []op, a, b []string) {
+	aStart, bStart := hunk[0].a, hunk[0].b
+	aCount, bCount := 0, 0
+	for _, o := range hunk {
+		if o.kind != opInsert {
+			aCount++
+		}
+		if o.kind != opDelete {
+			bCount++
+		}
+	}
+	// Empty ranges point at the line before them, as in GNU diff
+	if aCount > 0 {
+		aStart++
+	}
+	if bCount > 0 {
+		bStart++
+	}
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+
+	for _, o := range hunk {
+		var prefix, line string
+		switch o.kind {
+		case opEqual:
+			prefix, line = " ", a[o.a]
+		case opDelete:
+			prefix, line = "-", a[o.a]
+		case opInsert:
+			prefix, line = "+", b[o.b]
+		}
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
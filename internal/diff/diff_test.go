@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedIdentical(t *testing.T) {
+	if res, _ := Unified("a", "b", "x\ny\n", "x\ny\n", 3); res != "" {
+		t.Errorf("expected no diff, got %q", res)
+	}
+}
+
+func TestUnifiedChange(t *testing.T) {
+	from := "# Structuri\n\nint x;\nint y;\n"
+	to := "# Structuri\n\nint x;\nint z;\nint w;\n"
+	expected := "--- v1\n+++ v2\n@@ -1,4 +1,5 @@\n # Structuri\n \n int x;\n-int y;\n+int z;\n+int w;\n"
+	if res, _ := Unified("v1", "v2", from, to, 3); res != expected {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", res, expected)
+	}
+}
+
+func TestUnifiedSeparateHunks(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	to := "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"
+	expected := "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -9,2 +9,2 @@\n 9\n-10\n+ten\n"
+	if res, _ := Unified("a", "b", from, to, 1); res != expected {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", res, expected)
+	}
+}
+
+func TestUnifiedFromEmpty(t *testing.T) {
+	expected := "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+new\n"
+	if res, _ := Unified("a", "b", "", "new\n", 3); res != expected {
+		t.Errorf("unexpected diff:\n%s\nexpected:\n%s", res, expected)
+	}
+}
+
+func TestUnifiedTooLarge(t *testing.T) {
+	long := strings.Repeat("x\n", MaxLines+1)
+	if _, err := Unified("a", "b", long, "x\n", 3); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge for too many lines, got %v", err)
+	}
+	wide := strings.Repeat("x", MaxBytes+1)
+	if _, err := Unified("a", "b", "x\n", wide, 3); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge for too many bytes, got %v", err)
+	}
+}
+
+// lcsLength is the textbook quadratic solution the edit scripts are checked against
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestLineOpsShortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a\n", "b\n", "c\n", "d\n"}
+	random := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return lines
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, b := random(), random()
+		var fromA, fromB []string
+		equal := 0
+		for _, o := range lineOps(a, b) {
+			switch o.kind {
+			case opEqual:
+				if a[o.a] != b[o.b] {
+					t.Fatalf("%q -> %q: lines %v and %v are not equal", a, b, o.a, o.b)
+				}
+				fromA = append(fromA, a[o.a])
+				fromB = append(fromB, b[o.b])
+				equal++
+			case opDelete:
+				fromA = append(fromA, a[o.a])
+			case opInsert:
+				fromB = append(fromB, b[o.b])
+			}
+		}
+		if strings.Join(fromA, "") != strings.Join(a, "") || strings.Join(fromB, "") != strings.Join(b, "") {
+			t.Fatalf("%q -> %q: edit script does not rebuild the texts", a, b)
+		}
+		if want := lcsLength(a, b); equal != want {
+			t.Fatalf("%q -> %q: script keeps %v lines, the longest common subsequence has %v", a, b, equal, want)
+		}
+	}
+}
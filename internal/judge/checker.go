@@ -0,0 +1,261 @@
+package judge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CheckerMode string
+
+const (
+	// CheckerExact compares line by line, ignoring trailing spaces and trailing empty lines
+	CheckerExact CheckerMode = "exact"
+	// CheckerWhitespace compares the whitespace separated tokens
+	CheckerWhitespace CheckerMode = "whitespace"
+	// CheckerFloat compares tokens, numbers may differ by Epsilon, absolute or relative
+	CheckerFloat CheckerMode = "float"
+	// CheckerCustom runs a checker program written against testlib
+	CheckerCustom CheckerMode = "custom"
+)
+
+const (
+	DefaultEpsilon = 1e-6
+	checkerTimeout = 10 * time.Second
+)
+
+// testlibLimits apply to checkers and validators, they read whole outputs so they get more memory than programs
+var testlibLimits = Limits{TimeMs: int32(checkerTimeout / time.Millisecond), MemoryMb: 1024}
+
+// Exit codes of testlib checkers
+const (
+	testlibOK           = 0
+	testlibWrongAnswer  = 1
+	testlibPresentation = 2
+	testlibFail         = 3
+)
+
+func ValidCheckerMode(mode CheckerMode) bool {
+	return mode == CheckerExact || mode == CheckerWhitespace || mode == CheckerFloat || mode == CheckerCustom
+}
+
+// Checker decides whether the output of a program is a correct answer
+type Checker struct {
+	Mode    CheckerMode
+	Epsilon float64
+	// Source of the custom checker, compiled once and cached by the judge
+	Source  []byte
+	binary  string
+	isolate bool
+}
+
+// OutputsMatch compares outputs line by line, ignoring trailing spaces and trailing empty lines
+func OutputsMatch(expected, actual []byte) bool {
+	normalize := func(b []byte) []string {
+		lines := strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		return lines
+	}
+	e, a := normalize(expected), normalize(actual)
+	if len(e) != len(a) {
+		return false
+	}
+	for i := range e {
+		if e[i] != a[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TokensMatch compares whitespace separated tokens, numeric tokens within epsilon when epsilon is positive
+func TokensMatch(expected, actual []byte, epsilon float64) (bool, string) {
+	e, a := strings.Fields(string(expected)), strings.Fields(string(actual))
+	for i := range e {
+		if i >= len(a) {
+			return false, fmt.Sprintf("expected %v tokens, found %v", len(e), len(a))
+		}
+		if e[i] == a[i] {
+			continue
+		}
+		if epsilon > 0 {
+			want, errWant := strconv.ParseFloat(e[i], 64)
+			got, errGot := strconv.ParseFloat(a[i], 64)
+			if errWant == nil && errGot == nil && !math.IsNaN(got) {
+				diff := math.Abs(want - got)
+				if diff <= epsilon || diff <= epsilon*math.Abs(want) {
+					continue
+				}
+			}
+		}
+		return false, fmt.Sprintf("token %v differs", i+1)
+	}
+	if len(a) > len(e) {
+		return false, fmt.Sprintf("expected %v tokens, found %v", len(e), len(a))
+	}
+	return true, ""
+}
+
+// PrepareChecker compiles a custom checker, binaries are cached in CheckerDir by source hash
+func (j *Judge) PrepareChecker(ctx context.Context, checker *Checker) (string, error) {
+	if checker.Mode != CheckerCustom || checker.binary != "" {
+		return "", nil
+	}
+	if len(checker.Source) == 0 {
+		return "", fmt.Errorf("custom checker has no source")
+	}
+	binary, output, err := j.compileTestlib(ctx, checker.Source)
+	if err != nil {
+		return output, err
+	}
+	if output != "" {
+		return output, fmt.Errorf("checker does not compile")
+	}
+	checker.binary = binary
+	checker.isolate = j.Isolate
+	return "", nil
+}
+
+// compileTestlib builds a C++ helper program such as a checker or a validator and caches the binary in
+// CheckerDir by source hash. A non-empty output without an error means the source does not compile.
+func (j *Judge) compileTestlib(ctx context.Context, source []byte) (string, string, error) {
+	// Helpers run from the test directory, relative paths would not resolve there
+	checkerDir, err := filepath.Abs(j.CheckerDir)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid checker directory: %v", err)
+	}
+	sum := sha256.Sum256(source)
+	dir := filepath.Join(checkerDir, hex.EncodeToString(sum[:]))
+	binary := filepath.Join(dir, "main")
+	if _, err := os.Stat(binary); err == nil {
+		return binary, "", nil
+	}
+
+	// Compile next to the cache and move the result in, concurrent evaluations never see a partial binary
+	err = os.MkdirAll(checkerDir, 0755)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create checker directory: %v", err)
+	}
+	tmp, err := os.MkdirTemp(checkerDir, "build-")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create checker build directory: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	sourcePath := filepath.Join(tmp, "main.cpp")
+	err = os.WriteFile(sourcePath, source, 0644)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write checker source: %v", err)
+	}
+	includeDirs := j.includeDirs()
+	args := append([]string{j.Compiler}, j.Flags...)
+	for _, dir := range includeDirs {
+		args = append(args, "-I", dir)
+	}
+	args = append(args, "-o", filepath.Join(tmp, "main"), sourcePath)
+	output, err := j.compile(ctx, args, tmp, includeDirs)
+	if err != nil || output != "" {
+		return "", output, err
+	}
+	err = os.Rename(tmp, dir)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		if _, statErr := os.Stat(binary); statErr != nil {
+			return "", "", fmt.Errorf("failed to cache checker: %v", err)
+		}
+	}
+	return binary, "", nil
+}
+
+// includeDirs are the absolute IncludeDirs, the jail of the compiler binds them at those paths
+func (j *Judge) includeDirs() []string {
+	dirs := make([]string, 0, len(j.IncludeDirs))
+	for _, dir := range j.IncludeDirs {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// check judges one output, the verdict is accepted, wrong answer or an internal error if the checker failed
+func (c Checker) check(ctx context.Context, test Test, expected []byte, output []byte, outputPath string) (Verdict, string) {
+	switch c.Mode {
+	case CheckerWhitespace:
+		ok, message := TokensMatch(expected, output, 0)
+		return verdictOf(ok), message
+	case CheckerFloat:
+		ok, message := TokensMatch(expected, output, c.Epsilon)
+		return verdictOf(ok), message
+	case CheckerCustom:
+		return c.runCustom(ctx, test, outputPath)
+	default:
+		return verdictOf(OutputsMatch(expected, output)), ""
+	}
+}
+
+func verdictOf(ok bool) Verdict {
+	if ok {
+		return VerdictAccepted
+	}
+	return VerdictWrongAnswer
+}
+
+// runCustom follows the testlib convention: checker <input> <contestant output> <expected answer>.
+// The checker is confined like the programs, it only sees its binary, the test files and the contestant's directory
+func (c Checker) runCustom(ctx context.Context, test Test, outputPath string) (Verdict, string) {
+	if c.binary == "" {
+		return VerdictInternalError, "custom checker was not compiled"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkerTimeout)
+	defer cancel()
+
+	dir := filepath.Dir(outputPath)
+	cmd := limitedCommand(ctx, testlibLimits, []string{c.binary, test.InputPath, outputPath, test.OutputPath})
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=/usr/bin:/bin"}
+	messages := &limitedBuffer{max: maxStderr, silent: true}
+	cmd.Stdout = messages
+	cmd.Stderr = messages
+	finish := sandbox(cmd, newJail(c.isolate, dir, false, c.binary, test.InputPath, test.OutputPath))
+
+	err := cmd.Run()
+	_, sandboxErr := finish()
+	message := strings.TrimSpace(messages.buf.String())
+	if ctx.Err() == context.DeadlineExceeded {
+		return VerdictInternalError, "checker timed out"
+	}
+	if sandboxErr != nil {
+		return VerdictInternalError, fmt.Sprintf("failed to run checker: %v", sandboxErr)
+	}
+	if err == nil {
+		return VerdictAccepted, message
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return VerdictInternalError, fmt.Sprintf("failed to run checker: %v", err)
+	}
+	switch exitErr.ExitCode() {
+	case testlibWrongAnswer, testlibPresentation:
+		return VerdictWrongAnswer, message
+	case testlibFail:
+		return VerdictInternalError, "checker failed: " + message
+	default:
+		return VerdictInternalError, fmt.Sprintf("checker exited with %v: %v", exitErr.ExitCode(), message)
+	}
+}
@@ -0,0 +1,173 @@
+package judge
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTokensMatch(t *testing.T) {
+	cases := []struct {
+		expected, actual string
+		epsilon          float64
+		want             bool
+	}{
+		{"1 2\n3\n", "1\n2 3", 0, true},
+		{"1 2 3", "1 2", 0, false},
+		{"1 2", "1 2 3", 0, false},
+		{"0.333333", "0.3333334", 1e-6, true},
+		{"0.333333", "0.3334", 1e-6, false},
+		{"1000000000.0", "1000000000.5", 1e-6, true},
+		{"YES 1.5", "NO 1.5", 1e-6, false},
+		{"1.5", "nan", 1e-6, false},
+	}
+	for _, c := range cases {
+		if got, _ := TokensMatch([]byte(c.expected), []byte(c.actual), c.epsilon); got != c.want {
+			t.Errorf("TokensMatch(%q, %q, %v) = %v, want %v", c.expected, c.actual, c.epsilon, got, c.want)
+		}
+	}
+}
+
+func TestCheckers(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+
+	dir := t.TempDir()
+	// Any divisor of 12 other than 1 and 12 is a valid answer
+	tests := []Test{writeTest(t, dir, "1", "12\n", "2\n")}
+	prints := func(answer string) []byte {
+		return []byte("#include <cstdio>\nint main(){ puts(\"" + answer + "\"); }")
+	}
+	// Follows the testlib exit codes without needing testlib.h
+	divisorChecker := []byte(`#include <fstream>
+int main(int argc, char** argv){
+    std::ifstream in(argv[1]), out(argv[2]);
+    long long n, d;
+    in >> n;
+    if(!(out >> d)) return 2;
+    return (d > 1 && d < n && n % d == 0) ? 0 : 1;
+}`)
+
+	j := New("g++", false)
+	j.CheckerDir = t.TempDir()
+	cases := map[string]struct {
+		source  []byte
+		checker Checker
+		want    Verdict
+	}{
+		"exact rejects another divisor":   {prints("3"), Checker{Mode: CheckerExact}, VerdictWrongAnswer},
+		"whitespace accepts padding":      {prints("  2  "), Checker{Mode: CheckerWhitespace}, VerdictAccepted},
+		"float accepts close value":       {prints("2.0000001"), Checker{Mode: CheckerFloat, Epsilon: DefaultEpsilon}, VerdictAccepted},
+		"custom accepts another divisor":  {prints("3"), Checker{Mode: CheckerCustom, Source: divisorChecker}, VerdictAccepted},
+		"custom rejects a non divisor":    {prints("5"), Checker{Mode: CheckerCustom, Source: divisorChecker}, VerdictWrongAnswer},
+		"custom checker does not compile": {prints("3"), Checker{Mode: CheckerCustom, Source: []byte("int main(){")}, VerdictInternalError},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			res := j.Evaluate(context.Background(), Submission{Language: LanguageCpp, Source: c.source, Limits: Limits{TimeMs: 1000, MemoryMb: 64}, Tests: tests, Checker: c.checker}, Hooks{})
+			if res.Verdict != c.want {
+				t.Fatalf("verdict %v, want %v (%+v)", res.Verdict, c.want, res)
+			}
+		})
+	}
+}
+
+func TestCheckerJail(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+	if err := CheckIsolation(); err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	secret := filepath.Join(t.TempDir(), "secret.env")
+	if err := os.WriteFile(secret, []byte("SECRET=hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []Test{writeTest(t, dir, "1", "12\n", "2\n")}
+	// Accepts only when it reads its three files as nobody and cannot reach the server's
+	checker := []byte(`#include <cstdio>
+#include <unistd.h>
+int main(int argc, char** argv){
+    for(int i = 1; i <= 3; i++) if(!fopen(argv[i], "r")) return 3;
+    if(fopen("` + secret + `", "r") || fopen(argv[3], "w")) return 1;
+    return getuid() == 65534 ? 0 : 1;
+}`)
+
+	j := New("g++", true)
+	j.CheckerDir = t.TempDir()
+	source := []byte("#include <cstdio>\nint main(){ puts(\"2\"); }")
+	res := j.Evaluate(context.Background(), Submission{Language: LanguageCpp, Source: source, Limits: Limits{TimeMs: 1000, MemoryMb: 64}, Tests: tests, Checker: Checker{Mode: CheckerCustom, Source: checker}}, Hooks{})
+	if res.Verdict != VerdictAccepted {
+		t.Errorf("checker was not jailed: %+v", res)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+
+	j := New("g++", false)
+	j.CheckerDir = t.TempDir()
+	// Accepts a single n with 1 <= n <= 100, the way a testlib validator would
+	validator := &Validator{Source: []byte(`#include <cstdio>
+int main(){
+    int n;
+    if(scanf("%d", &n) != 1 || n < 1 || n > 100){ fprintf(stderr, "n out of range"); return 3; }
+    return 0;
+}`)}
+	output, err := j.PrepareValidator(context.Background(), validator)
+	if err != nil {
+		t.Fatalf("failed to compile validator: %v\n%s", err, output)
+	}
+	if err := validator.Validate(context.Background(), strings.NewReader("42\n")); err != nil {
+		t.Errorf("expected a valid input, got %v", err)
+	}
+	err = validator.Validate(context.Background(), strings.NewReader("1000\n"))
+	if err == nil || !strings.Contains(err.Error(), "n out of range") {
+		t.Errorf("expected the validator's message, got %v", err)
+	}
+
+	broken := &Validator{Source: []byte("int main(){")}
+	if output, err := j.PrepareValidator(context.Background(), broken); err == nil || output == "" {
+		t.Errorf("expected a compilation error with output, got %v", err)
+	}
+}
+
+func TestValidatorJail(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+	if err := CheckIsolation(); err != nil {
+		t.Skip(err)
+	}
+
+	secret := filepath.Join(t.TempDir(), "secret.env")
+	if err := os.WriteFile(secret, []byte("SECRET=hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	j := New("g++", true)
+	j.CheckerDir = t.TempDir()
+	validator := &Validator{Source: []byte(`#include <cstdio>
+#include <unistd.h>
+int main(){
+    int n;
+    if(scanf("%d", &n) != 1) return 3;
+    if(fopen("` + secret + `", "r")){ fprintf(stderr, "read the server"); return 3; }
+    if(getuid() != 65534){ fprintf(stderr, "not nobody"); return 3; }
+    return 0;
+}`)}
+	output, err := j.PrepareValidator(context.Background(), validator)
+	if err != nil {
+		t.Fatalf("failed to compile validator: %v\n%s", err, output)
+	}
+	if err := validator.Validate(context.Background(), strings.NewReader("42\n")); err != nil {
+		t.Errorf("validator was not jailed: %v", err)
+	}
+}
@@ -0,0 +1,430 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Verdict string
+
+const (
+	VerdictAccepted            Verdict = "AC"
+	VerdictWrongAnswer         Verdict = "WA"
+	VerdictTimeLimitExceeded   Verdict = "TLE"
+	VerdictMemoryLimitExceeded Verdict = "MLE"
+	VerdictRuntimeError        Verdict = "RE"
+	VerdictCompilationError    Verdict = "CE"
+	// VerdictInternalError means the judge itself failed, the submission is not at fault
+	VerdictInternalError Verdict = "IE"
+)
+
+const (
+	// MaxOutputBytes caps what a program may print on one test
+	MaxOutputBytes = 64 << 20
+	// maxCompileOutput caps the compiler messages kept for the student
+	maxCompileOutput = 64 << 10
+	maxStderr        = 4 << 10
+	compileTimeout   = 30 * time.Second
+	// Address space needed by the C++ runtime on top of what the program itself uses
+	addressSlackKb = 16 << 10
+	// maxProcesses caps the processes and threads of the sandbox user, forks beyond it fail
+	maxProcesses = 64
+	// maxFileBytes caps any file a program writes, it only has scratch space for temporary files
+	maxFileBytes = 16 << 20
+)
+
+type Limits struct {
+	TimeMs   int32
+	MemoryMb int32
+}
+
+type Test struct {
+	Name       string
+	InputPath  string
+	OutputPath string
+}
+
+type TestResult struct {
+	Name     string  `json:"name"`
+	Verdict  Verdict `json:"verdict"`
+	TimeMs   int64   `json:"time_ms"`
+	MemoryKb int64   `json:"memory_kb"`
+	Message  string  `json:"message,omitempty"`
+}
+
+type Submission struct {
+	// Language is the ID of a language in the registry of the judge
+	Language string
+	Source   []byte
+	Limits   Limits
+	Tests    []Test
+	// Checker judges each output, the zero value compares exactly
+	Checker Checker
+	// StopOnFailure skips the remaining tests after the first one that is not accepted
+	StopOnFailure bool
+}
+
+type Result struct {
+	Verdict       Verdict      `json:"verdict"`
+	CompileOutput string       `json:"compile_output,omitempty"`
+	Tests         []TestResult `json:"tests"`
+	TimeMs        int64        `json:"time_ms"`
+	MemoryKb      int64        `json:"memory_kb"`
+}
+
+// Judge compiles sources and runs them against tests in a sandbox
+type Judge struct {
+	// Compiler and Flags build custom checkers, which are always C++
+	Compiler  string
+	Flags     []string
+	Languages *Languages
+	// WorkDir holds one temporary directory per submission, the system temp directory if empty
+	WorkDir string
+	// Isolate runs compilers and programs in a jail that only sees the system and their work directory,
+	// see sandbox_linux.go
+	Isolate bool
+	// CheckerDir caches compiled custom checkers and validators
+	CheckerDir string
+	// IncludeDirs are searched for headers such as testlib.h when compiling checkers and validators
+	IncludeDirs []string
+}
+
+func New(compiler string, isolate bool) *Judge {
+	return &Judge{
+		Compiler:   compiler,
+		Flags:      []string{"-std=c++17", "-O2", "-pipe"},
+		Languages:  DefaultLanguages(compiler),
+		Isolate:    isolate,
+		CheckerDir: filepath.Join(os.TempDir(), "codium-checkers"),
+	}
+}
+
+// limitedBuffer keeps the first max bytes written to it, overflowing is reported or silently ignored
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	max      int
+	silent   bool
+	overflow bool
+}
+
+var errOutputLimit = errors.New("output limit exceeded")
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	room := b.max - b.buf.Len()
+	if len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.overflow = true
+		if b.silent {
+			return len(p), nil
+		}
+		return 0, errOutputLimit
+	}
+	return b.buf.Write(p)
+}
+
+// jail is the filesystem a sandboxed command sees besides the read-only system: its work directory, bound
+// at the same path, and the read-only paths it needs such as header directories
+type jail struct {
+	Dir      string   `json:"dir"`
+	Writable bool     `json:"writable"`
+	ReadOnly []string `json:"read_only,omitempty"`
+}
+
+// usage is what a sandboxed command used, measured on the command alone
+type usage struct {
+	TimeMs   int64 `json:"time_ms"`
+	MemoryKb int64 `json:"memory_kb"`
+}
+
+// jail confines a command to dir when the judge isolates, nil otherwise
+func (j *Judge) jail(dir string, writable bool, readOnly ...string) *jail {
+	return newJail(j.Isolate, dir, writable, readOnly...)
+}
+
+func newJail(isolate bool, dir string, writable bool, readOnly ...string) *jail {
+	if !isolate {
+		return nil
+	}
+	return &jail{Dir: dir, Writable: writable, ReadOnly: readOnly}
+}
+
+// limitedCommand wraps command in a shell that applies the limits of one run: CPU time, address space, stack,
+// processes and file sizes
+func limitedCommand(ctx context.Context, limits Limits, command []string) *exec.Cmd {
+	// CPU time is enforced by the kernel, a sleeping program is caught by the wall clock of ctx instead
+	cpuSeconds := (limits.TimeMs+999)/1000 + 1
+	memoryKb := int64(limits.MemoryMb) * 1024
+	// sh counts file sizes in 512 byte blocks, dash names the process limit -p where bash uses -u
+	script := fmt.Sprintf(`ulimit -t %d && ulimit -v %d && ulimit -s %d && { ulimit -u %d 2>/dev/null || ulimit -p %d; } && ulimit -f %d && exec "$@"`,
+		cpuSeconds, memoryKb+addressSlackKb, memoryKb, maxProcesses, maxProcesses, maxFileBytes/512)
+	return exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", script, "sh"}, command...)...)
+}
+
+// Program is a compiled submission, Command runs it from Dir
+type Program struct {
+	Dir     string
+	Command []string
+}
+
+// Compile builds source in dir, it returns the program or the compiler output if compilation failed
+func (j *Judge) Compile(ctx context.Context, lang Language, source []byte, dir string) (*Program, string, error) {
+	err := os.WriteFile(filepath.Join(dir, lang.SourceName()), source, 0644)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to write source: %v", err)
+	}
+	if len(lang.Compile) > 0 {
+		output, err := j.compile(ctx, lang.command(lang.Compile, dir), dir, nil)
+		if err != nil || output != "" {
+			return nil, output, err
+		}
+	}
+	return &Program{Dir: dir, Command: lang.command(lang.Run, dir)}, "", nil
+}
+
+// compile runs a compiler in dir, jailed like the programs so that sources cannot include server files.
+// The output is only returned if compilation failed
+func (j *Judge) compile(ctx context.Context, args []string, dir string, includeDirs []string) (string, error) {
+	compileCtx, cancel := context.WithTimeout(ctx, compileTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(compileCtx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=/usr/bin:/bin", "TMPDIR=/tmp"}
+	output := &limitedBuffer{max: maxCompileOutput, silent: true}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	finish := sandbox(cmd, j.jail(dir, true, includeDirs...))
+
+	err := cmd.Run()
+	if _, sandboxErr := finish(); sandboxErr != nil {
+		return "", sandboxErr
+	}
+	// A cancelled evaluation is not the source's fault, only the compiler's own deadline is
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("compilation cancelled: %v", ctx.Err())
+	}
+	if compileCtx.Err() == context.DeadlineExceeded {
+		return "compilation timed out", nil
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			message := strings.ReplaceAll(output.buf.String(), dir+string(filepath.Separator), "")
+			if strings.TrimSpace(message) == "" {
+				message = exitErr.Error()
+			}
+			return message, nil
+		}
+		return "", fmt.Errorf("failed to run compiler: %v", err)
+	}
+	return "", nil
+}
+
+// execution is the outcome of running a program once under the limits
+type execution struct {
+	state    *os.ProcessState
+	stdout   *limitedBuffer
+	stderr   *limitedBuffer
+	timedOut bool
+	timeMs   int64
+	memoryKb int64
+}
+
+// execute runs program once with stdin under limits, the error is only set if the program could not run at all
+func (j *Judge) execute(ctx context.Context, program *Program, stdin io.Reader, limits Limits, maxStdout int, maxStderr int) (execution, error) {
+	wall := time.Duration(limits.TimeMs)*2*time.Millisecond + time.Second
+	runCtx, cancel := context.WithTimeout(ctx, wall)
+	defer cancel()
+
+	cmd := limitedCommand(runCtx, limits, program.Command)
+	cmd.Dir = program.Dir
+	cmd.Env = []string{"PATH=/usr/bin:/bin"}
+	cmd.Stdin = stdin
+	ex := execution{
+		stdout: &limitedBuffer{max: maxStdout},
+		stderr: &limitedBuffer{max: maxStderr, silent: true},
+	}
+	cmd.Stdout = ex.stdout
+	cmd.Stderr = ex.stderr
+	finish := sandbox(cmd, j.jail(program.Dir, false))
+
+	err := cmd.Run()
+	used, sandboxErr := finish()
+	if ctx.Err() != nil {
+		return ex, fmt.Errorf("judging cancelled: %v", ctx.Err())
+	}
+	if sandboxErr != nil {
+		return ex, sandboxErr
+	}
+	if cmd.ProcessState == nil {
+		return ex, fmt.Errorf("failed to start program: %v", err)
+	}
+	ex.state = cmd.ProcessState
+	ex.timedOut = runCtx.Err() == context.DeadlineExceeded
+	ex.timeMs = used.TimeMs
+	ex.memoryKb = used.MemoryKb
+	return ex, nil
+}
+
+// verdict classifies an execution that broke the limits or failed, it is accepted if the program exited normally
+func (ex execution) verdict(limits Limits) (Verdict, string) {
+	switch {
+	case ex.timedOut || ex.timeMs > int64(limits.TimeMs) || strings.Contains(ex.state.String(), "CPU time limit exceeded"):
+		return VerdictTimeLimitExceeded, ""
+	case ex.memoryKb > int64(limits.MemoryMb)*1024 || (!ex.state.Success() && outOfMemory(ex.stderr.buf.String())):
+		return VerdictMemoryLimitExceeded, ""
+	case ex.stdout.overflow:
+		return VerdictRuntimeError, errOutputLimit.Error()
+	case !ex.state.Success():
+		return VerdictRuntimeError, ex.state.String()
+	default:
+		return VerdictAccepted, ""
+	}
+}
+
+// Run executes a program on one test with the limits applied and has checker judge its output
+func (j *Judge) Run(ctx context.Context, program *Program, test Test, limits Limits, checker Checker) TestResult {
+	res := TestResult{Name: test.Name}
+	internalError := func(format string, a ...any) TestResult {
+		res.Verdict = VerdictInternalError
+		res.Message = fmt.Sprintf(format, a...)
+		return res
+	}
+
+	input, err := os.Open(test.InputPath)
+	if err != nil {
+		return internalError("failed to open input: %v", err)
+	}
+	defer input.Close()
+	expected, err := os.ReadFile(test.OutputPath)
+	if err != nil {
+		return internalError("failed to read expected output: %v", err)
+	}
+
+	ex, err := j.execute(ctx, program, input, limits, MaxOutputBytes, maxStderr)
+	if err != nil {
+		return internalError("%v", err)
+	}
+	res.TimeMs = ex.timeMs
+	res.MemoryKb = ex.memoryKb
+	res.Verdict, res.Message = ex.verdict(limits)
+	if res.Verdict != VerdictAccepted {
+		return res
+	}
+
+	outputPath := ""
+	if checker.Mode == CheckerCustom {
+		// Custom checkers read the contestant output from a file
+		outputPath = filepath.Join(program.Dir, "output.txt")
+		err = os.WriteFile(outputPath, ex.stdout.buf.Bytes(), 0644)
+		if err != nil {
+			return internalError("failed to write output for the checker: %v", err)
+		}
+	}
+	res.Verdict, res.Message = checker.check(ctx, test, expected, ex.stdout.buf.Bytes(), outputPath)
+	return res
+}
+
+// outOfMemory recognizes the allocation failures C++ and Python report when the address space runs out
+func outOfMemory(stderr string) bool {
+	return strings.Contains(stderr, "std::bad_alloc") || strings.Contains(stderr, "MemoryError")
+}
+
+// Hooks report the progress of an evaluation, any of them may be nil
+type Hooks struct {
+	// Compiled is called once the source compiled, before the first test runs
+	Compiled func()
+	// Test is called after each test
+	Test func(TestResult)
+}
+
+// workDir creates the temporary directory one program is compiled and run in
+func (j *Judge) workDir() (string, error) {
+	dir, err := os.MkdirTemp(j.WorkDir, "codium-judge-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create work directory: %v", err)
+	}
+	// The jail binds the directory at the same path, which must not depend on the current directory
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to resolve work directory: %v", err)
+	}
+	dir = abs
+	// The sandboxed program may run as another user, it needs to reach its binary or source
+	err = os.Chmod(dir, 0755)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to prepare work directory: %v", err)
+	}
+	return dir, nil
+}
+
+// Evaluate compiles a submission and runs it on every test
+func (j *Judge) Evaluate(ctx context.Context, sub Submission, hooks Hooks) Result {
+	res := Result{Verdict: VerdictAccepted, Tests: []TestResult{}}
+
+	lang, ok := j.Languages.Get(sub.Language)
+	if !ok {
+		res.Verdict = VerdictInternalError
+		res.CompileOutput = fmt.Sprintf("unsupported language %q", sub.Language)
+		return res
+	}
+	limits := lang.Scale(sub.Limits)
+
+	dir, err := j.workDir()
+	if err != nil {
+		res.Verdict = VerdictInternalError
+		res.CompileOutput = err.Error()
+		return res
+	}
+	defer os.RemoveAll(dir)
+
+	program, compileOutput, err := j.Compile(ctx, lang, sub.Source, dir)
+	if err != nil {
+		res.Verdict = VerdictInternalError
+		res.CompileOutput = err.Error()
+		return res
+	}
+	if program == nil {
+		res.Verdict = VerdictCompilationError
+		res.CompileOutput = compileOutput
+		return res
+	}
+	checkerOutput, err := j.PrepareChecker(ctx, &sub.Checker)
+	if err != nil {
+		res.Verdict = VerdictInternalError
+		res.CompileOutput = strings.TrimSpace(err.Error() + "\n" + checkerOutput)
+		return res
+	}
+	if hooks.Compiled != nil {
+		hooks.Compiled()
+	}
+
+	for _, test := range sub.Tests {
+		result := j.Run(ctx, program, test, limits, sub.Checker)
+		res.Tests = append(res.Tests, result)
+		res.TimeMs = max(res.TimeMs, result.TimeMs)
+		res.MemoryKb = max(res.MemoryKb, result.MemoryKb)
+		if hooks.Test != nil {
+			hooks.Test(result)
+		}
+		if result.Verdict != VerdictAccepted {
+			if res.Verdict == VerdictAccepted {
+				res.Verdict = result.Verdict
+			}
+			if sub.StopOnFailure || result.Verdict == VerdictInternalError {
+				break
+			}
+		}
+	}
+	return res
+}
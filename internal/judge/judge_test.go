@@ -0,0 +1,184 @@
+package judge
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputsMatch(t *testing.T) {
+	cases := []struct {
+		expected, actual string
+		want             bool
+	}{
+		{"3\n", "3", true},
+		{"1 2\n3\n", "1 2   \r\n3\n\n\n", true},
+		{"1 2\n", "1  2\n", false},
+		{"1\n2\n", "1\n", false},
+		{"", "\n", true},
+	}
+	for _, c := range cases {
+		if got := OutputsMatch([]byte(c.expected), []byte(c.actual)); got != c.want {
+			t.Errorf("OutputsMatch(%q, %q) = %v, want %v", c.expected, c.actual, got, c.want)
+		}
+	}
+}
+
+func writeTest(t *testing.T, dir, name, input, output string) Test {
+	t.Helper()
+	test := Test{Name: name, InputPath: filepath.Join(dir, name+".in"), OutputPath: filepath.Join(dir, name+".out")}
+	if err := os.WriteFile(test.InputPath, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(test.OutputPath, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return test
+}
+
+func TestEvaluate(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+
+	dir := t.TempDir()
+	tests := []Test{
+		writeTest(t, dir, "1", "1 2\n", "3\n"),
+		writeTest(t, dir, "2", "20 22\n", "42\n"),
+	}
+	limits := Limits{TimeMs: 1000, MemoryMb: 64}
+
+	cases := map[string]struct {
+		source string
+		want   Verdict
+	}{
+		"accepted":      {"#include <iostream>\nint main(){long long a,b;std::cin>>a>>b;std::cout<<a+b<<\"\\n\";}", VerdictAccepted},
+		"wrong answer":  {"#include <iostream>\nint main(){long long a,b;std::cin>>a>>b;std::cout<<a-b<<\"\\n\";}", VerdictWrongAnswer},
+		"compile error": {"int main(){ return }", VerdictCompilationError},
+		"runtime error": {"#include <cstdlib>\nint main(){ std::abort(); }", VerdictRuntimeError},
+		"time limit":    {"int main(){ volatile unsigned long long x=0; for(;;) x++; }", VerdictTimeLimitExceeded},
+		"memory limit":  {"#include <vector>\n#include <iostream>\nint main(){ std::vector<char> v(512u<<20, 1); std::cout<<int(v[12345]); }", VerdictMemoryLimitExceeded},
+	}
+
+	j := New("g++", false)
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var seen int
+			compiled := false
+			hooks := Hooks{Compiled: func() { compiled = true }, Test: func(TestResult) { seen++ }}
+			res := j.Evaluate(context.Background(), Submission{Language: LanguageCpp, Source: []byte(c.source), Limits: limits, Tests: tests, StopOnFailure: true}, hooks)
+			if res.Verdict != c.want {
+				t.Fatalf("verdict %v, want %v (%+v)", res.Verdict, c.want, res)
+			}
+			if c.want == VerdictCompilationError && res.CompileOutput == "" {
+				t.Error("expected compiler output")
+			}
+			if compiled == (c.want == VerdictCompilationError) {
+				t.Errorf("compiled hook called: %v", compiled)
+			}
+			if c.want == VerdictAccepted && (len(res.Tests) != 2 || seen != 2) {
+				t.Errorf("expected both tests to run, got %+v", res.Tests)
+			}
+			if c.want != VerdictAccepted && c.want != VerdictCompilationError && len(res.Tests) != 1 {
+				t.Errorf("expected judging to stop after the first failure, got %+v", res.Tests)
+			}
+		})
+	}
+}
+
+func TestEvaluateIsolated(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+
+	dir := t.TempDir()
+	tests := []Test{writeTest(t, dir, "1", "", "ok\n")}
+	// The sandbox has no network, so the program cannot reach anything
+	source := "#include <cstdio>\n#include <sys/socket.h>\n#include <netinet/in.h>\n#include <arpa/inet.h>\n" +
+		"int main(){ int s=socket(AF_INET,SOCK_STREAM,0); sockaddr_in a{}; a.sin_family=AF_INET; a.sin_port=htons(53); inet_pton(AF_INET,\"1.1.1.1\",&a.sin_addr);" +
+		" if(connect(s,(sockaddr*)&a,sizeof a)!=0) printf(\"ok\\n\"); }"
+
+	res := New("g++", true).Evaluate(context.Background(), Submission{Language: LanguageCpp, Source: []byte(source), Limits: Limits{TimeMs: 1000, MemoryMb: 64}, Tests: tests}, Hooks{})
+	if res.Verdict == VerdictInternalError || (len(res.Tests) == 1 && res.Tests[0].Verdict == VerdictInternalError) {
+		t.Skipf("namespaces are not available here: %+v", res)
+	}
+	if res.Verdict != VerdictAccepted {
+		t.Fatalf("verdict %v, want %v (%+v)", res.Verdict, VerdictAccepted, res)
+	}
+}
+
+func TestJail(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+	if err := CheckIsolation(); err != nil {
+		t.Skip(err)
+	}
+
+	secret := filepath.Join(t.TempDir(), "secret.env")
+	if err := os.WriteFile(secret, []byte("SECRET=hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, err := filepath.Abs("judge.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := New("g++", true)
+	limits := Limits{TimeMs: 1000, MemoryMb: 64}
+
+	// Server files are neither readable nor writable, and the program runs as nobody
+	source := "#include <cstdio>\n#include <unistd.h>\nint main(){" +
+		" if(fopen(\"" + secret + "\",\"r\")) puts(\"secret\");" +
+		" if(fopen(\"" + server + "\",\"r\")) puts(\"server\");" +
+		" if(fopen(\"/usr/leak\",\"w\")) puts(\"system\");" +
+		" if(fopen(\"main\",\"w\")) puts(\"binary\");" +
+		" if(!fopen(\"/tmp/scratch\",\"w\")) puts(\"no scratch\");" +
+		" printf(\"%d\\n\", (int)getuid()); }"
+	res := j.Execute(context.Background(), LanguageCpp, []byte(source), nil, limits)
+	if res.Verdict == VerdictInternalError {
+		t.Skipf("namespaces are not available here: %+v", res)
+	}
+	if res.Verdict != VerdictOK || res.Stdout != "65534\n" {
+		t.Errorf("jailed program saw the host: %+v", res)
+	}
+
+	// The compiler is jailed too, including a server file fails without printing it
+	res = j.Execute(context.Background(), LanguageCpp, []byte("#include \""+secret+"\"\nint main(){}"), nil, limits)
+	if res.Verdict != VerdictCompilationError || strings.Contains(res.CompileOutput, "hunter2") {
+		t.Errorf("compiler read a server file: %+v", res)
+	}
+
+	// Forks beyond the process limit fail instead of taking the host down
+	forks := "#include <cstdio>\n#include <unistd.h>\nint main(){ int n=0; for(int i=0;i<1000;i++){ pid_t p=fork(); if(p==0){ pause(); _exit(0);} if(p<0) break; n++; } printf(\"%d\\n\", n<1000); }"
+	res = j.Execute(context.Background(), LanguageCpp, []byte(forks), nil, limits)
+	if res.Stdout != "1\n" {
+		t.Errorf("fork bomb was not contained: %+v", res)
+	}
+}
+
+func TestMemoryExcludesServer(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+
+	// Programs are started from the server, whose own memory must not count against them
+	ballast := make([]byte, 128<<20)
+	for i := range ballast {
+		ballast[i] = 1
+	}
+	limits := Limits{TimeMs: 1000, MemoryMb: 64}
+	for _, isolate := range []bool{false, true} {
+		if isolate && CheckIsolation() != nil {
+			continue
+		}
+		res := New("g++", isolate).Execute(context.Background(), LanguageCpp, []byte("#include <cstdio>\nint main(){ puts(\"ok\"); }"), nil, limits)
+		if res.Verdict != VerdictOK || res.MemoryKb <= 0 || res.MemoryKb > 32<<10 {
+			t.Errorf("isolate %v: program reported %v KB: %+v", isolate, res.MemoryKb, res)
+		}
+	}
+	ballast[len(ballast)-1]++
+}
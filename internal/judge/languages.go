@@ -0,0 +1,203 @@
+package judge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Language IDs of the built-in registry
+const (
+	LanguageCpp    = "cpp"
+	LanguageC      = "c"
+	LanguagePython = "python"
+)
+
+// Placeholders expanded in compile and run commands
+const (
+	placeholderSource = "{source}"
+	placeholderBinary = "{binary}"
+	placeholderDir    = "{dir}"
+)
+
+const maxTimeMultiplier = 10
+
+var (
+	languageIDPattern  = regexp.MustCompile(`^[a-z0-9_+-]{1,20}$`)
+	extensionPattern   = regexp.MustCompile(`^[a-z0-9]{1,10}$`)
+	defaultCompileArgs = []string{"-O2", "-pipe", "-o", placeholderBinary, placeholderSource}
+)
+
+// Language describes how to build and run one source language, commands are argument lists where
+// {source}, {binary} and {dir} are replaced with paths inside the work directory
+type Language struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Extension string `json:"extension"`
+	// Compile is empty for languages that run from source
+	Compile []string `json:"compile"`
+	Run     []string `json:"run"`
+	// TimeMultiplier scales the time limit of a problem, slower languages get more time
+	TimeMultiplier float64 `json:"time_multiplier"`
+}
+
+// Validate checks a language and fills in the default time multiplier
+func (l *Language) Validate() error {
+	if !languageIDPattern.MatchString(l.ID) {
+		return fmt.Errorf("invalid language id %q", l.ID)
+	}
+	if strings.TrimSpace(l.Name) == "" {
+		return fmt.Errorf("language %v has no name", l.ID)
+	}
+	if !extensionPattern.MatchString(l.Extension) {
+		return fmt.Errorf("language %v has an invalid extension %q", l.ID, l.Extension)
+	}
+	if len(l.Run) == 0 || l.Run[0] == "" {
+		return fmt.Errorf("language %v has no run command", l.ID)
+	}
+	if len(l.Compile) > 0 && l.Compile[0] == "" {
+		return fmt.Errorf("language %v has an empty compile command", l.ID)
+	}
+	if l.TimeMultiplier == 0 {
+		l.TimeMultiplier = 1
+	}
+	if l.TimeMultiplier < 1 || l.TimeMultiplier > maxTimeMultiplier {
+		return fmt.Errorf("language %v has a time multiplier outside 1-%v", l.ID, maxTimeMultiplier)
+	}
+	return nil
+}
+
+// SourceName is the file the submission is written to
+func (l Language) SourceName() string {
+	return "main." + l.Extension
+}
+
+// Scale applies the time multiplier to the limits of a problem
+func (l Language) Scale(limits Limits) Limits {
+	multiplier := max(l.TimeMultiplier, 1)
+	limits.TimeMs = int32(float64(limits.TimeMs) * multiplier)
+	return limits
+}
+
+// command expands the placeholders of args for a work directory
+func (l Language) command(args []string, dir string) []string {
+	replacer := strings.NewReplacer(
+		placeholderSource, filepath.Join(dir, l.SourceName()),
+		placeholderBinary, filepath.Join(dir, "main"),
+		placeholderDir, dir,
+	)
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = replacer.Replace(arg)
+	}
+	return expanded
+}
+
+// Languages is the registry of languages the judge accepts, in the order they are offered to students
+type Languages struct {
+	list []Language
+	byID map[string]Language
+}
+
+// NewLanguages validates and registers languages, IDs and extensions must be unique
+func NewLanguages(languages ...Language) (*Languages, error) {
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no languages configured")
+	}
+	registry := &Languages{byID: make(map[string]Language)}
+	extensions := make(map[string]string)
+	for _, lang := range languages {
+		err := lang.Validate()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := registry.byID[lang.ID]; ok {
+			return nil, fmt.Errorf("language %v is configured twice", lang.ID)
+		}
+		if other, ok := extensions[lang.Extension]; ok {
+			return nil, fmt.Errorf("languages %v and %v share the extension %v", other, lang.ID, lang.Extension)
+		}
+		extensions[lang.Extension] = lang.ID
+		registry.byID[lang.ID] = lang
+		registry.list = append(registry.list, lang)
+	}
+	return registry, nil
+}
+
+// DefaultLanguages is the registry used without a config file: C++ built with cxx, C and Python 3
+func DefaultLanguages(cxx string) *Languages {
+	registry, err := NewLanguages(
+		Language{
+			ID:             LanguageCpp,
+			Name:           "C++17",
+			Extension:      "cpp",
+			Compile:        append([]string{cxx, "-std=c++17"}, defaultCompileArgs...),
+			Run:            []string{placeholderBinary},
+			TimeMultiplier: 1,
+		},
+		Language{
+			ID:             LanguageC,
+			Name:           "C11",
+			Extension:      "c",
+			Compile:        append(append([]string{"gcc", "-std=c11"}, defaultCompileArgs...), "-lm"),
+			Run:            []string{placeholderBinary},
+			TimeMultiplier: 1,
+		},
+		Language{
+			ID:        LanguagePython,
+			Name:      "Python 3",
+			Extension: "py",
+			// Byte-compiling reports syntax errors as compilation errors before any test runs
+			Compile:        []string{"python3", "-m", "py_compile", placeholderSource},
+			Run:            []string{"python3", placeholderSource},
+			TimeMultiplier: 3,
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// LoadLanguages reads a registry from a JSON file holding a list of languages, for example
+//
+//	[{"id": "cpp", "name": "C++17", "extension": "cpp",
+//	  "compile": ["g++", "-std=c++17", "-O2", "-o", "{binary}", "{source}"],
+//	  "run": ["{binary}"], "time_multiplier": 1}]
+func LoadLanguages(path string) (*Languages, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read language config: %v", err)
+	}
+	var languages []Language
+	err = json.Unmarshal(data, &languages)
+	if err != nil {
+		return nil, fmt.Errorf("invalid language config: %v", err)
+	}
+	return NewLanguages(languages...)
+}
+
+// Get looks a language up by ID
+func (r *Languages) Get(id string) (Language, bool) {
+	lang, ok := r.byID[id]
+	return lang, ok
+}
+
+// ByExtension finds the language of a file name
+func (r *Languages) ByExtension(name string) (Language, bool) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	for _, lang := range r.list {
+		if lang.Extension == ext {
+			return lang, true
+		}
+	}
+	return Language{}, false
+}
+
+// List returns the languages in registry order
+func (r *Languages) List() []Language {
+	return append([]Language{}, r.list...)
+}
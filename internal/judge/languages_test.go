@@ -0,0 +1,85 @@
+package judge
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadLanguages(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]struct {
+		config string
+		ok     bool
+	}{
+		"valid":               {`[{"id": "py", "name": "Python", "extension": "py", "run": ["python3", "{source}"]}]`, true},
+		"empty":               {`[]`, false},
+		"no run command":      {`[{"id": "py", "name": "Python", "extension": "py"}]`, false},
+		"bad id":              {`[{"id": "Py thon", "name": "Python", "extension": "py", "run": ["python3"]}]`, false},
+		"slow multiplier":     {`[{"id": "py", "name": "Python", "extension": "py", "run": ["python3"], "time_multiplier": 50}]`, false},
+		"duplicate id":        {`[{"id": "c", "name": "C", "extension": "c", "run": ["{binary}"]}, {"id": "c", "name": "C", "extension": "h", "run": ["{binary}"]}]`, false},
+		"duplicate extension": {`[{"id": "c", "name": "C", "extension": "c", "run": ["{binary}"]}, {"id": "c11", "name": "C11", "extension": "c", "run": ["{binary}"]}]`, false},
+	}
+	for name, c := range cases {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(c.config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		registry, err := LoadLanguages(path)
+		if (err == nil) != c.ok {
+			t.Errorf("%v: error %v, want ok %v", name, err, c.ok)
+			continue
+		}
+		if c.ok {
+			lang, _ := registry.Get("py")
+			if lang.TimeMultiplier != 1 {
+				t.Errorf("%v: time multiplier %v, want the default of 1", name, lang.TimeMultiplier)
+			}
+		}
+	}
+}
+
+func TestDefaultLanguages(t *testing.T) {
+	registry := DefaultLanguages("g++")
+	if lang, ok := registry.ByExtension("solution.PY"); !ok || lang.ID != LanguagePython {
+		t.Errorf("ByExtension(solution.PY) = %v, %v", lang.ID, ok)
+	}
+	lang, _ := registry.Get(LanguagePython)
+	if got := lang.Scale(Limits{TimeMs: 1000, MemoryMb: 64}); got.TimeMs != 3000 || got.MemoryMb != 64 {
+		t.Errorf("Scale = %+v", got)
+	}
+	if got := lang.command(lang.Run, "/work"); got[1] != "/work/main.py" {
+		t.Errorf("run command %v", got)
+	}
+}
+
+func TestEvaluateLanguages(t *testing.T) {
+	dir := t.TempDir()
+	tests := []Test{writeTest(t, dir, "1", "1 2\n", "3\n")}
+	cases := []struct {
+		language, tool, source string
+		want                   Verdict
+	}{
+		{LanguageC, "gcc", "#include <stdio.h>\nint main(void){long long a,b;scanf(\"%lld %lld\",&a,&b);printf(\"%lld\\n\",a+b);return 0;}", VerdictAccepted},
+		{LanguageC, "gcc", "int main(void){ return }", VerdictCompilationError},
+		{LanguagePython, "python3", "a, b = map(int, input().split())\nprint(a + b)\n", VerdictAccepted},
+		{LanguagePython, "python3", "print(1 +\n", VerdictCompilationError},
+		{LanguagePython, "python3", "raise SystemExit(3)\n", VerdictRuntimeError},
+		{LanguagePython, "python3", "x = bytearray(512 << 20)\nprint(len(x))\n", VerdictMemoryLimitExceeded},
+		{"brainfuck", "sh", "+", VerdictInternalError},
+	}
+
+	j := New("g++", false)
+	for _, c := range cases {
+		if _, err := exec.LookPath(c.tool); err != nil {
+			t.Logf("%v is not installed, skipping %v", c.tool, c.language)
+			continue
+		}
+		res := j.Evaluate(context.Background(), Submission{Language: c.language, Source: []byte(c.source), Limits: Limits{TimeMs: 1000, MemoryMb: 64}, Tests: tests}, Hooks{})
+		if res.Verdict != c.want {
+			t.Errorf("%v %q: verdict %v, want %v (%+v)", c.language, c.source, res.Verdict, c.want, res)
+		}
+	}
+}
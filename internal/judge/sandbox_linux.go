@@ -0,0 +1,335 @@
+//go:build linux
+
+package judge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// nobody owns nothing on the host, and inside the jail the server files are not even visible to it
+const nobody = 65534
+
+// sandboxInit is the name the server re-executes itself under to run a command. The fresh process starts the
+// command as its child, so the command's peak memory does not include the memory of the server it came from.
+const sandboxInit = "codium-sandbox"
+
+const (
+	// jailRoot is where the root of a jail is mounted, in the jail's own mount namespace
+	jailRoot = "/tmp/codium-jail"
+	// jailTmpSize bounds the scratch space of a jail, compilers need some for their temporary files
+	jailTmpSize = "32m"
+	// reportFd receives the report of the sandbox, what the command used or why it could not run
+	reportFd = 3
+
+	prSetNoNewPrivs  = 38
+	mountReadOnly    = syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY | syscall.MS_NOSUID | syscall.MS_NODEV
+	mountScratchOpts = syscall.MS_NOSUID | syscall.MS_NODEV
+)
+
+// jailSystem is the read-only part of the host a jail sees, what compilers and interpreters need to run.
+// Paths missing on the host are skipped.
+var jailSystem = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/libx32", "/etc/alternatives", "/etc/ld.so.cache"}
+
+// jailDevices are bound into /dev of a jail
+var jailDevices = []string{"/dev/null", "/dev/zero", "/dev/random", "/dev/urandom"}
+
+var errIsolationNeedsRoot = errors.New("isolation needs the server to run as root, set JUDGE_ISOLATE=false to run without it")
+
+// sandboxReport is written by the sandbox process once its command finished
+type sandboxReport struct {
+	Error string `json:"error,omitempty"`
+	usage
+}
+
+func init() {
+	if len(os.Args) < 4 || os.Args[0] != sandboxInit {
+		return
+	}
+	state, used, err := runSandboxed(os.Args[1], os.Args[2], os.Args[3:])
+	report := sandboxReport{usage: used}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	reportPipe := os.NewFile(reportFd, "sandbox report")
+	json.NewEncoder(reportPipe).Encode(report)
+	reportPipe.Close()
+	if err != nil {
+		os.Exit(1)
+	}
+	exitLike(state)
+}
+
+// CheckIsolation reports whether programs can be isolated on this host
+func CheckIsolation() error {
+	if os.Getuid() != 0 {
+		return errIsolationNeedsRoot
+	}
+	return nil
+}
+
+// sandbox runs cmd through a fresh copy of the server, in its own process group so that killing the command kills
+// its forks too. With a jail that copy runs in new mount, network, IPC and UTS namespaces, pivots into a minimal
+// read-only root and starts the command as nobody in a new PID namespace, see enterJail.
+// The returned function is called once cmd finished, it kills the forks the command left behind and returns what
+// the command itself used. A sandbox that was killed before reporting reports nothing.
+func sandbox(cmd *exec.Cmd, jail *jail) func() (usage, error) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	// The sandbox process kills the command when asked to stop, and still reports what it used
+	cmd.Cancel = func() error {
+		return syscall.Kill(cmd.Process.Pid, syscall.SIGTERM)
+	}
+	// Leftover forks keep the output pipes open, Wait gives up on them after this delay and kills the sandbox
+	cmd.WaitDelay = time.Second
+	killLeftovers := func() {
+		if cmd.Process != nil {
+			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		}
+	}
+	failed := func(err error) func() (usage, error) {
+		cmd.Err = err
+		return func() (usage, error) {
+			killLeftovers()
+			return usage{}, nil
+		}
+	}
+
+	if jail != nil {
+		err := CheckIsolation()
+		if err != nil {
+			return failed(err)
+		}
+		cmd.SysProcAttr.Cloneflags = syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+	}
+	config, err := json.Marshal(jail)
+	if err != nil {
+		return failed(fmt.Errorf("failed to describe jail: %v", err))
+	}
+	reportRead, reportWrite, err := os.Pipe()
+	if err != nil {
+		return failed(fmt.Errorf("failed to create sandbox pipe: %v", err))
+	}
+
+	cmd.ExtraFiles = []*os.File{reportWrite}
+	cmd.Args = append([]string{sandboxInit, string(config), cmd.Path}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+	return func() (usage, error) {
+		killLeftovers()
+		reportWrite.Close()
+		defer reportRead.Close()
+		var report sandboxReport
+		err := json.NewDecoder(io.LimitReader(reportRead, maxStderr)).Decode(&report)
+		if err != nil {
+			return usage{}, nil
+		}
+		if report.Error != "" {
+			return usage{}, fmt.Errorf("failed to run sandbox: %v", report.Error)
+		}
+		return report.usage, nil
+	}
+}
+
+// runSandboxed runs in the re-executed server, it enters the jail if there is one, runs the command as its child
+// and measures it
+func runSandboxed(config string, path string, args []string) (*os.ProcessState, usage, error) {
+	var j *jail
+	err := json.Unmarshal([]byte(config), &j)
+	if err != nil {
+		return nil, usage{}, fmt.Errorf("invalid jail: %v", err)
+	}
+	syscall.CloseOnExec(reportFd)
+
+	cmd := &exec.Cmd{Path: path, Args: args, Env: os.Environ(), Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	// The death signal and the flag below belong to the thread that starts the command
+	runtime.LockOSThread()
+	if j != nil {
+		err = enterJail(j)
+		if err != nil {
+			return nil, usage{}, err
+		}
+		// As the first process of its PID namespace, the command takes all its forks down when it exits
+		cmd.SysProcAttr.Cloneflags = syscall.CLONE_NEWPID
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: nobody, Gid: nobody}
+		_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0)
+		if errno != 0 {
+			return nil, usage{}, fmt.Errorf("failed to forbid new privileges: %v", errno)
+		}
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM)
+	err = cmd.Start()
+	if err != nil {
+		return nil, usage{}, fmt.Errorf("failed to run %v: %v", path, err)
+	}
+	go func() {
+		<-stop
+		cmd.Process.Kill()
+	}()
+	// The exit status is passed on as it is, see exitLike
+	cmd.Wait()
+	used := usage{TimeMs: (cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()).Milliseconds()}
+	if rusage, ok := cmd.ProcessState.SysUsage().(*syscall.Rusage); ok {
+		// Maxrss is reported in kilobytes on Linux
+		used.MemoryKb = rusage.Maxrss
+	}
+	return cmd.ProcessState, used, nil
+}
+
+// exitLike ends the sandbox process the way its command ended, so that the server sees the command's exit status
+func exitLike(state *os.ProcessState) {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if ok && status.Signaled() {
+		// The Go runtime handles most signals itself, the default action is restored so that the signal
+		// terminates the process. A zeroed struct sigaction is SIG_DFL without flags on every architecture.
+		var action [4]uint64
+		syscall.RawSyscall6(syscall.SYS_RT_SIGACTION, uintptr(status.Signal()), uintptr(unsafe.Pointer(&action)), 0, 8, 0, 0)
+		syscall.Setrlimit(syscall.RLIMIT_CORE, &syscall.Rlimit{})
+		syscall.Kill(os.Getpid(), status.Signal())
+		time.Sleep(time.Second)
+	}
+	os.Exit(state.ExitCode())
+}
+
+// enterJail builds the jail root on a tmpfs in the namespaces created by sandbox, pivots into it and enters the
+// work directory. The sandbox process stays root, only the command it starts runs as nobody.
+func enterJail(j *jail) error {
+	// Nothing mounted here may leak back to the host
+	err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, "")
+	if err != nil {
+		return fmt.Errorf("failed to make mounts private: %v", err)
+	}
+	err = os.MkdirAll(jailRoot, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create jail root: %v", err)
+	}
+	err = syscall.Mount("tmpfs", jailRoot, "tmpfs", mountScratchOpts, "size=1m,mode=755")
+	if err != nil {
+		return fmt.Errorf("failed to mount jail root: %v", err)
+	}
+
+	for _, dir := range jailSystem {
+		err = bindIntoJail(dir, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, device := range jailDevices {
+		err = bindIntoJail(device, true)
+		if err != nil {
+			return err
+		}
+	}
+	// /tmp comes before the work directory, which usually lives under it on the host
+	tmp := filepath.Join(jailRoot, "tmp")
+	err = os.Mkdir(tmp, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create /tmp: %v", err)
+	}
+	err = syscall.Mount("tmpfs", tmp, "tmpfs", mountScratchOpts, "size="+jailTmpSize+",mode=1777")
+	if err != nil {
+		return fmt.Errorf("failed to mount /tmp: %v", err)
+	}
+	for _, dir := range j.ReadOnly {
+		err = bindIntoJail(dir, false)
+		if err != nil {
+			return err
+		}
+	}
+	err = bindIntoJail(j.Dir, j.Writable)
+	if err != nil {
+		return err
+	}
+	if j.Writable {
+		err = os.Chown(j.Dir, nobody, nobody)
+		if err != nil {
+			return fmt.Errorf("failed to hand the work directory over: %v", err)
+		}
+	}
+	err = syscall.Mount("", jailRoot, "", syscall.MS_REMOUNT|syscall.MS_RDONLY|mountScratchOpts, "")
+	if err != nil {
+		return fmt.Errorf("failed to make jail root read-only: %v", err)
+	}
+
+	// Stacking the old root under the new one and detaching it leaves nothing of the host reachable
+	err = os.Chdir(jailRoot)
+	if err != nil {
+		return fmt.Errorf("failed to enter jail root: %v", err)
+	}
+	err = syscall.PivotRoot(".", ".")
+	if err != nil {
+		return fmt.Errorf("failed to pivot into jail: %v", err)
+	}
+	err = syscall.Unmount(".", syscall.MNT_DETACH)
+	if err != nil {
+		return fmt.Errorf("failed to detach host root: %v", err)
+	}
+	err = os.Chdir(j.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to enter work directory: %v", err)
+	}
+
+	return nil
+}
+
+// bindIntoJail makes a host path visible at the same path inside the jail root. Symbolic links such as /bin on
+// merged /usr systems are copied as links, other paths are bind mounted read-only unless writable is set.
+func bindIntoJail(path string, writable bool) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect %v: %v", path, err)
+	}
+	target := filepath.Join(jailRoot, path)
+	err = os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create parent of %v: %v", path, err)
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(path)
+		if err != nil {
+			return fmt.Errorf("failed to read link %v: %v", path, err)
+		}
+		err = os.Symlink(link, target)
+		if err != nil {
+			return fmt.Errorf("failed to copy link %v: %v", path, err)
+		}
+		return nil
+	case info.IsDir():
+		err = os.Mkdir(target, 0755)
+	default:
+		err = os.WriteFile(target, nil, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create mount point for %v: %v", path, err)
+	}
+
+	err = syscall.Mount(path, target, "", syscall.MS_BIND|syscall.MS_REC, "")
+	if err != nil {
+		return fmt.Errorf("failed to bind %v: %v", path, err)
+	}
+	flags := uintptr(mountReadOnly)
+	if writable {
+		flags = syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_NOSUID
+	}
+	err = syscall.Mount("", target, "", flags, "")
+	if err != nil {
+		return fmt.Errorf("failed to restrict %v: %v", path, err)
+	}
+	return nil
+}
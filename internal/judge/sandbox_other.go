@@ -0,0 +1,29 @@
+//go:build !linux
+
+package judge
+
+import (
+	"errors"
+	"os/exec"
+)
+
+var errIsolationUnsupported = errors.New("isolation is only available on Linux, set JUDGE_ISOLATE=false to run without it")
+
+// CheckIsolation reports whether programs can be isolated on this host
+func CheckIsolation() error {
+	return errIsolationUnsupported
+}
+
+// sandbox has no isolation outside Linux, the ulimit wrapper is all that limits the program. Jailed commands fail to start.
+// Memory use is unknown, limits are still enforced through ulimit
+func sandbox(cmd *exec.Cmd, jail *jail) func() (usage, error) {
+	if jail != nil {
+		cmd.Err = errIsolationUnsupported
+	}
+	return func() (usage, error) {
+		if cmd.ProcessState == nil {
+			return usage{}, nil
+		}
+		return usage{TimeMs: (cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()).Milliseconds()}, nil
+	}
+}
@@ -0,0 +1,75 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+)
+
+const (
+	// MaxScratchOutput caps what a scratch run may print, it is returned to the browser as is
+	MaxScratchOutput = 64 << 10
+	// VerdictOK ends a scratch run that exited normally, there is no expected output to compare with
+	VerdictOK Verdict = "OK"
+)
+
+// ScratchResult is the outcome of running code once on input the student typed
+type ScratchResult struct {
+	Verdict       Verdict `json:"verdict"`
+	CompileOutput string  `json:"compile_output,omitempty"`
+	Stdout        string  `json:"stdout"`
+	Stderr        string  `json:"stderr"`
+	// ExitCode is -1 if the program was killed by a signal
+	ExitCode int    `json:"exit_code"`
+	TimeMs   int64  `json:"time_ms"`
+	MemoryKb int64  `json:"memory_kb"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Execute compiles source and runs it once on stdin in the same sandbox as Evaluate
+func (j *Judge) Execute(ctx context.Context, language string, source []byte, stdin []byte, limits Limits) ScratchResult {
+	res := ScratchResult{ExitCode: -1}
+	internalError := func(err error) ScratchResult {
+		res.Verdict = VerdictInternalError
+		res.Message = err.Error()
+		return res
+	}
+
+	lang, ok := j.Languages.Get(language)
+	if !ok {
+		return internalError(fmt.Errorf("unsupported language %q", language))
+	}
+	limits = lang.Scale(limits)
+
+	dir, err := j.workDir()
+	if err != nil {
+		return internalError(err)
+	}
+	defer os.RemoveAll(dir)
+
+	program, compileOutput, err := j.Compile(ctx, lang, source, dir)
+	if err != nil {
+		return internalError(err)
+	}
+	if program == nil {
+		res.Verdict = VerdictCompilationError
+		res.CompileOutput = compileOutput
+		return res
+	}
+
+	ex, err := j.execute(ctx, program, bytes.NewReader(stdin), limits, MaxScratchOutput, MaxScratchOutput)
+	if err != nil {
+		return internalError(err)
+	}
+	res.Stdout = ex.stdout.buf.String()
+	res.Stderr = ex.stderr.buf.String()
+	res.ExitCode = ex.state.ExitCode()
+	res.TimeMs = ex.timeMs
+	res.MemoryKb = ex.memoryKb
+	res.Verdict, res.Message = ex.verdict(limits)
+	if res.Verdict == VerdictAccepted {
+		res.Verdict = VerdictOK
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package judge
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExecute(t *testing.T) {
+	if _, err := exec.LookPath("g++"); err != nil {
+		t.Skip("g++ is not installed")
+	}
+
+	limits := Limits{TimeMs: 1000, MemoryMb: 64}
+	cases := map[string]struct {
+		source, stdin  string
+		want           Verdict
+		stdout, stderr string
+		exitCode       int
+	}{
+		"echo": {
+			"#include <iostream>\nint main(){int a,b;std::cin>>a>>b;std::cout<<a*b<<\"\\n\";std::cerr<<\"debug\";}",
+			"6 7\n", VerdictOK, "42\n", "debug", 0,
+		},
+		"exit code":     {"int main(){ return 3; }", "", VerdictRuntimeError, "", "", 3},
+		"compile error": {"int main(){ return }", "", VerdictCompilationError, "", "", -1},
+		"time limit":    {"int main(){ volatile unsigned long long x=0; for(;;) x++; }", "", VerdictTimeLimitExceeded, "", "", -1},
+		"output limit": {
+			"#include <cstdio>\nint main(){ for(;;) std::puts(\"spam\"); }",
+			"", VerdictRuntimeError, "", "", -1,
+		},
+	}
+
+	j := New("g++", false)
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			res := j.Execute(context.Background(), LanguageCpp, []byte(c.source), []byte(c.stdin), limits)
+			if res.Verdict != c.want {
+				t.Fatalf("verdict %v, want %v (%+v)", res.Verdict, c.want, res)
+			}
+			if c.stdout != "" && res.Stdout != c.stdout {
+				t.Errorf("stdout %q, want %q", res.Stdout, c.stdout)
+			}
+			if c.stderr != "" && res.Stderr != c.stderr {
+				t.Errorf("stderr %q, want %q", res.Stderr, c.stderr)
+			}
+			if c.exitCode != -1 && res.ExitCode != c.exitCode {
+				t.Errorf("exit code %v, want %v", res.ExitCode, c.exitCode)
+			}
+			if len(res.Stdout) > MaxScratchOutput || (name == "output limit" && !strings.HasPrefix(res.Stdout, "spam\n")) {
+				t.Errorf("stdout of %v bytes", len(res.Stdout))
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const validatorTimeout = 10 * time.Second
+
+// Validator is a testlib validator, it reads a test input from standard input and exits with a
+// non-zero code when the input breaks the constraints of the problem
+type Validator struct {
+	Source  []byte
+	binary  string
+	isolate bool
+}
+
+// PrepareValidator compiles a validator, binaries share the checker cache
+func (j *Judge) PrepareValidator(ctx context.Context, validator *Validator) (string, error) {
+	if validator.binary != "" {
+		return "", nil
+	}
+	if len(validator.Source) == 0 {
+		return "", fmt.Errorf("validator has no source")
+	}
+	binary, output, err := j.compileTestlib(ctx, validator.Source)
+	if err != nil {
+		return output, err
+	}
+	if output != "" {
+		return output, fmt.Errorf("validator does not compile")
+	}
+	validator.binary = binary
+	validator.isolate = j.Isolate
+	return "", nil
+}
+
+// Validate runs the validator on one test input, the error carries the validator's message.
+// The validator comes from an uploaded package, it is confined like the programs and only sees its binary
+func (v Validator) Validate(ctx context.Context, input io.Reader) error {
+	if v.binary == "" {
+		return fmt.Errorf("validator was not compiled")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, validatorTimeout)
+	defer cancel()
+
+	dir := filepath.Dir(v.binary)
+	cmd := limitedCommand(ctx, testlibLimits, []string{v.binary})
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=/usr/bin:/bin"}
+	cmd.Stdin = input
+	messages := &limitedBuffer{max: maxStderr, silent: true}
+	cmd.Stdout = messages
+	cmd.Stderr = messages
+	finish := sandbox(cmd, newJail(v.isolate, dir, false))
+
+	err := cmd.Run()
+	_, sandboxErr := finish()
+	message := strings.TrimSpace(messages.buf.String())
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("validator timed out")
+	}
+	if sandboxErr != nil {
+		return fmt.Errorf("failed to run validator: %v", sandboxErr)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("invalid input: %v", message)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run validator: %v", err)
+	}
+	return nil
+}
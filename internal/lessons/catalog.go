@@ -0,0 +1,142 @@
+package lessons
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+type Entry struct {
+	Path string
+	Meta Metadata
+	// Body is the markdown without front matter, Content the whole file
+	Body    []byte
+	Content []byte
+}
+
+type manifestEntry struct {
+	ID    string `json:"id"`
+	Path  string `json:"path"`
+	Title string `json:"title"`
+	Grade string `json:"grade"`
+}
+
+// Fingerprint summarizes the name, size and modification time of every lesson under root,
+// it changes whenever a lesson is added, removed or edited
+func Fingerprint(root string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(hash, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Scan parses every lesson under root. Files that cannot be parsed or reuse an ID are
+// reported in the returned problems instead of failing the whole scan.
+func Scan(root string) ([]Entry, []string, error) {
+	var entries []Entry
+	var problems []string
+	seen := make(map[int32]string)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		meta, body, err := Parse(path, content)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", path, err))
+			return nil
+		}
+		for _, conflict := range meta.Conflicts {
+			problems = append(problems, fmt.Sprintf("%s: %s", path, conflict))
+		}
+		if other, ok := seen[meta.ID]; ok {
+			problems = append(problems, fmt.Sprintf("%s: lesson id %d is already used by %s", path, meta.ID, other))
+			return nil
+		}
+		seen[meta.ID] = path
+
+		entries = append(entries, Entry{Path: path, Meta: meta, Body: body, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, problems, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Meta.ID < entries[j].Meta.ID
+	})
+	return entries, problems, nil
+}
+
+// WriteManifest replaces the manifest atomically so the static file server never sees a partial file.
+// Lesson sources are not served statically, paths point to the lesson API instead.
+func WriteManifest(manifestPath string, entries []Entry) error {
+	manifest := struct {
+		Lectii []manifestEntry `json:"lectii"`
+	}{Lectii: []manifestEntry{}}
+
+	for _, entry := range entries {
+		id := strconv.Itoa(int(entry.Meta.ID))
+		manifest.Lectii = append(manifest.Lectii, manifestEntry{
+			ID:    id,
+			Path:  "/api/lessons/" + id,
+			Title: entry.Meta.Title,
+			Grade: entry.Meta.Grade,
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(manifestPath), ".manifest-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(append(data, '\n'))
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), manifestPath)
+}
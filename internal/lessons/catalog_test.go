@@ -0,0 +1,83 @@
+package lessons
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeLesson(t *testing.T, path string, content string) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanAndWriteManifest(t *testing.T) {
+	appDir := t.TempDir()
+	root := filepath.Join(appDir, "Lectii")
+	writeLesson(t, filepath.Join(root, "Clasa a X-a", "123_[Introducere in struct].md"), "# Lecția 123\n")
+	writeLesson(t, filepath.Join(root, "Clasa a XII-a", "223_[Introducere in cpp].md"), "# C++\n")
+	writeLesson(t, filepath.Join(root, "Clasa a XII-a", "notite.md"), "# Fara id\n")
+	writeLesson(t, filepath.Join(root, "Clasa a XII-a", "123_[Duplicat].md"), "# Duplicat\n")
+
+	entries, problems, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Meta.ID != 123 || entries[1].Meta.ID != 223 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if len(problems) != 2 {
+		t.Errorf("expected the unnamed and duplicate lessons to be reported, got %v", problems)
+	}
+
+	manifestPath := filepath.Join(root, "manifest.json")
+	err = WriteManifest(manifestPath, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest struct {
+		Lectii []manifestEntry `json:"lectii"`
+	}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Lectii) != 2 {
+		t.Fatalf("unexpected manifest: %s", data)
+	}
+	expected := "/api/lessons/123"
+	if manifest.Lectii[0].Path != expected {
+		t.Errorf("expected path %s, got %s", expected, manifest.Lectii[0].Path)
+	}
+}
+
+func TestFingerprintChanges(t *testing.T) {
+	root := t.TempDir()
+	writeLesson(t, filepath.Join(root, "Clasa a X-a", "123_[Structuri].md"), "# Structuri\n")
+
+	before, err := Fingerprint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeLesson(t, filepath.Join(root, "Clasa a X-a", "124_[Pointeri].md"), "# Pointeri\n")
+	after, err := Fingerprint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Error("fingerprint did not change after adding a lesson")
+	}
+}
@@ -0,0 +1,83 @@
+package lessons
+
+import "sort"
+
+// FindCycle returns the lessons forming a cycle in the prerequisite graph, starting and ending
+// with the same lesson, or nil if the graph is acyclic. prerequisites maps a lesson to the lessons it requires.
+func FindCycle(prerequisites map[int32][]int32) []int32 {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[int32]int)
+	var stack []int32
+
+	var visit func(lesson int32) []int32
+	visit = func(lesson int32) []int32 {
+		state[lesson] = visiting
+		stack = append(stack, lesson)
+		for _, required := range prerequisites[lesson] {
+			switch state[required] {
+			case visiting:
+				// The cycle is the part of the stack starting at the repeated lesson
+				for i := range stack {
+					if stack[i] == required {
+						return append(append([]int32{}, stack[i:]...), required)
+					}
+				}
+			case unvisited:
+				if cycle := visit(required); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[lesson] = done
+		return nil
+	}
+
+	// Sorted so the reported cycle is deterministic
+	lessons := make([]int32, 0, len(prerequisites))
+	for lesson := range prerequisites {
+		lessons = append(lessons, lesson)
+	}
+	sort.Slice(lessons, func(i, j int) bool { return lessons[i] < lessons[j] })
+
+	for _, lesson := range lessons {
+		if state[lesson] == unvisited {
+			if cycle := visit(lesson); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Unlocked returns, in the order of available, the lessons that are not completed yet and whose
+// available prerequisites are all completed. Prerequisites missing from available are ignored,
+// a student cannot be blocked by a lesson they have no access to.
+func Unlocked(available []int32, prerequisites map[int32][]int32, completed map[int32]bool) []int32 {
+	isAvailable := make(map[int32]bool, len(available))
+	for _, lesson := range available {
+		isAvailable[lesson] = true
+	}
+
+	unlocked := []int32{}
+	for _, lesson := range available {
+		if completed[lesson] {
+			continue
+		}
+		ready := true
+		for _, required := range prerequisites[lesson] {
+			if isAvailable[required] && !completed[required] {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			unlocked = append(unlocked, lesson)
+		}
+	}
+	return unlocked
+}
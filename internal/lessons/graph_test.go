@@ -0,0 +1,58 @@
+package lessons
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindCycleAcyclic(t *testing.T) {
+	prerequisites := map[int32][]int32{
+		124: {123},
+		125: {123, 124},
+		223: {125},
+	}
+	if cycle := FindCycle(prerequisites); cycle != nil {
+		t.Errorf("unexpected cycle: %v", cycle)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	prerequisites := map[int32][]int32{
+		123: {125},
+		124: {123},
+		125: {124},
+		223: {123},
+	}
+	cycle := FindCycle(prerequisites)
+	expected := []int32{123, 125, 124, 123}
+	if !reflect.DeepEqual(cycle, expected) {
+		t.Errorf("expected %v, got %v", expected, cycle)
+	}
+}
+
+func TestFindCycleSelfLoop(t *testing.T) {
+	cycle := FindCycle(map[int32][]int32{123: {123}})
+	if !reflect.DeepEqual(cycle, []int32{123, 123}) {
+		t.Errorf("unexpected cycle: %v", cycle)
+	}
+}
+
+func TestUnlocked(t *testing.T) {
+	available := []int32{123, 124, 125, 223}
+	prerequisites := map[int32][]int32{
+		124: {123},
+		125: {123, 124},
+		// 999 is not available, so it does not block 223
+		223: {123, 999},
+	}
+
+	unlocked := Unlocked(available, prerequisites, map[int32]bool{})
+	if !reflect.DeepEqual(unlocked, []int32{123}) {
+		t.Errorf("expected only the first lesson, got %v", unlocked)
+	}
+
+	unlocked = Unlocked(available, prerequisites, map[int32]bool{123: true})
+	if !reflect.DeepEqual(unlocked, []int32{124, 223}) {
+		t.Errorf("expected 124 and 223, got %v", unlocked)
+	}
+}
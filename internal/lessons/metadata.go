@@ -0,0 +1,169 @@
+package lessons
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lesson files are named "<id>_[<title>].md", e.g. "123_[Introducere in struct].md"
+var filenameRegex = regexp.MustCompile(`^(\d+)_\[([^\[\]/\\]+)\]\.md$`)
+
+// Lessons usually open with "# Lecția 123: ..."
+var headingIDRegex = regexp.MustCompile(`(?m)^#\s+Lec[tț]ia\s+(\d+)\b`)
+
+type Metadata struct {
+	ID    int32    `json:"id"`
+	Title string   `json:"title"`
+	Grade string   `json:"grade"`
+	Tags  []string `json:"tags"`
+	// Prerequisites is nil when the front matter does not mention them
+	Prerequisites    []int32  `json:"prerequisites"`
+	EstimatedMinutes int32    `json:"estimated_minutes"`
+	Author           string   `json:"author"`
+	Conflicts        []string `json:"conflicts,omitempty"`
+}
+
+type frontMatter struct {
+	ID            *int32   `yaml:"id"`
+	Title         string   `yaml:"title"`
+	Grade         string   `yaml:"grade"`
+	Tags          []string `yaml:"tags"`
+	Prerequisites []int32  `yaml:"prerequisites"`
+	EstimatedTime minutes  `yaml:"estimated_time"`
+	Author        string   `yaml:"author"`
+}
+
+// minutes accepts both plain numbers ("45") and durations ("1h30m")
+type minutes int32
+
+func (m *minutes) UnmarshalYAML(value *yaml.Node) error {
+	if n, err := strconv.Atoi(value.Value); err == nil {
+		*m = minutes(n)
+		return nil
+	}
+	d, err := time.ParseDuration(value.Value)
+	if err != nil {
+		return fmt.Errorf("invalid estimated_time %q", value.Value)
+	}
+	*m = minutes(d.Minutes())
+	return nil
+}
+
+func ParseFilename(filename string) (int32, string, error) {
+	match := filenameRegex.FindStringSubmatch(filename)
+	if match == nil {
+		return 0, "", fmt.Errorf("lesson filename %q does not match <id>_[<title>].md", filename)
+	}
+	id, err := strconv.ParseInt(match[1], 10, 32)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid lesson ID in %q: %v", filename, err)
+	}
+	return int32(id), strings.TrimSpace(match[2]), nil
+}
+
+// SplitFrontMatter separates an optional "---" delimited YAML header from the markdown body
+func SplitFrontMatter(content []byte) ([]byte, []byte, bool) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	normalized := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(normalized, []byte("---\n")) {
+		return nil, content, false
+	}
+	rest := normalized[len("---\n"):]
+	end := bytes.Index(rest, []byte("\n---\n"))
+	if end == -1 {
+		if !bytes.HasSuffix(rest, []byte("\n---")) {
+			return nil, content, false
+		}
+		return rest[:len(rest)-len("\n---")], []byte{}, true
+	}
+	return rest[:end], rest[end+len("\n---\n"):], true
+}
+
+// ParseContent reads the front matter of a lesson, returning its metadata and the markdown body without it
+func ParseContent(content []byte) (Metadata, []byte, error) {
+	meta := Metadata{Tags: []string{}}
+	header, body, found := SplitFrontMatter(content)
+	if !found {
+		return meta, body, nil
+	}
+
+	var fm frontMatter
+	err := yaml.Unmarshal(header, &fm)
+	if err != nil {
+		return meta, body, fmt.Errorf("invalid lesson front matter: %v", err)
+	}
+
+	if fm.ID != nil {
+		meta.ID = *fm.ID
+	}
+	meta.Title = strings.TrimSpace(fm.Title)
+	meta.Grade = strings.TrimSpace(fm.Grade)
+	meta.Author = strings.TrimSpace(fm.Author)
+	meta.EstimatedMinutes = int32(fm.EstimatedTime)
+	for _, tag := range fm.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			meta.Tags = append(meta.Tags, tag)
+		}
+	}
+	if fm.Prerequisites != nil {
+		meta.Prerequisites = fm.Prerequisites
+	}
+	return meta, body, nil
+}
+
+// Parse combines the filename convention, the grade directory and the front matter of a lesson.
+// Front matter takes precedence, every disagreement is reported in Metadata.Conflicts.
+func Parse(path string, content []byte) (Metadata, []byte, error) {
+	meta, body, err := ParseContent(content)
+	if err != nil {
+		return meta, body, err
+	}
+
+	fileID, fileTitle, err := ParseFilename(filepath.Base(path))
+	if err != nil {
+		if meta.ID == 0 || meta.Title == "" {
+			return meta, body, err
+		}
+		meta.Conflicts = append(meta.Conflicts, err.Error())
+	} else {
+		if meta.ID == 0 {
+			meta.ID = fileID
+		} else if meta.ID != fileID {
+			meta.Conflicts = append(meta.Conflicts, fmt.Sprintf("front matter id %d differs from filename id %d", meta.ID, fileID))
+		}
+		if meta.Title == "" {
+			meta.Title = fileTitle
+		} else if !strings.EqualFold(meta.Title, fileTitle) {
+			meta.Conflicts = append(meta.Conflicts, fmt.Sprintf("front matter title %q differs from filename title %q", meta.Title, fileTitle))
+		}
+	}
+
+	dirGrade := filepath.Base(filepath.Dir(path))
+	if meta.Grade == "" {
+		meta.Grade = dirGrade
+	} else if meta.Grade != dirGrade {
+		meta.Conflicts = append(meta.Conflicts, fmt.Sprintf("front matter grade %q differs from directory %q", meta.Grade, dirGrade))
+	}
+
+	if match := headingIDRegex.FindSubmatch(body); match != nil {
+		if headingID, err := strconv.Atoi(string(match[1])); err == nil && int32(headingID) != meta.ID {
+			meta.Conflicts = append(meta.Conflicts, fmt.Sprintf("heading mentions lesson %d but the lesson id is %d", headingID, meta.ID))
+		}
+	}
+
+	for _, prerequisite := range meta.Prerequisites {
+		if prerequisite == meta.ID {
+			meta.Conflicts = append(meta.Conflicts, "lesson lists itself as a prerequisite")
+		}
+	}
+
+	return meta, body, nil
+}
@@ -0,0 +1,85 @@
+package lessons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFilename(t *testing.T) {
+	id, title, err := ParseFilename("123_[Introducere in struct].md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 123 || title != "Introducere in struct" {
+		t.Errorf("unexpected result: %v %q", id, title)
+	}
+
+	_, _, err = ParseFilename("introducere.md")
+	if err == nil {
+		t.Error("accepted a filename without id and title")
+	}
+}
+
+func TestParseWithoutFrontMatter(t *testing.T) {
+	meta, body, err := Parse("App/Lectii/Clasa a X-a/123_[Introducere in struct].md", []byte("# Lecția 123: Structuri\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ID != 123 || meta.Title != "Introducere in struct" || meta.Grade != "Clasa a X-a" {
+		t.Errorf("unexpected metadata: %+v", meta)
+	}
+	if len(meta.Conflicts) != 0 {
+		t.Errorf("unexpected conflicts: %v", meta.Conflicts)
+	}
+	if string(body) != "# Lecția 123: Structuri\n" {
+		t.Errorf("body changed: %q", body)
+	}
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	content := "---\ntitle: Introducere în struct\ntags: [cpp, struct]\nprerequisites: [223]\nestimated_time: 1h30m\nauthor: Prof. Ionescu\n---\n# Structuri\n"
+	meta, body, err := Parse("App/Lectii/Clasa a X-a/123_[Introducere in struct].md", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Introducere în struct" || meta.EstimatedMinutes != 90 || meta.Author != "Prof. Ionescu" {
+		t.Errorf("unexpected metadata: %+v", meta)
+	}
+	if len(meta.Tags) != 2 || len(meta.Prerequisites) != 1 || meta.Prerequisites[0] != 223 {
+		t.Errorf("unexpected tags or prerequisites: %+v", meta)
+	}
+	if string(body) != "# Structuri\n" {
+		t.Errorf("front matter left in body: %q", body)
+	}
+	// The diacritics make the titles differ, which is reported
+	if len(meta.Conflicts) != 1 {
+		t.Errorf("expected one conflict, got %v", meta.Conflicts)
+	}
+}
+
+func TestParseConflicts(t *testing.T) {
+	content := "---\nid: 124\ngrade: Clasa a XI-a\n---\n# Lecția 125: Structuri\n"
+	meta, _, err := Parse("App/Lectii/Clasa a X-a/123_[Introducere in struct].md", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ID != 124 || meta.Grade != "Clasa a XI-a" {
+		t.Errorf("front matter should take precedence: %+v", meta)
+	}
+	expected := []string{"id 124", "grade", "lesson 125"}
+	if len(meta.Conflicts) != len(expected) {
+		t.Fatalf("expected %d conflicts, got %v", len(expected), meta.Conflicts)
+	}
+	for i := range expected {
+		if !strings.Contains(meta.Conflicts[i], expected[i]) {
+			t.Errorf("conflict %d: expected mention of %q, got %q", i, expected[i], meta.Conflicts[i])
+		}
+	}
+}
+
+func TestParseInvalidFrontMatter(t *testing.T) {
+	_, _, err := Parse("App/Lectii/Clasa a X-a/123_[Introducere in struct].md", []byte("---\nestimated_time: soon\n---\n"))
+	if err == nil {
+		t.Error("accepted an invalid estimated_time")
+	}
+}
@@ -0,0 +1,56 @@
+package lessons
+
+import "fmt"
+
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusInReview  Status = "in_review"
+	StatusPublished Status = "published"
+	StatusArchived  Status = "archived"
+)
+
+type Action string
+
+const (
+	// ActionSubmit sends a draft to the reviewers
+	ActionSubmit Action = "submit"
+	// ActionApprove publishes a lesson in review
+	ActionApprove Action = "approve"
+	// ActionReject sends a lesson in review back to its author
+	ActionReject Action = "reject"
+	// ActionPublish skips the review, reserved for admins
+	ActionPublish Action = "publish"
+	// ActionArchive hides a published lesson
+	ActionArchive Action = "archive"
+	// ActionRestore turns an archived lesson back into a draft
+	ActionRestore Action = "restore"
+)
+
+var transitions = map[Action]map[Status]Status{
+	ActionSubmit:  {StatusDraft: StatusInReview},
+	ActionApprove: {StatusInReview: StatusPublished},
+	ActionReject:  {StatusInReview: StatusDraft},
+	ActionPublish: {StatusDraft: StatusPublished, StatusInReview: StatusPublished},
+	ActionArchive: {StatusPublished: StatusArchived},
+	ActionRestore: {StatusArchived: StatusDraft},
+}
+
+// Transition returns the status a lesson ends up in after action, or an error if the action is not allowed from status
+func Transition(status Status, action Action) (Status, error) {
+	targets, ok := transitions[action]
+	if !ok {
+		return status, fmt.Errorf("unknown action %q", action)
+	}
+	next, ok := targets[status]
+	if !ok {
+		return status, fmt.Errorf("cannot %s a lesson that is %s", action, status)
+	}
+	return next, nil
+}
+
+// IsReviewAction reports whether action is taken by a reviewer rather than the author
+func IsReviewAction(action Action) bool {
+	return action != ActionSubmit
+}
@@ -0,0 +1,45 @@
+package lessons
+
+import "testing"
+
+func TestTransition(t *testing.T) {
+	cases := []struct {
+		from   Status
+		action Action
+		to     Status
+	}{
+		{StatusDraft, ActionSubmit, StatusInReview},
+		{StatusInReview, ActionApprove, StatusPublished},
+		{StatusInReview, ActionReject, StatusDraft},
+		{StatusDraft, ActionPublish, StatusPublished},
+		{StatusPublished, ActionArchive, StatusArchived},
+		{StatusArchived, ActionRestore, StatusDraft},
+	}
+	for _, c := range cases {
+		to, err := Transition(c.from, c.action)
+		if err != nil {
+			t.Errorf("%s from %s: %v", c.action, c.from, err)
+			continue
+		}
+		if to != c.to {
+			t.Errorf("%s from %s: expected %s, got %s", c.action, c.from, c.to, to)
+		}
+	}
+}
+
+func TestTransitionInvalid(t *testing.T) {
+	invalid := []struct {
+		from   Status
+		action Action
+	}{
+		{StatusDraft, ActionApprove},
+		{StatusPublished, ActionSubmit},
+		{StatusArchived, ActionPublish},
+		{StatusDraft, "delete"},
+	}
+	for _, c := range invalid {
+		if _, err := Transition(c.from, c.action); err == nil {
+			t.Errorf("%s from %s should not be allowed", c.action, c.from)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package markdown
+
+import (
+	"Codium/internal/text"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// headingIDs turns "🔧 Accesarea membrilor structurii" into "accesarea-membrilor-structurii",
+// the default goldmark generator drops non ASCII letters instead of transliterating them
+type headingIDs struct {
+	used map[string]int
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]int)}
+}
+
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var sb strings.Builder
+	dash := false
+	for _, c := range text.StripDiacritics(strings.ToLower(string(value))) {
+		if c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)) {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(c)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	id := sb.String()
+	if id == "" {
+		id = "sectiune"
+	}
+	ids.used[id]++
+	if ids.used[id] > 1 {
+		id += "-" + strconv.Itoa(ids.used[id]-1)
+	}
+	return []byte(id)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.used[string(value)]++
+}
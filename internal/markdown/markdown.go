@@ -0,0 +1,138 @@
+package markdown
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Headings deeper than this are left out of the table of contents
+const maxTocLevel = 3
+
+// Rendered outputs are kept until the cache grows past this many entries
+const maxCacheEntries = 512
+
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+type Rendered struct {
+	HTML string    `json:"html"`
+	TOC  []Heading `json:"toc"`
+	Hash string    `json:"hash"`
+}
+
+type Renderer struct {
+	md    goldmark.Markdown
+	mu    sync.RWMutex
+	cache map[string]Rendered
+}
+
+// NewRenderer creates a lesson renderer. Raw HTML and dangerous link targets (javascript: etc.)
+// are never passed through since goldmark is not configured with html.WithUnsafe.
+func NewRenderer() *Renderer {
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(
+				extension.GFM,
+				highlighting.NewHighlighting(
+					highlighting.WithStyle("github"),
+				),
+			),
+			goldmark.WithParserOptions(
+				parser.WithAutoHeadingID(),
+			),
+		),
+		cache: make(map[string]Rendered),
+	}
+}
+
+func Hash(source []byte) string {
+	sum := sha256.Sum256(source)
+	return hex.EncodeToString(sum[:])
+}
+
+// Render converts lesson markdown to HTML, results are cached by the hash of the source
+func (r *Renderer) Render(source []byte) (Rendered, error) {
+	hash := Hash(source)
+
+	r.mu.RLock()
+	cached, ok := r.cache[hash]
+	r.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	// Heading IDs are generated per document, so every render needs a fresh context
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := r.md.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	err := r.md.Renderer().Render(&buf, source, doc)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	res := Rendered{
+		HTML: buf.String(),
+		TOC:  tableOfContents(doc, source),
+		Hash: hash,
+	}
+
+	r.mu.Lock()
+	if len(r.cache) >= maxCacheEntries {
+		r.cache = make(map[string]Rendered)
+	}
+	r.cache[hash] = res
+	r.mu.Unlock()
+
+	return res, nil
+}
+
+func tableOfContents(doc ast.Node, source []byte) []Heading {
+	toc := []Heading{}
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if heading.Level >= 2 && heading.Level <= maxTocLevel {
+			id, _ := heading.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			toc = append(toc, Heading{
+				Level: heading.Level,
+				ID:    string(idBytes),
+				Text:  strings.TrimSpace(nodeText(heading, source)),
+			})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+func nodeText(node ast.Node, source []byte) string {
+	var sb strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch n := child.(type) {
+		case *ast.Text:
+			sb.Write(n.Segment.Value(source))
+		case *ast.String:
+			sb.Write(n.Value)
+		default:
+			sb.WriteString(nodeText(child, source))
+		}
+	}
+	return sb.String()
+}
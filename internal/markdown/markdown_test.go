@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+const lesson = "# Lecția 123\n\n## 📚 Obiectivele lecției\n\n```cpp\nstruct Punct2D {\n    int x;\n};\n```\n\n## Structuri\n\n### Structuri\n"
+
+func TestRenderHighlightsCpp(t *testing.T) {
+	res, err := NewRenderer().Render([]byte(lesson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res.HTML, "<pre") || !strings.Contains(res.HTML, "<span style=") {
+		t.Errorf("expected highlighted code block, got %s", res.HTML)
+	}
+}
+
+func TestRenderTableOfContents(t *testing.T) {
+	res, err := NewRenderer().Render([]byte(lesson))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Heading{
+		{Level: 2, ID: "obiectivele-lectiei", Text: "📚 Obiectivele lecției"},
+		{Level: 2, ID: "structuri", Text: "Structuri"},
+		{Level: 3, ID: "structuri-1", Text: "Structuri"},
+	}
+	if len(res.TOC) != len(expected) {
+		t.Fatalf("expected %d headings, got %v", len(expected), res.TOC)
+	}
+	for i := range expected {
+		if res.TOC[i] != expected[i] {
+			t.Errorf("heading %d: expected %v, got %v", i, expected[i], res.TOC[i])
+		}
+	}
+	if !strings.Contains(res.HTML, `id="obiectivele-lectiei"`) {
+		t.Error("heading anchor missing from html")
+	}
+}
+
+func TestRenderStripsRawHtml(t *testing.T) {
+	res, err := NewRenderer().Render([]byte("<script>alert(1)</script>\n\n[link](javascript:alert(1))\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(res.HTML, "<script>") || strings.Contains(res.HTML, "javascript:") {
+		t.Errorf("unsafe html was not removed: %s", res.HTML)
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	renderer := NewRenderer()
+	first, err := renderer.Render([]byte(lesson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := renderer.Render([]byte(lesson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Hash != second.Hash || len(renderer.cache) != 1 {
+		t.Error("expected the second render to be served from cache")
+	}
+}
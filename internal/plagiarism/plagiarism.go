@@ -0,0 +1,262 @@
+package plagiarism
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+)
+
+// Defaults of Options, any match of at least DefaultK+DefaultWindow-1 tokens is guaranteed to be found
+const (
+	DefaultK      = 8
+	DefaultWindow = 6
+	// DefaultCommon is how many authors may share a fingerprint before it counts as boilerplate
+	DefaultCommon = 10
+)
+
+// Document is one source to compare, documents of the same author are never paired
+type Document struct {
+	ID       string
+	Author   string
+	Language Language
+	Source   string
+}
+
+// Options tune the analysis, zero values fall back to the defaults
+type Options struct {
+	// K is the length in tokens of the fingerprinted k-grams
+	K int
+	// Window is the number of consecutive k-grams winnowing picks one fingerprint from
+	Window int
+	// Common drops fingerprints shared by more authors, such as the template every solution starts from
+	Common int
+	// MinSimilarity filters out pairs that are less similar
+	MinSimilarity float64
+	// Limit caps the number of pairs returned, zero means no limit
+	Limit int
+}
+
+// Span is a range of lines of a source, 1-based and inclusive
+type Span struct {
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+}
+
+// Match is a run of identical normalized tokens found in both sources of a pair
+type Match struct {
+	A      Span `json:"a"`
+	B      Span `json:"b"`
+	Tokens int  `json:"tokens"`
+}
+
+// Pair is a suspicious pair of documents. SimilarityA is the share of A's fingerprints found in B and
+// SimilarityB the other way around, Similarity is the share of both taken together
+type Pair struct {
+	A           string  `json:"a"`
+	B           string  `json:"b"`
+	Similarity  float64 `json:"similarity"`
+	SimilarityA float64 `json:"similarity_a"`
+	SimilarityB float64 `json:"similarity_b"`
+	Matches     []Match `json:"matches"`
+}
+
+// Fingerprint is a winnowed k-gram hash and the index of the k-gram's first token
+type Fingerprint struct {
+	Hash uint64
+	Pos  int
+}
+
+const hashBase = 1000003
+
+// Fingerprints hashes every k-gram of tokens and keeps the minimum hash of each window of w consecutive
+// k-grams, the rightmost one on ties, as in the winnowing algorithm of MOSS
+func Fingerprints(tokens []Token, k, w int) []Fingerprint {
+	if len(tokens) < k {
+		return nil
+	}
+
+	tokenHashes := make([]uint64, len(tokens))
+	for i, token := range tokens {
+		h := fnv.New64a()
+		h.Write([]byte(token.Text))
+		tokenHashes[i] = h.Sum64()
+	}
+
+	// Rolling hash of k-grams, arithmetic wraps around modulo 2^64
+	power := uint64(1)
+	for i := 1; i < k; i++ {
+		power *= hashBase
+	}
+	grams := make([]uint64, len(tokens)-k+1)
+	var h uint64
+	for i, tokenHash := range tokenHashes {
+		if i >= k {
+			h -= tokenHashes[i-k] * power
+		}
+		h = h*hashBase + tokenHash
+		if i >= k-1 {
+			grams[i-k+1] = h
+		}
+	}
+
+	var prints []Fingerprint
+	last := -1
+	for start := 0; start+w <= len(grams) || start == 0; start++ {
+		end := min(start+w, len(grams))
+		best := start
+		for i := start; i < end; i++ {
+			if grams[i] <= grams[best] {
+				best = i
+			}
+		}
+		if best != last {
+			prints = append(prints, Fingerprint{Hash: grams[best], Pos: best})
+			last = best
+		}
+	}
+	return prints
+}
+
+type document struct {
+	Document
+	tokens []Token
+	prints []Fingerprint
+	hashes map[uint64]bool
+}
+
+// Analyze compares every two documents of the same language by different authors and ranks the pairs
+// from the most similar one
+func Analyze(docs []Document, opts Options) []Pair {
+	k := cmp.Or(opts.K, DefaultK)
+	w := cmp.Or(opts.Window, DefaultWindow)
+	common := cmp.Or(opts.Common, DefaultCommon)
+
+	analyzed := make([]document, len(docs))
+	for i, doc := range docs {
+		tokens := Tokenize(doc.Source, doc.Language)
+		analyzed[i] = document{Document: doc, tokens: tokens, prints: Fingerprints(tokens, k, w)}
+	}
+
+	// Index the fingerprints and count how many authors share each of them
+	index := make(map[uint64][]int)
+	authors := make(map[uint64]map[string]bool)
+	for i, doc := range analyzed {
+		for _, fp := range doc.prints {
+			if holders := index[fp.Hash]; len(holders) == 0 || holders[len(holders)-1] != i {
+				index[fp.Hash] = append(holders, i)
+			}
+			if authors[fp.Hash] == nil {
+				authors[fp.Hash] = make(map[string]bool)
+			}
+			authors[fp.Hash][doc.Author] = true
+		}
+	}
+	for i := range analyzed {
+		doc := &analyzed[i]
+		doc.hashes = make(map[uint64]bool)
+		for _, fp := range doc.prints {
+			if len(authors[fp.Hash]) <= common {
+				doc.hashes[fp.Hash] = true
+			}
+		}
+	}
+
+	// Count the distinct fingerprints every two documents share
+	shared := make(map[[2]int]int)
+	for hash, holders := range index {
+		if len(authors[hash]) > common {
+			continue
+		}
+		for x := 0; x < len(holders); x++ {
+			for y := x + 1; y < len(holders); y++ {
+				a, b := analyzed[holders[x]], analyzed[holders[y]]
+				if a.Author == b.Author || a.Language != b.Language {
+					continue
+				}
+				shared[[2]int{holders[x], holders[y]}]++
+			}
+		}
+	}
+
+	pairs := make([]Pair, 0, len(shared))
+	for key, count := range shared {
+		a, b := analyzed[key[0]], analyzed[key[1]]
+		pair := Pair{
+			A:           a.ID,
+			B:           b.ID,
+			Similarity:  float64(2*count) / float64(len(a.hashes)+len(b.hashes)),
+			SimilarityA: float64(count) / float64(len(a.hashes)),
+			SimilarityB: float64(count) / float64(len(b.hashes)),
+		}
+		if pair.Similarity >= opts.MinSimilarity {
+			pairs = append(pairs, pair)
+		}
+	}
+	slices.SortFunc(pairs, func(x, y Pair) int {
+		return cmp.Or(cmp.Compare(y.Similarity, x.Similarity), cmp.Compare(x.A, y.A), cmp.Compare(x.B, y.B))
+	})
+	if opts.Limit > 0 && len(pairs) > opts.Limit {
+		pairs = pairs[:opts.Limit]
+	}
+
+	byID := make(map[string]*document, len(analyzed))
+	for i := range analyzed {
+		byID[analyzed[i].ID] = &analyzed[i]
+	}
+	for i := range pairs {
+		pairs[i].Matches = matches(byID[pairs[i].A], byID[pairs[i].B], k)
+	}
+	return pairs
+}
+
+// matches extends every shared fingerprint into the longest run of identical tokens around it,
+// runs overlapping an earlier one are dropped
+func matches(a, b *document, k int) []Match {
+	positions := make(map[uint64][]int)
+	for _, fp := range b.prints {
+		if b.hashes[fp.Hash] && a.hashes[fp.Hash] {
+			positions[fp.Hash] = append(positions[fp.Hash], fp.Pos)
+		}
+	}
+
+	usedA := make([]bool, len(a.tokens))
+	usedB := make([]bool, len(b.tokens))
+	var res []Match
+	for _, fp := range a.prints {
+		for _, posB := range positions[fp.Hash] {
+			posA := fp.Pos
+			if usedA[posA] || usedB[posB] {
+				continue
+			}
+			startA, startB := posA, posB
+			for startA > 0 && startB > 0 && !usedA[startA-1] && !usedB[startB-1] && a.tokens[startA-1].Text == b.tokens[startB-1].Text {
+				startA--
+				startB--
+			}
+			endA, endB := posA, posB
+			for endA < len(a.tokens) && endB < len(b.tokens) && !usedA[endA] && !usedB[endB] && a.tokens[endA].Text == b.tokens[endB].Text {
+				endA++
+				endB++
+			}
+			// Equal hashes of different k-grams are collisions
+			if endA-startA < k {
+				continue
+			}
+			for i := startA; i < endA; i++ {
+				usedA[i] = true
+			}
+			for i := startB; i < endB; i++ {
+				usedB[i] = true
+			}
+			res = append(res, Match{
+				A:      Span{StartLine: a.tokens[startA].StartLine, EndLine: a.tokens[endA-1].EndLine},
+				B:      Span{StartLine: b.tokens[startB].StartLine, EndLine: b.tokens[endB-1].EndLine},
+				Tokens: endA - startA,
+			})
+		}
+	}
+	slices.SortFunc(res, func(x, y Match) int {
+		return cmp.Compare(x.A.StartLine, y.A.StartLine)
+	})
+	return res
+}
@@ -0,0 +1,150 @@
+package plagiarism
+
+import (
+	"fmt"
+	"testing"
+)
+
+const original = `#include <bits/stdc++.h>
+using namespace std;
+
+int main() {
+    int n;
+    cin >> n;
+    vector<long long> v(n);
+    for (int i = 0; i < n; i++) cin >> v[i];
+    sort(v.begin(), v.end());
+    long long best = 0;
+    for (int i = 1; i < n; i++) {
+        if (v[i] - v[i - 1] > best) best = v[i] - v[i - 1];
+    }
+    cout << best << "\n";
+    return 0;
+}
+`
+
+// The same program with other names, comments and layout
+const renamed = `#include <iostream>
+#include <vector>
+#include <algorithm>
+using namespace std;
+// max gap
+int main()
+{
+    int count;
+    cin >> count;
+    vector<long long> values(count);
+    for (int j = 0; j < count; j++)
+        cin >> values[j];
+    sort(values.begin(), values.end());
+    long long answer = 0;
+    for (int j = 1; j < count; j++)
+    {
+        if (values[j] - values[j - 1] > answer)
+            answer = values[j] - values[j - 1];
+    }
+    cout << answer << endl;
+    return 0;
+}
+`
+
+const unrelated = `#include <stdio.h>
+
+int main(void) {
+    char s[100];
+    scanf("%s", s);
+    int vowels = 0;
+    for (char *p = s; *p; p++) {
+        switch (*p) {
+        case 'a': case 'e': case 'i': case 'o': case 'u':
+            vowels++;
+        }
+    }
+    printf("%d\n", vowels);
+    return 0;
+}
+`
+
+func TestFingerprintsWinnowing(t *testing.T) {
+	tokens := Tokenize(original, LanguageC)
+	prints := Fingerprints(tokens, 5, 4)
+	if len(prints) == 0 {
+		t.Fatal("expected fingerprints")
+	}
+	// Every window of 4 k-grams holds at least one fingerprint
+	grams := len(tokens) - 5 + 1
+	for start := 0; start+4 <= grams; start++ {
+		found := false
+		for _, fp := range prints {
+			if fp.Pos >= start && fp.Pos < start+4 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("window at %d has no fingerprint", start)
+		}
+	}
+	if len(Fingerprints(tokens[:3], 5, 4)) != 0 {
+		t.Error("expected no fingerprints for fewer tokens than k")
+	}
+}
+
+func TestAnalyzeRenamedCopy(t *testing.T) {
+	pairs := Analyze([]Document{
+		{ID: "a", Author: "ana", Language: LanguageC, Source: original},
+		{ID: "b", Author: "bogdan", Language: LanguageC, Source: renamed},
+		{ID: "c", Author: "cristi", Language: LanguageC, Source: unrelated},
+	}, Options{MinSimilarity: 0.5})
+	if len(pairs) != 1 {
+		t.Fatalf("expected one suspicious pair, got %+v", pairs)
+	}
+	pair := pairs[0]
+	if pair.A != "a" || pair.B != "b" {
+		t.Errorf("unexpected pair %v-%v", pair.A, pair.B)
+	}
+	if pair.Similarity < 0.9 {
+		t.Errorf("expected a renamed copy to be at least 90%% similar, got %.2f", pair.Similarity)
+	}
+	if len(pair.Matches) == 0 {
+		t.Fatal("expected matches")
+	}
+	// The copy ends differently, endl instead of "\n"
+	match := pair.Matches[0]
+	if match.A != (Span{StartLine: 2, EndLine: 14}) || match.B != (Span{StartLine: 4, EndLine: 20}) {
+		t.Errorf("unexpected first match %+v", match)
+	}
+}
+
+func TestAnalyzeSkipsSameAuthorAndOtherLanguages(t *testing.T) {
+	pairs := Analyze([]Document{
+		{ID: "a", Author: "ana", Language: LanguageC, Source: original},
+		{ID: "b", Author: "ana", Language: LanguageC, Source: renamed},
+		{ID: "c", Author: "cristi", Language: LanguagePython, Source: original},
+	}, Options{})
+	if len(pairs) != 0 {
+		t.Errorf("expected no pairs, got %+v", pairs)
+	}
+}
+
+func TestAnalyzeIgnoresCommonCode(t *testing.T) {
+	// Every author starts from the same template, only two of them share the rest
+	template := "#include <iostream>\nusing namespace std;\nint main() {\n    ios::sync_with_stdio(false);\n    cin.tie(nullptr);\n    int n;\n    cin >> n;\n"
+	bodies := []string{
+		"    while (n > 0) { cout << n % 10; n /= 10; }\n",
+		"    if (n % 2 == 0) cout << \"even\"; else cout << \"odd\";\n",
+		"    long long s = 0;\n    for (int i = 1; i <= n; i++) s += i;\n    cout << s;\n",
+		"    vector<int> v(n);\n    for (auto &x : v) cin >> x;\n    cout << *max_element(v.begin(), v.end());\n",
+		"    string s;\n    cin >> s;\n    reverse(s.begin(), s.end());\n    cout << s << endl;\n",
+	}
+	var docs []Document
+	for i, body := range bodies {
+		docs = append(docs, Document{ID: fmt.Sprint(i), Author: fmt.Sprint("author", i), Source: template + body + "}\n"})
+	}
+	docs = append(docs, Document{ID: "copy", Author: "copier", Source: docs[3].Source})
+
+	pairs := Analyze(docs, Options{Common: 3, MinSimilarity: 0.3})
+	if len(pairs) != 1 || pairs[0].A != "3" || pairs[0].B != "copy" {
+		t.Fatalf("expected only the copy to be reported, got %+v", pairs)
+	}
+}
@@ -0,0 +1,180 @@
+package plagiarism
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Language selects the comment syntax and keywords used by the tokenizer
+type Language int
+
+const (
+	// LanguageC covers C and C++
+	LanguageC Language = iota
+	LanguagePython
+)
+
+// Normalized token texts, renaming a variable or changing a constant leaves the token stream unchanged
+const (
+	tokenIdentifier = "id"
+	tokenNumber     = "num"
+	tokenString     = "str"
+)
+
+// Token is one normalized token of a source and the lines it spans
+type Token struct {
+	Text      string
+	StartLine int
+	EndLine   int
+}
+
+var cKeywords = wordSet(`auto bool break case catch char class const constexpr continue default delete do double
+	else enum extern false float for friend goto if inline int long namespace new nullptr operator private protected
+	public register return short signed sizeof static struct switch template this throw true try typedef typename
+	union unsigned using virtual void volatile while`)
+
+var pythonKeywords = wordSet(`and as assert async await break class continue def del elif else except False finally
+	for from global if import in is lambda None nonlocal not or pass raise return True try while with yield`)
+
+// Longest operators first so that the tokenizer always takes the longest match
+var operators = []string{
+	"<<=", ">>=", "**=", "//=", "...",
+	"==", "!=", "<=", ">=", "&&", "||", "++", "--", "<<", ">>", "->", "::", "+=", "-=", "*=", "/=", "%=",
+	"&=", "|=", "^=", "**", "//",
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// Tokenize splits a source into normalized tokens. Comments, whitespace and C preprocessor lines are dropped,
+// identifiers that are not keywords become "id", numbers "num" and string or character literals "str"
+func Tokenize(source string, language Language) []Token {
+	keywords := cKeywords
+	if language == LanguagePython {
+		keywords = pythonKeywords
+	}
+
+	var tokens []Token
+	src := []rune(source)
+	line := 1
+	lineStart := true
+	emit := func(text string, startLine int) {
+		tokens = append(tokens, Token{Text: text, StartLine: startLine, EndLine: line})
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			lineStart = true
+			i++
+			continue
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case language == LanguageC && c == '#' && lineStart:
+			// Preprocessor directives, continued lines included
+			for i < len(src) && src[i] != '\n' {
+				if src[i] == '\\' && i+1 < len(src) && src[i+1] == '\n' {
+					line++
+					i++
+				}
+				i++
+			}
+			continue
+		case language == LanguagePython && c == '#',
+			language == LanguageC && c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case language == LanguageC && c == '/' && i+1 < len(src) && src[i+1] == '*':
+			i += 2
+			for i < len(src) && !(src[i] == '*' && i+1 < len(src) && src[i+1] == '/') {
+				if src[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+			continue
+		}
+		lineStart = false
+		startLine := line
+
+		switch {
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || unicode.IsLetter(src[i]) || unicode.IsDigit(src[i])) {
+				i++
+			}
+			word := string(src[start:i])
+			// Python string prefixes such as f"..." or rb'...'
+			if language == LanguagePython && i < len(src) && (src[i] == '"' || src[i] == '\'') && len(word) <= 2 && strings.Trim(strings.ToLower(word), "rbfu") == "" {
+				i = skipString(src, i, language, &line)
+				emit(tokenString, startLine)
+				continue
+			}
+			if keywords[word] {
+				emit(word, startLine)
+			} else {
+				emit(tokenIdentifier, startLine)
+			}
+		case unicode.IsDigit(c) || c == '.' && i+1 < len(src) && unicode.IsDigit(src[i+1]):
+			for i < len(src) && (src[i] == '.' || src[i] == '_' || src[i] == '\'' && language == LanguageC ||
+				unicode.IsLetter(src[i]) || unicode.IsDigit(src[i])) {
+				i++
+			}
+			emit(tokenNumber, startLine)
+		case c == '"' || c == '\'':
+			i = skipString(src, i, language, &line)
+			emit(tokenString, startLine)
+		default:
+			op := string(c)
+			for _, candidate := range operators {
+				if strings.HasPrefix(string(src[i:min(i+len(candidate), len(src))]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			i += len([]rune(op))
+			emit(op, startLine)
+		}
+	}
+	return tokens
+}
+
+// skipString returns the index after the string literal starting at i, counting the lines it spans
+func skipString(src []rune, i int, language Language, line *int) int {
+	quote := src[i]
+	if language == LanguagePython && i+2 < len(src) && src[i+1] == quote && src[i+2] == quote {
+		i += 3
+		for i < len(src) && !(src[i] == quote && i+2 < len(src) && src[i+1] == quote && src[i+2] == quote) {
+			i = skipChar(src, i, line)
+		}
+		return min(i+3, len(src))
+	}
+
+	i++
+	for i < len(src) && src[i] != quote && src[i] != '\n' {
+		i = skipChar(src, i, line)
+	}
+	return min(i+1, len(src))
+}
+
+// skipChar moves past one character of a string literal, escapes included
+func skipChar(src []rune, i int, line *int) int {
+	if src[i] == '\\' && i+1 < len(src) {
+		i++
+	}
+	if src[i] == '\n' {
+		*line++
+	}
+	return i + 1
+}
@@ -0,0 +1,46 @@
+package plagiarism
+
+import (
+	"slices"
+	"testing"
+)
+
+func texts(tokens []Token) []string {
+	res := make([]string, len(tokens))
+	for i, token := range tokens {
+		res[i] = token.Text
+	}
+	return res
+}
+
+func TestTokenizeC(t *testing.T) {
+	source := "#include <stdio.h>\n// read n\nint main() {\n    int n = 10; /* limit\n */ printf(\"%d\\n\", n << 1);\n}\n"
+	expected := []string{"int", "id", "(", ")", "{", "int", "id", "=", "num", ";", "id", "(", "str", ",", "id", "<<", "num", ")", ";", "}"}
+	tokens := Tokenize(source, LanguageC)
+	if got := texts(tokens); !slices.Equal(got, expected) {
+		t.Fatalf("unexpected tokens %v, expected %v", got, expected)
+	}
+	if tokens[0].StartLine != 3 || tokens[10].StartLine != 5 || tokens[len(tokens)-1].StartLine != 6 {
+		t.Errorf("unexpected lines %d, %d, %d", tokens[0].StartLine, tokens[10].StartLine, tokens[len(tokens)-1].StartLine)
+	}
+}
+
+func TestTokenizePython(t *testing.T) {
+	source := "# sum\ndef total(xs):\n    \"\"\"Adds\n    numbers\"\"\"\n    return sum(xs) // 2 + f'{x}'\n"
+	expected := []string{"def", "id", "(", "id", ")", ":", "str", "return", "id", "(", "id", ")", "//", "num", "+", "str"}
+	tokens := Tokenize(source, LanguagePython)
+	if got := texts(tokens); !slices.Equal(got, expected) {
+		t.Fatalf("unexpected tokens %v, expected %v", got, expected)
+	}
+	if docstring := tokens[6]; docstring.StartLine != 3 || docstring.EndLine != 4 {
+		t.Errorf("docstring spans lines %d-%d, expected 3-4", docstring.StartLine, docstring.EndLine)
+	}
+}
+
+func TestTokenizeIgnoresNamesAndLayout(t *testing.T) {
+	a := "int main(){int a,b;scanf(\"%d %d\",&a,&b);printf(\"%d\",a+b);}"
+	b := "int main()\n{\n    int first, second; // numbers\n    scanf(\"%d%d\", &first, &second);\n    printf(\"%d\\n\", first + second);\n}\n"
+	if ta, tb := texts(Tokenize(a, LanguageC)), texts(Tokenize(b, LanguageC)); !slices.Equal(ta, tb) {
+		t.Errorf("expected equal token streams:\n%v\n%v", ta, tb)
+	}
+}
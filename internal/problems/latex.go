@@ -0,0 +1,282 @@
+package problems
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Symbols replaced by their Unicode counterpart, statements are markdown without math rendering
+var latexSymbols = map[string]string{
+	"le": "≤", "leq": "≤", "ge": "≥", "geq": "≥", "ne": "≠", "neq": "≠", "lt": "<", "gt": ">",
+	"cdot": "·", "times": "×", "div": "÷", "pm": "±", "ldots": "…", "dots": "…", "cdots": "…",
+	"infty": "∞", "sum": "∑", "prod": "∏", "in": "∈", "notin": "∉", "subset": "⊂", "cup": "∪", "cap": "∩",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "Rightarrow": "⇒", "oplus": "⊕", "land": "∧", "lor": "∨",
+	"neg": "¬", "lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉", "approx": "≈", "equiv": "≡",
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "lambda": "λ", "pi": "π", "sigma": "σ",
+	"mid": "|", "vert": "|", "ell": "ℓ", "quad": " ", "qquad": " ",
+	"bmod": "mod", "mod": "mod", "max": "max", "min": "min", "log": "log", "gcd": "gcd", "lcm": "lcm",
+	"left": "", "right": "", "limits": "", "displaystyle": "", "noindent": "", "newline": "\n",
+	"bigskip": "", "medskip": "", "smallskip": "", "par": "\n\n",
+}
+
+// Commands whose argument keeps its text with the given markdown around it
+var latexWrappers = map[string]string{
+	"textbf": "**", "bf": "**", "textit": "*", "emph": "*", "it": "*", "textsl": "*",
+	"texttt": "`", "t": "`", "tt": "`", "verb": "`",
+	"text": "", "mathrm": "", "mathit": "", "mathbf": "", "operatorname": "", "textrm": "", "mbox": "",
+	"textsf": "", "underline": "", "textup": "", "textnormal": "",
+}
+
+// Environments that only change layout, their content is kept as it is
+var latexLayouts = map[string]bool{
+	"center": true, "flushleft": true, "flushright": true, "itemize": true, "enumerate": true,
+	"math": true, "displaymath": true, "equation": true, "equation*": true,
+}
+
+var (
+	blankLines = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+	// Items of a list follow each other without blank lines
+	listGaps = regexp.MustCompile(`\n\n( *(- |1\. ))`)
+)
+
+type latexConverter struct {
+	src         []rune
+	pos         int
+	lists       []string
+	unsupported map[string]bool
+}
+
+// LatexToMarkdown converts the LaTeX of a Polygon statement to markdown. Formatting, lists and math
+// symbols are translated; other commands and environments keep their text and are listed as unsupported
+func LatexToMarkdown(tex string) (string, []string) {
+	c := &latexConverter{src: []rune(tex), unsupported: make(map[string]bool)}
+	text := c.convert(false, 0)
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	text = listGaps.ReplaceAllString(text, "\n$1")
+
+	unsupported := make([]string, 0, len(c.unsupported))
+	for name := range c.unsupported {
+		unsupported = append(unsupported, name)
+	}
+	sort.Strings(unsupported)
+	return strings.TrimSpace(text), unsupported
+}
+
+func (c *latexConverter) peek(offset int) rune {
+	if c.pos+offset < len(c.src) {
+		return c.src[c.pos+offset]
+	}
+	return 0
+}
+
+// convert reads until the end of input, a closing brace when inGroup or the closing run of dollars
+// of the current formula when dollars is not zero
+func (c *latexConverter) convert(inGroup bool, dollars int) string {
+	math := dollars > 0
+	var sb strings.Builder
+	for c.pos < len(c.src) {
+		r := c.src[c.pos]
+		switch {
+		case r == '}' && inGroup:
+			c.pos++
+			return sb.String()
+		case r == '{':
+			c.pos++
+			sb.WriteString(c.convert(true, dollars))
+		case r == '$':
+			run := c.dollarRun()
+			if math {
+				if run == dollars {
+					return sb.String()
+				}
+				continue
+			}
+			formula := strings.TrimSpace(c.convert(false, run))
+			// $$...$$ and Polygon's $$$$$$...$$$$$$ are display formulas
+			if run == 2 || run == 6 {
+				sb.WriteString("\n\n" + formula + "\n\n")
+			} else {
+				sb.WriteString(formula)
+			}
+		case r == '\\':
+			sb.WriteString(c.command(dollars))
+		case r == '%':
+			for c.pos < len(c.src) && c.src[c.pos] != '\n' {
+				c.pos++
+			}
+		case r == '~':
+			c.pos++
+			sb.WriteRune(' ')
+		case !math && r == '-' && c.peek(1) == '-':
+			if c.peek(2) == '-' {
+				c.pos += 3
+				sb.WriteString("—")
+			} else {
+				c.pos += 2
+				sb.WriteString("–")
+			}
+		case !math && (r == '`' && c.peek(1) == '`' || r == '\'' && c.peek(1) == '\''):
+			c.pos += 2
+			sb.WriteRune('"')
+		case !math && r == '<' && c.peek(1) == '<':
+			c.pos += 2
+			sb.WriteRune('«')
+		case !math && r == '>' && c.peek(1) == '>':
+			c.pos += 2
+			sb.WriteRune('»')
+		case math && (r == '^' || r == '_') && c.peek(1) == '{':
+			c.pos += 2
+			group := c.convert(true, dollars)
+			if len([]rune(group)) > 1 {
+				group = "(" + group + ")"
+			}
+			sb.WriteRune(r)
+			sb.WriteString(group)
+		default:
+			c.pos++
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func (c *latexConverter) dollarRun() int {
+	run := 0
+	for c.pos < len(c.src) && c.src[c.pos] == '$' {
+		run++
+		c.pos++
+	}
+	return run
+}
+
+// argument reads the next {...} group, or nothing when the command has no argument
+func (c *latexConverter) argument(dollars int) string {
+	for c.pos < len(c.src) && c.src[c.pos] == ' ' {
+		c.pos++
+	}
+	if c.peek(0) != '{' {
+		return ""
+	}
+	c.pos++
+	return c.convert(true, dollars)
+}
+
+// rawArgument reads the next {...} group without converting it, for names and URLs
+func (c *latexConverter) rawArgument() string {
+	if c.peek(0) != '{' {
+		return ""
+	}
+	end := c.pos + 1
+	for end < len(c.src) && c.src[end] != '}' {
+		end++
+	}
+	arg := string(c.src[c.pos+1 : end])
+	c.pos = min(end+1, len(c.src))
+	return arg
+}
+
+// command converts the command starting at the backslash under the cursor
+func (c *latexConverter) command(dollars int) string {
+	c.pos++
+	if c.pos >= len(c.src) {
+		return ""
+	}
+	first := c.src[c.pos]
+	if !unicode.IsLetter(first) {
+		c.pos++
+		switch first {
+		case '\\':
+			return "  \n"
+		case ',', ';', ':', '!', ' ':
+			return " "
+		default:
+			return string(first)
+		}
+	}
+
+	start := c.pos
+	for c.pos < len(c.src) && unicode.IsLetter(c.src[c.pos]) {
+		c.pos++
+	}
+	name := string(c.src[start:c.pos])
+
+	switch name {
+	case "begin":
+		env := c.rawArgument()
+		if env == "itemize" || env == "enumerate" {
+			c.lists = append(c.lists, env)
+		}
+		if env == "tabular" {
+			// Column specification
+			c.rawArgument()
+		}
+		if !latexLayouts[env] {
+			c.unsupported["environment "+env] = true
+		}
+		return "\n"
+	case "end":
+		env := c.rawArgument()
+		if (env == "itemize" || env == "enumerate") && len(c.lists) > 0 {
+			c.lists = c.lists[:len(c.lists)-1]
+		}
+		return "\n"
+	case "item":
+		for c.peek(0) == ' ' {
+			c.pos++
+		}
+		indent := strings.Repeat("  ", max(len(c.lists)-1, 0))
+		if len(c.lists) > 0 && c.lists[len(c.lists)-1] == "enumerate" {
+			return "\n" + indent + "1. "
+		}
+		return "\n" + indent + "- "
+	case "frac", "dfrac", "tfrac":
+		numerator := c.argument(dollars)
+		denominator := c.argument(dollars)
+		return "(" + numerator + ")/(" + denominator + ")"
+	case "sqrt":
+		return "√(" + c.argument(dollars) + ")"
+	case "href":
+		url := c.rawArgument()
+		return "[" + c.argument(dollars) + "](" + url + ")"
+	case "url":
+		url := c.rawArgument()
+		return "<" + url + ">"
+	case "includegraphics":
+		c.skipOptions()
+		c.rawArgument()
+		c.unsupported["images"] = true
+		return ""
+	case "section", "subsection":
+		if c.peek(0) == '*' {
+			c.pos++
+		}
+		return "\n\n### " + c.argument(dollars) + "\n\n"
+	}
+
+	if symbol, ok := latexSymbols[name]; ok {
+		return symbol
+	}
+	if wrapper, ok := latexWrappers[name]; ok {
+		text := c.argument(dollars)
+		if text == "" || dollars > 0 {
+			return text
+		}
+		return wrapper + text + wrapper
+	}
+	c.unsupported["\\"+name] = true
+	c.skipOptions()
+	return c.argument(dollars)
+}
+
+// skipOptions drops an optional [...] argument
+func (c *latexConverter) skipOptions() {
+	if c.peek(0) != '[' {
+		return
+	}
+	for c.pos < len(c.src) && c.src[c.pos] != ']' {
+		c.pos++
+	}
+	c.pos++
+}
@@ -0,0 +1,499 @@
+package problems
+
+import (
+	"Codium/internal/judge"
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxPolygonFile bounds the statements, checker and validator read from a package
+	MaxPolygonFile = 1 << 20
+	// MaxSampleSize bounds the tests copied into the statement as samples
+	MaxSampleSize = 4 << 10
+)
+
+// Statement languages in order of preference
+var polygonLanguages = []string{"romanian", "english"}
+
+// Standard Polygon checkers the built-in checker modes replace
+var polygonCheckers = map[string]struct {
+	mode    judge.CheckerMode
+	epsilon float64
+}{
+	"std::fcmp.cpp":  {judge.CheckerExact, 0},
+	"std::wcmp.cpp":  {judge.CheckerWhitespace, 0},
+	"std::ncmp.cpp":  {judge.CheckerWhitespace, 0},
+	"std::icmp.cpp":  {judge.CheckerWhitespace, 0},
+	"std::rcmp4.cpp": {judge.CheckerFloat, 1e-4},
+	"std::rcmp6.cpp": {judge.CheckerFloat, 1e-6},
+	"std::rcmp9.cpp": {judge.CheckerFloat, 1e-9},
+}
+
+type polygonSource struct {
+	Path string `xml:"path,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type polygonTestset struct {
+	Name          string `xml:"name,attr"`
+	TimeLimit     int    `xml:"time-limit"`
+	MemoryLimit   int64  `xml:"memory-limit"`
+	TestCount     int    `xml:"test-count"`
+	InputPattern  string `xml:"input-path-pattern"`
+	AnswerPattern string `xml:"answer-path-pattern"`
+	Tests         []struct {
+		Sample bool    `xml:"sample,attr"`
+		Group  string  `xml:"group,attr"`
+		Points float64 `xml:"points,attr"`
+	} `xml:"tests>test"`
+	Groups []struct {
+		Name         string  `xml:"name,attr"`
+		Points       float64 `xml:"points,attr"`
+		PointsPolicy string  `xml:"points-policy,attr"`
+		Dependencies []struct {
+			Group string `xml:"group,attr"`
+		} `xml:"dependencies>dependency"`
+	} `xml:"groups>group"`
+}
+
+// polygonProblem is the part of problem.xml the importer reads
+type polygonProblem struct {
+	ShortName string `xml:"short-name,attr"`
+	Names     []struct {
+		Language string `xml:"language,attr"`
+		Value    string `xml:"value,attr"`
+	} `xml:"names>name"`
+	Judging struct {
+		InputFile  string           `xml:"input-file,attr"`
+		OutputFile string           `xml:"output-file,attr"`
+		Testsets   []polygonTestset `xml:"testset"`
+	} `xml:"judging"`
+	Checker *struct {
+		Name   string        `xml:"name,attr"`
+		Source polygonSource `xml:"source"`
+	} `xml:"assets>checker"`
+	Interactor *struct{} `xml:"assets>interactor"`
+	Validators []struct {
+		Source polygonSource `xml:"source"`
+	} `xml:"assets>validators>validator"`
+	Solutions []struct{} `xml:"assets>solutions>solution"`
+	Tags      []struct {
+		Value string `xml:"value,attr"`
+	} `xml:"tags>tag"`
+}
+
+type polygonTest struct {
+	name   string
+	input  *zip.File
+	answer *zip.File
+}
+
+// PolygonPackage is a problem read from a Polygon package archive
+type PolygonPackage struct {
+	Spec     Spec
+	Subtasks []Subtask
+	// Validator is the source of the package's testlib validator, nil when there is none
+	Validator []byte
+	// Unsupported lists what the import leaves out or approximates
+	Unsupported []string
+	tests       []polygonTest
+	files       map[string]*zip.File
+}
+
+// ReadPolygonPackage reads problem.xml, the statement, tests, checker and validator of a Polygon package.
+// Features Codium cannot represent are reported in Unsupported, interactive problems are rejected.
+func ReadPolygonPackage(r io.ReaderAt, size int64) (*PolygonPackage, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %v", err)
+	}
+	p := &PolygonPackage{files: make(map[string]*zip.File)}
+	for _, file := range archive.File {
+		if !file.FileInfo().IsDir() {
+			p.files[path.Clean(file.Name)] = file
+		}
+	}
+
+	descriptor, err := p.readFile("problem.xml")
+	if err != nil {
+		return nil, err
+	}
+	var problem polygonProblem
+	err = xml.Unmarshal(descriptor, &problem)
+	if err != nil {
+		return nil, fmt.Errorf("invalid problem.xml: %v", err)
+	}
+	if problem.Interactor != nil {
+		return nil, fmt.Errorf("interactive problems are not supported")
+	}
+
+	var testset *polygonTestset
+	for i := range problem.Judging.Testsets {
+		if problem.Judging.Testsets[i].Name == "tests" {
+			testset = &problem.Judging.Testsets[i]
+		} else {
+			p.unsupported("testset %q is ignored, only \"tests\" is imported", problem.Judging.Testsets[i].Name)
+		}
+	}
+	if testset == nil {
+		return nil, fmt.Errorf("package has no \"tests\" testset")
+	}
+	if file := problem.Judging.InputFile; file != "" && file != "stdin" {
+		p.unsupported("input is read from standard input instead of %v", file)
+	}
+	if file := problem.Judging.OutputFile; file != "" && file != "stdout" {
+		p.unsupported("output is written to standard output instead of %v", file)
+	}
+
+	p.Spec = Spec{
+		Slug:          strings.ToLower(problem.ShortName),
+		TimeLimitMs:   int32(min(max(testset.TimeLimit, MinTimeLimitMs), MaxTimeLimitMs)),
+		MemoryLimitMb: int32(min(max(testset.MemoryLimit>>20, MinMemoryLimitMb), MaxMemoryLimitMb)),
+	}
+	if int(p.Spec.TimeLimitMs) != testset.TimeLimit {
+		p.unsupported("time limit of %v ms is outside %v-%v ms, set to %v ms", testset.TimeLimit, MinTimeLimitMs, MaxTimeLimitMs, p.Spec.TimeLimitMs)
+	}
+	if int64(p.Spec.MemoryLimitMb) != testset.MemoryLimit>>20 {
+		p.unsupported("memory limit of %v MB is outside %v-%v MB, set to %v MB", testset.MemoryLimit>>20, MinMemoryLimitMb, MaxMemoryLimitMb, p.Spec.MemoryLimitMb)
+	}
+	if !slugRegex.MatchString(p.Spec.Slug) {
+		p.Spec.Slug = Slug(problem.ShortName)
+	}
+	for _, tag := range problem.Tags {
+		p.Spec.Tags = append(p.Spec.Tags, tag.Value)
+	}
+
+	language := ""
+	for _, preferred := range polygonLanguages {
+		for _, name := range problem.Names {
+			if name.Language == preferred && language == "" {
+				language = preferred
+			}
+		}
+	}
+	if language == "" && len(problem.Names) > 0 {
+		language = problem.Names[0].Language
+	}
+	for _, name := range problem.Names {
+		if name.Language == language {
+			p.Spec.Title = name.Value
+		}
+	}
+	if p.Spec.Title == "" {
+		p.Spec.Title = problem.ShortName
+	}
+	p.readStatement(language)
+
+	err = p.readTests(testset)
+	if err != nil {
+		return nil, err
+	}
+	err = p.readSubtasks(testset)
+	if err != nil {
+		return nil, err
+	}
+	err = p.readChecker(problem)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, validator := range problem.Validators {
+		if i > 0 {
+			p.unsupported("only the first validator is run, %v is ignored", validator.Source.Path)
+			continue
+		}
+		if !strings.HasPrefix(validator.Source.Type, "cpp") {
+			p.unsupported("validator %v is not C++ and is not run", validator.Source.Path)
+			continue
+		}
+		p.Validator, err = p.readFile(validator.Source.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(problem.Solutions) > 0 {
+		p.unsupported("%v solutions are not imported", len(problem.Solutions))
+	}
+
+	err = p.Spec.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// unsupported adds a line to the report, each line once
+func (p *PolygonPackage) unsupported(format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+	if !slices.Contains(p.Unsupported, line) {
+		p.Unsupported = append(p.Unsupported, line)
+	}
+}
+
+func (p *PolygonPackage) readFile(name string) ([]byte, error) {
+	file, ok := p.files[path.Clean(name)]
+	if !ok {
+		return nil, fmt.Errorf("package has no %v", name)
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %v: %v", name, err)
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, MaxPolygonFile+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %v", name, err)
+	}
+	if len(data) > MaxPolygonFile {
+		return nil, fmt.Errorf("%v is larger than %v bytes", name, MaxPolygonFile)
+	}
+	return data, nil
+}
+
+// readStatement converts the LaTeX sections of the statement in a language, missing sections stay empty
+func (p *PolygonPackage) readStatement(language string) {
+	section := func(name string) string {
+		for _, dir := range []string{"statement-sections", "statements"} {
+			tex, err := p.readFile(path.Join(dir, language, name+".tex"))
+			if err != nil {
+				continue
+			}
+			text, unsupported := LatexToMarkdown(string(tex))
+			for _, feature := range unsupported {
+				p.unsupported("statement uses LaTeX %v, only its text was kept", feature)
+			}
+			return text
+		}
+		return ""
+	}
+
+	legend := section("legend")
+	if legend == "" {
+		p.unsupported("package has no %v statement legend", language)
+	}
+	if notes := section("notes"); notes != "" {
+		legend += "\n\n### Note\n\n" + notes
+	}
+	p.Spec.Statement = legend
+	p.Spec.InputFormat = section("input")
+	p.Spec.OutputFormat = section("output")
+	if section("tutorial") != "" {
+		p.unsupported("the tutorial is not imported")
+	}
+}
+
+// polygonPath expands a path pattern such as tests/%02d for a test number
+func polygonPath(pattern string, number int) string {
+	return path.Clean(fmt.Sprintf(pattern, number))
+}
+
+func (p *PolygonPackage) readTests(testset *polygonTestset) error {
+	count := max(testset.TestCount, len(testset.Tests))
+	if count == 0 {
+		return fmt.Errorf("package has no tests")
+	}
+	if count > MaxTests {
+		return fmt.Errorf("package has %v tests, at most %v are allowed", count, MaxTests)
+	}
+	if testset.InputPattern == "" || testset.AnswerPattern == "" {
+		return fmt.Errorf("testset has no input or answer path pattern")
+	}
+
+	width := max(2, len(strconv.Itoa(count)))
+	extraSamples := 0
+	for number := 1; number <= count; number++ {
+		test := polygonTest{name: fmt.Sprintf("%0*d", width, number)}
+		test.input = p.files[polygonPath(testset.InputPattern, number)]
+		test.answer = p.files[polygonPath(testset.AnswerPattern, number)]
+		if test.input == nil || test.answer == nil {
+			return fmt.Errorf("test %v has no input or answer, export a full package with generated tests", number)
+		}
+		p.tests = append(p.tests, test)
+
+		if number <= len(testset.Tests) && testset.Tests[number-1].Sample {
+			if len(p.Spec.Samples) == MaxSamples {
+				extraSamples++
+				continue
+			}
+			input, inputErr := p.readFile(test.input.Name)
+			answer, answerErr := p.readFile(test.answer.Name)
+			if inputErr != nil || answerErr != nil || len(input) > MaxSampleSize || len(answer) > MaxSampleSize {
+				p.unsupported("sample test %v is too large to show in the statement", number)
+				continue
+			}
+			p.Spec.Samples = append(p.Spec.Samples, Sample{Input: string(input), Output: string(answer)})
+		}
+	}
+	if extraSamples > 0 {
+		p.unsupported("only the first %v sample tests are shown in the statement, %v more are only used for judging", MaxSamples, extraSamples)
+	}
+	return nil
+}
+
+// readSubtasks turns test groups into subtasks, a group scores its points only if all of its tests pass
+func (p *PolygonPackage) readSubtasks(testset *polygonTestset) error {
+	grouped := false
+	pointsPerTest := false
+	for _, test := range testset.Tests {
+		grouped = grouped || test.Group != ""
+		pointsPerTest = pointsPerTest || test.Points != 0
+	}
+	if !grouped {
+		if pointsPerTest {
+			p.unsupported("points of individual tests are not supported, the problem is scored as a whole")
+		}
+		return nil
+	}
+
+	// Groups in the order of <groups>, then the ones only named by tests
+	var order []string
+	numbers := make(map[string]int32)
+	addGroup := func(name string) {
+		if _, ok := numbers[name]; !ok {
+			order = append(order, name)
+			numbers[name] = int32(len(order))
+		}
+	}
+	for _, group := range testset.Groups {
+		addGroup(group.Name)
+	}
+	tests := make(map[string][]int)
+	points := make(map[string]float64)
+	var ungrouped []string
+	for i, test := range testset.Tests {
+		if test.Group == "" {
+			ungrouped = append(ungrouped, strconv.Itoa(i+1))
+			continue
+		}
+		addGroup(test.Group)
+		tests[test.Group] = append(tests[test.Group], i+1)
+		points[test.Group] += test.Points
+	}
+	if len(ungrouped) > 0 {
+		p.unsupported("tests %v belong to no group and are not scored", strings.Join(ungrouped, ", "))
+	}
+
+	declared := make(map[string]int)
+	for i, group := range testset.Groups {
+		declared[group.Name] = i
+	}
+	for _, name := range order {
+		if len(tests[name]) == 0 {
+			return fmt.Errorf("group %q has no tests", name)
+		}
+		subtask := Subtask{Number: numbers[name], Tests: testRanges(tests[name], p.tests), DependsOn: []int32{}}
+		total := points[name]
+		if i, ok := declared[name]; ok {
+			group := testset.Groups[i]
+			if group.PointsPolicy == "each-test" {
+				p.unsupported("group %q scores each test, it is scored as a whole group", name)
+			}
+			if group.Points != 0 {
+				total = group.Points
+			}
+			for _, dependency := range group.Dependencies {
+				number, ok := numbers[dependency.Group]
+				if !ok || number >= subtask.Number {
+					p.unsupported("group %q can only depend on earlier groups, the dependency on %q is dropped", name, dependency.Group)
+					continue
+				}
+				subtask.DependsOn = append(subtask.DependsOn, number)
+			}
+		}
+		subtask.Points = int32(math.Round(total))
+		if float64(subtask.Points) != total {
+			p.unsupported("group %q is worth %v points, rounded to %v", name, total, subtask.Points)
+		}
+		p.Subtasks = append(p.Subtasks, subtask)
+	}
+	return ValidateSubtasks(p.Subtasks, p.TestNames())
+}
+
+// testRanges writes test numbers as the shortest list of names and inclusive ranges
+func testRanges(numbers []int, tests []polygonTest) []string {
+	var patterns []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if i == j {
+			patterns = append(patterns, tests[numbers[i]-1].name)
+		} else {
+			patterns = append(patterns, fmt.Sprintf("%d-%d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return patterns
+}
+
+func (p *PolygonPackage) readChecker(problem polygonProblem) error {
+	p.Spec.CheckerMode = judge.CheckerExact
+	if problem.Checker == nil {
+		p.unsupported("package has no checker, outputs are compared exactly")
+		return nil
+	}
+	if standard, ok := polygonCheckers[problem.Checker.Name]; ok {
+		p.Spec.CheckerMode = standard.mode
+		p.Spec.CheckerEpsilon = standard.epsilon
+		return nil
+	}
+	if !strings.HasPrefix(problem.Checker.Source.Type, "cpp") {
+		return fmt.Errorf("checker %v is not written in C++", problem.Checker.Source.Path)
+	}
+	source, err := p.readFile(problem.Checker.Source.Path)
+	if err != nil {
+		return err
+	}
+	p.Spec.CheckerMode = judge.CheckerCustom
+	p.Spec.CheckerSource = string(source)
+	return nil
+}
+
+// TestNames lists the names the tests are imported under, in order
+func (p *PolygonPackage) TestNames() []string {
+	names := make([]string, len(p.tests))
+	for i, test := range p.tests {
+		names[i] = test.name
+	}
+	return names
+}
+
+// OpenInput opens the input of the i-th test, for validation
+func (p *PolygonPackage) OpenInput(i int) (io.ReadCloser, error) {
+	return p.tests[i].input.Open()
+}
+
+// WriteTests writes the tests as a zip archive of NN.in and NN.out files, the layout ExtractTests reads
+func (p *PolygonPackage) WriteTests(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	for _, test := range p.tests {
+		for _, file := range []struct {
+			name string
+			src  *zip.File
+		}{{test.name + ".in", test.input}, {test.name + ".out", test.answer}} {
+			dst, err := archive.Create(file.name)
+			if err != nil {
+				return fmt.Errorf("failed to add %v: %v", file.name, err)
+			}
+			src, err := file.src.Open()
+			if err != nil {
+				return fmt.Errorf("failed to open %v: %v", file.src.Name, err)
+			}
+			_, err = io.Copy(dst, io.LimitReader(src, MaxTestSetSize+1))
+			src.Close()
+			if err != nil {
+				return fmt.Errorf("failed to copy %v: %v", file.src.Name, err)
+			}
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,223 @@
+package problems
+
+import (
+	"Codium/internal/judge"
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const polygonXML = `<?xml version="1.0" encoding="utf-8" standalone="no"?>
+<problem revision="7" short-name="Sum-Pairs" url="https://polygon.codeforces.com/p/example/sum-pairs">
+    <names>
+        <name language="english" value="Sum of Pairs"/>
+        <name language="romanian" value="Suma perechilor"/>
+    </names>
+    <judging cpu-name="Intel(R) Core(TM) i3-8100 CPU @ 3.60GHz" cpu-speed="3600" input-file="" output-file="">
+        <testset name="tests">
+            <time-limit>2000</time-limit>
+            <memory-limit>268435456</memory-limit>
+            <test-count>4</test-count>
+            <input-path-pattern>tests/%02d</input-path-pattern>
+            <answer-path-pattern>tests/%02d.a</answer-path-pattern>
+            <tests>
+                <test method="manual" sample="true" group="samples"/>
+                <test cmd="gen 1" method="generated" group="small" points="15"/>
+                <test cmd="gen 2" method="generated" group="small" points="15"/>
+                <test cmd="gen 3" method="generated" group="large"/>
+            </tests>
+            <groups>
+                <group name="samples" points="0" points-policy="complete-group"/>
+                <group name="small" points-policy="complete-group">
+                    <dependencies><dependency group="samples"/></dependencies>
+                </group>
+                <group name="large" points="70" points-policy="each-test">
+                    <dependencies><dependency group="small"/></dependencies>
+                </group>
+            </groups>
+        </testset>
+        <testset name="pretests">
+            <time-limit>2000</time-limit>
+            <memory-limit>268435456</memory-limit>
+            <test-count>0</test-count>
+            <input-path-pattern>pretests/%02d</input-path-pattern>
+            <answer-path-pattern>pretests/%02d.a</answer-path-pattern>
+        </testset>
+    </judging>
+    <assets>
+        <checker name="std::rcmp6.cpp" type="testlib">
+            <source path="files/check.cpp" type="cpp.g++17"/>
+        </checker>
+        <validators>
+            <validator><source path="files/val.cpp" type="cpp.g++17"/></validator>
+        </validators>
+        <solutions>
+            <solution tag="main"><source path="solutions/main.cpp" type="cpp.g++17"/></solution>
+        </solutions>
+    </assets>
+    <tags><tag value="math"/><tag value="implementation"/></tags>
+</problem>`
+
+func polygonFiles() map[string]string {
+	return map[string]string{
+		"problem.xml":                            polygonXML,
+		"statement-sections/romanian/legend.tex": "Se dau $$$n$$$ numere \\textbf{naturale}.",
+		"statement-sections/romanian/input.tex":  "Pe prima linie se află $$$n$$$ ($$$1 \\le n \\le 10^{5}$$$).",
+		"statement-sections/romanian/output.tex": "Afișați suma.",
+		"statement-sections/romanian/notes.tex":  "\\begin{tabular}{c}x\\end{tabular}",
+		"statement-sections/english/legend.tex":  "You are given $$$n$$$ numbers.",
+		"tests/01":                               "2\n1 2\n",
+		"tests/01.a":                             "3\n",
+		"tests/02":                               "1\n5\n",
+		"tests/02.a":                             "5\n",
+		"tests/03":                               "3\n1 1 1\n",
+		"tests/03.a":                             "3\n",
+		"tests/04":                               "2\n100000 100000\n",
+		"tests/04.a":                             "200000\n",
+		"files/check.cpp":                        "#include \"testlib.h\"\nint main(){}",
+		"files/val.cpp":                          "#include \"testlib.h\"\nint main(){}",
+		"solutions/main.cpp":                     "int main(){}",
+	}
+}
+
+func readPolygon(t *testing.T, files map[string]string) (*PolygonPackage, error) {
+	t.Helper()
+	archive := buildZip(t, files)
+	return ReadPolygonPackage(archive, archive.Size())
+}
+
+func TestReadPolygonPackage(t *testing.T) {
+	p, err := readPolygon(t, polygonFiles())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec := p.Spec
+	if spec.Slug != "sum-pairs" || spec.Title != "Suma perechilor" {
+		t.Errorf("unexpected slug %q and title %q", spec.Slug, spec.Title)
+	}
+	if spec.TimeLimitMs != 2000 || spec.MemoryLimitMb != 256 {
+		t.Errorf("unexpected limits %v ms, %v MB", spec.TimeLimitMs, spec.MemoryLimitMb)
+	}
+	if spec.Statement != "Se dau n numere **naturale**.\n\n### Note\n\nx" {
+		t.Errorf("unexpected statement %q", spec.Statement)
+	}
+	if spec.InputFormat != "Pe prima linie se află n (1 ≤ n ≤ 10^5)." {
+		t.Errorf("unexpected input format %q", spec.InputFormat)
+	}
+	if spec.CheckerMode != judge.CheckerFloat || spec.CheckerEpsilon != 1e-6 || spec.CheckerSource != "" {
+		t.Errorf("expected the standard checker to become a float checker, got %v %v", spec.CheckerMode, spec.CheckerEpsilon)
+	}
+	if len(spec.Samples) != 1 || spec.Samples[0].Input != "2\n1 2\n" || spec.Samples[0].Output != "3\n" {
+		t.Errorf("unexpected samples %+v", spec.Samples)
+	}
+	if !slices.Equal(spec.Tags, []string{"math", "implementation"}) {
+		t.Errorf("unexpected tags %v", spec.Tags)
+	}
+	if string(p.Validator) != "#include \"testlib.h\"\nint main(){}" {
+		t.Errorf("unexpected validator %q", p.Validator)
+	}
+
+	expected := []Subtask{
+		{Number: 1, Points: 0, Tests: []string{"01"}, DependsOn: []int32{}},
+		{Number: 2, Points: 30, Tests: []string{"2-3"}, DependsOn: []int32{1}},
+		{Number: 3, Points: 70, Tests: []string{"04"}, DependsOn: []int32{2}},
+	}
+	if len(p.Subtasks) != len(expected) {
+		t.Fatalf("unexpected subtasks %+v", p.Subtasks)
+	}
+	for i, subtask := range p.Subtasks {
+		want := expected[i]
+		if subtask.Number != want.Number || subtask.Points != want.Points || !slices.Equal(subtask.Tests, want.Tests) || !slices.Equal(subtask.DependsOn, want.DependsOn) {
+			t.Errorf("subtask %v is %+v, expected %+v", i+1, subtask, want)
+		}
+	}
+
+	report := strings.Join(p.Unsupported, "\n")
+	for _, feature := range []string{`testset "pretests"`, `group "large" scores each test`, "environment tabular", "1 solutions"} {
+		if !strings.Contains(report, feature) {
+			t.Errorf("report does not mention %q:\n%s", feature, report)
+		}
+	}
+}
+
+func TestPolygonWriteTests(t *testing.T) {
+	p, err := readPolygon(t, polygonFiles())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = p.WriteTests(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests, err := ExtractTests(bytes.NewReader(buf.Bytes()), int64(buf.Len()), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) != 4 || tests[3].Name != "04" || tests[3].OutputSize != int64(len("200000\n")) {
+		t.Errorf("unexpected tests %+v", tests)
+	}
+}
+
+func TestReadPolygonPackageErrors(t *testing.T) {
+	interactive := polygonFiles()
+	interactive["problem.xml"] = strings.Replace(polygonXML, "<assets>", `<assets><interactor><source path="files/interactor.cpp" type="cpp.g++17"/></interactor>`, 1)
+	missingAnswer := polygonFiles()
+	delete(missingAnswer, "tests/04.a")
+	customChecker := polygonFiles()
+	customChecker["problem.xml"] = strings.Replace(polygonXML, "std::rcmp6.cpp", "check.cpp", 1)
+	javaChecker := polygonFiles()
+	javaChecker["problem.xml"] = strings.Replace(strings.Replace(polygonXML, "std::rcmp6.cpp", "Check.java", 1), `files/check.cpp" type="cpp.g++17"`, `files/Check.java" type="java21"`, 1)
+
+	cases := map[string]struct {
+		files map[string]string
+		err   string
+	}{
+		"no problem.xml":  {map[string]string{"tests/01": "1\n"}, "no problem.xml"},
+		"interactive":     {interactive, "interactive"},
+		"missing answer":  {missingAnswer, "test 4 has no input or answer"},
+		"non C++ checker": {javaChecker, "not written in C++"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := readPolygon(t, c.files)
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("expected an error containing %q, got %v", c.err, err)
+			}
+		})
+	}
+
+	p, err := readPolygon(t, customChecker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Spec.CheckerMode != judge.CheckerCustom || !strings.Contains(p.Spec.CheckerSource, "testlib.h") {
+		t.Errorf("expected the checker source to be imported, got %v", p.Spec.CheckerMode)
+	}
+}
+
+func TestLatexToMarkdown(t *testing.T) {
+	cases := []struct {
+		tex         string
+		markdown    string
+		unsupported []string
+	}{
+		{"Find $$$a_i \\cdot b_{i+1}$$$ for \\emph{every} $$$i$$$ --- quickly.", "Find a_i · b_(i+1) for *every* i — quickly.", nil},
+		{"\\begin{itemize}\n\\item first\n\\item \\texttt{second}\n\\end{itemize}", "- first\n- `second`", nil},
+		{"Answer modulo $$$10^9+7$$$.~See \\href{https://example.com}{here}. % comment", "Answer modulo 10^9+7. See [here](https://example.com).", nil},
+		{"$$$$$$\\frac{n}{2}$$$$$$", "(n)/(2)", nil},
+		{"\\foo{kept} \\includegraphics[width=5cm]{a.png}", "kept", []string{"\\foo", "images"}},
+	}
+	for _, c := range cases {
+		markdown, unsupported := LatexToMarkdown(c.tex)
+		if markdown != c.markdown {
+			t.Errorf("LatexToMarkdown(%q) = %q, want %q", c.tex, markdown, c.markdown)
+		}
+		if !slices.Equal(unsupported, c.unsupported) && len(unsupported)+len(c.unsupported) > 0 {
+			t.Errorf("LatexToMarkdown(%q) reported %v, want %v", c.tex, unsupported, c.unsupported)
+		}
+	}
+}
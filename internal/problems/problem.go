@@ -0,0 +1,156 @@
+package problems
+
+import (
+	"Codium/internal/judge"
+	"Codium/internal/text"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Difficulty string
+
+const (
+	DifficultyEasy   Difficulty = "easy"
+	DifficultyMedium Difficulty = "medium"
+	DifficultyHard   Difficulty = "hard"
+)
+
+const (
+	MinTimeLimitMs   = 100
+	MaxTimeLimitMs   = 10000
+	MinMemoryLimitMb = 16
+	MaxMemoryLimitMb = 1024
+	MaxSamples       = 10
+	MaxCheckerSource = 256 << 10
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type Sample struct {
+	Input       string `json:"input"`
+	Output      string `json:"output"`
+	Explanation string `json:"explanation"`
+}
+
+// Spec is everything an admin provides when creating or replacing a problem
+type Spec struct {
+	Slug          string     `json:"slug"`
+	Title         string     `json:"title"`
+	Statement     string     `json:"statement"`
+	InputFormat   string     `json:"input_format"`
+	OutputFormat  string     `json:"output_format"`
+	TimeLimitMs   int32      `json:"time_limit_ms"`
+	MemoryLimitMb int32      `json:"memory_limit_mb"`
+	Difficulty    Difficulty `json:"difficulty"`
+	Tags          []string   `json:"tags"`
+	Samples       []Sample   `json:"samples"`
+	// CheckerMode picks how outputs are compared, CheckerSource is only used by custom checkers
+	CheckerMode    judge.CheckerMode `json:"checker_mode"`
+	CheckerEpsilon float64           `json:"checker_epsilon"`
+	CheckerSource  string            `json:"checker_source"`
+}
+
+func ValidDifficulty(d Difficulty) bool {
+	return d == DifficultyEasy || d == DifficultyMedium || d == DifficultyHard
+}
+
+// Slug turns a title such as "Suma cifrelor" into "suma-cifrelor"
+func Slug(title string) string {
+	title = text.StripDiacritics(strings.ToLower(title))
+	var slug strings.Builder
+	dash := false
+	for _, c := range title {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			dash = false
+			slug.WriteRune(c)
+		} else {
+			dash = true
+		}
+	}
+	return slug.String()
+}
+
+// Normalize fills in the defaults of a spec and checks that it describes a usable problem
+func (s *Spec) Normalize() error {
+	s.Title = strings.TrimSpace(s.Title)
+	if s.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+
+	s.Slug = strings.TrimSpace(s.Slug)
+	if s.Slug == "" {
+		s.Slug = Slug(s.Title)
+	}
+	if !slugRegex.MatchString(s.Slug) {
+		return fmt.Errorf("invalid slug %q", s.Slug)
+	}
+
+	if s.TimeLimitMs == 0 {
+		s.TimeLimitMs = 1000
+	}
+	if s.TimeLimitMs < MinTimeLimitMs || s.TimeLimitMs > MaxTimeLimitMs {
+		return fmt.Errorf("time limit must be between %v and %v ms", MinTimeLimitMs, MaxTimeLimitMs)
+	}
+	if s.MemoryLimitMb == 0 {
+		s.MemoryLimitMb = 256
+	}
+	if s.MemoryLimitMb < MinMemoryLimitMb || s.MemoryLimitMb > MaxMemoryLimitMb {
+		return fmt.Errorf("memory limit must be between %v and %v MB", MinMemoryLimitMb, MaxMemoryLimitMb)
+	}
+
+	if s.Difficulty == "" {
+		s.Difficulty = DifficultyMedium
+	}
+	if !ValidDifficulty(s.Difficulty) {
+		return fmt.Errorf("unknown difficulty %q", s.Difficulty)
+	}
+
+	tags := make([]string, 0, len(s.Tags))
+	seen := make(map[string]bool)
+	for _, tag := range s.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	s.Tags = tags
+
+	if s.CheckerMode == "" {
+		s.CheckerMode = judge.CheckerExact
+	}
+	if !judge.ValidCheckerMode(s.CheckerMode) {
+		return fmt.Errorf("unknown checker mode %q", s.CheckerMode)
+	}
+	if s.CheckerEpsilon == 0 {
+		s.CheckerEpsilon = judge.DefaultEpsilon
+	}
+	if s.CheckerEpsilon < 0 || s.CheckerEpsilon > 1 {
+		return fmt.Errorf("checker epsilon must be between 0 and 1")
+	}
+	if s.CheckerMode == judge.CheckerCustom {
+		if strings.TrimSpace(s.CheckerSource) == "" {
+			return fmt.Errorf("custom checkers need a checker source")
+		}
+		if len(s.CheckerSource) > MaxCheckerSource {
+			return fmt.Errorf("checker source is larger than %v bytes", MaxCheckerSource)
+		}
+	} else {
+		s.CheckerSource = ""
+	}
+
+	if len(s.Samples) > MaxSamples {
+		return fmt.Errorf("at most %v samples are allowed", MaxSamples)
+	}
+	for i, sample := range s.Samples {
+		if sample.Output == "" {
+			return fmt.Errorf("sample %v has no output", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package problems
+
+import (
+	"Codium/internal/judge"
+	"slices"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	cases := map[string]string{
+		"Suma cifrelor":       "suma-cifrelor",
+		"  Șiruri și țări!  ": "siruri-si-tari",
+		"A+B":                 "a-b",
+		"---":                 "",
+	}
+	for title, want := range cases {
+		if got := Slug(title); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestNormalizeDefaults(t *testing.T) {
+	spec := Spec{Title: " Suma cifrelor ", Tags: []string{"Math", "math", " ", "greedy"}}
+	if err := spec.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Title != "Suma cifrelor" || spec.Slug != "suma-cifrelor" {
+		t.Errorf("unexpected title/slug %q %q", spec.Title, spec.Slug)
+	}
+	if spec.TimeLimitMs != 1000 || spec.MemoryLimitMb != 256 || spec.Difficulty != DifficultyMedium || spec.CheckerMode != judge.CheckerExact {
+		t.Errorf("unexpected defaults %+v", spec)
+	}
+	if !slices.Equal(spec.Tags, []string{"math", "greedy"}) {
+		t.Errorf("unexpected tags %v", spec.Tags)
+	}
+}
+
+func TestNormalizeRejects(t *testing.T) {
+	cases := map[string]Spec{
+		"no title":     {},
+		"bad slug":     {Title: "x", Slug: "Not A Slug"},
+		"time limit":   {Title: "x", TimeLimitMs: 50},
+		"memory limit": {Title: "x", MemoryLimitMb: 4096},
+		"difficulty":   {Title: "x", Difficulty: "impossible"},
+		"sample":       {Title: "x", Samples: []Sample{{Input: "1"}}},
+		"checker mode": {Title: "x", CheckerMode: "fuzzy"},
+		"epsilon":      {Title: "x", CheckerMode: judge.CheckerFloat, CheckerEpsilon: -1},
+		"no checker":   {Title: "x", CheckerMode: judge.CheckerCustom},
+	}
+	for name, spec := range cases {
+		if err := spec.Normalize(); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
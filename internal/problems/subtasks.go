@@ -0,0 +1,133 @@
+package problems
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxScore is what a problem without subtasks is worth, all of its tests form a single group
+const MaxScore = 100
+
+// Subtask groups tests, its points are only earned if every test of the group and of its dependencies passes
+type Subtask struct {
+	Number int32 `json:"number"`
+	Points int32 `json:"points"`
+	// Tests holds test names such as "07" or inclusive ranges such as "1-5"
+	Tests     []string `json:"tests"`
+	DependsOn []int32  `json:"depends_on"`
+}
+
+type SubtaskScore struct {
+	Number int32 `json:"number"`
+	Points int32 `json:"points"`
+	Score  int32 `json:"score"`
+	// Verdict is the first verdict that is not accepted among the tests of the group, or AC
+	Verdict string `json:"verdict"`
+}
+
+// matches reports whether a test name is selected by a pattern
+func matches(pattern string, name string) bool {
+	if pattern == name {
+		return true
+	}
+	number, err := strconv.Atoi(name)
+	if err != nil {
+		return false
+	}
+	from, to, isRange := strings.Cut(pattern, "-")
+	if !isRange {
+		single, err := strconv.Atoi(pattern)
+		return err == nil && single == number
+	}
+	low, errLow := strconv.Atoi(strings.TrimSpace(from))
+	high, errHigh := strconv.Atoi(strings.TrimSpace(to))
+	return errLow == nil && errHigh == nil && low <= number && number <= high
+}
+
+// ExpandTests lists the tests, in order, selected by the patterns of a subtask
+func ExpandTests(patterns []string, tests []string) []string {
+	var selected []string
+	for _, name := range tests {
+		for _, pattern := range patterns {
+			if matches(pattern, name) {
+				selected = append(selected, name)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// ValidateSubtasks numbers subtasks from 1 and checks them against the tests of the problem.
+// Dependencies must point to earlier subtasks, which keeps them free of cycles.
+func ValidateSubtasks(subtasks []Subtask, tests []string) error {
+	for i := range subtasks {
+		subtask := &subtasks[i]
+		subtask.Number = int32(i + 1)
+		if subtask.Points < 0 {
+			return fmt.Errorf("subtask %v has negative points", subtask.Number)
+		}
+		if len(subtask.Tests) == 0 {
+			return fmt.Errorf("subtask %v has no tests", subtask.Number)
+		}
+		for _, pattern := range subtask.Tests {
+			if len(ExpandTests([]string{pattern}, tests)) == 0 {
+				return fmt.Errorf("subtask %v: %q matches no test", subtask.Number, pattern)
+			}
+		}
+		for _, dependency := range subtask.DependsOn {
+			if dependency < 1 || dependency >= subtask.Number {
+				return fmt.Errorf("subtask %v can only depend on earlier subtasks, not %v", subtask.Number, dependency)
+			}
+		}
+		if subtask.DependsOn == nil {
+			subtask.DependsOn = []int32{}
+		}
+	}
+	return nil
+}
+
+// Score grades a submission from the verdicts of its tests. A subtask scores its points times the
+// lowest test score of its group, dependencies included; accepted tests score 1 and anything else 0.
+// Tests that did not run score 0 and are reported as skipped (SK). Without subtasks every test belongs to one group worth MaxScore.
+func Score(subtasks []Subtask, tests []string, verdicts map[string]string) (int32, int32, []SubtaskScore) {
+	if len(subtasks) == 0 {
+		subtasks = []Subtask{{Number: 1, Points: MaxScore, Tests: tests}}
+	}
+
+	// Groups with their dependencies, resolved in order since subtasks only depend on earlier ones
+	groups := make(map[int32][]string)
+	var total, maxScore int32
+	breakdown := make([]SubtaskScore, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		group := ExpandTests(subtask.Tests, tests)
+		for _, dependency := range subtask.DependsOn {
+			group = append(group, groups[dependency]...)
+		}
+		groups[subtask.Number] = group
+
+		result := SubtaskScore{Number: subtask.Number, Points: subtask.Points, Verdict: "AC"}
+		lowest := 1.0
+		if len(group) == 0 {
+			lowest = 0
+		}
+		for _, name := range group {
+			verdict, ran := verdicts[name]
+			if !ran {
+				verdict = "SK"
+			}
+			if verdict != "AC" {
+				lowest = 0
+				if result.Verdict == "AC" {
+					result.Verdict = verdict
+				}
+			}
+		}
+		result.Score = int32(float64(subtask.Points) * lowest)
+		total += result.Score
+		maxScore += subtask.Points
+		breakdown = append(breakdown, result)
+	}
+	return total, maxScore, breakdown
+}
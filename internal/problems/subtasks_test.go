@@ -0,0 +1,77 @@
+package problems
+
+import (
+	"slices"
+	"testing"
+)
+
+var sampleTests = []string{"01", "02", "03", "04", "05", "06"}
+
+func TestExpandTests(t *testing.T) {
+	got := ExpandTests([]string{"2-4", "06", "9"}, sampleTests)
+	if !slices.Equal(got, []string{"02", "03", "04", "06"}) {
+		t.Errorf("unexpected tests %v", got)
+	}
+}
+
+func TestValidateSubtasks(t *testing.T) {
+	subtasks := []Subtask{
+		{Points: 30, Tests: []string{"1-2"}},
+		{Points: 70, Tests: []string{"3-6"}, DependsOn: []int32{1}},
+	}
+	if err := ValidateSubtasks(subtasks, sampleTests); err != nil {
+		t.Fatal(err)
+	}
+	if subtasks[0].Number != 1 || subtasks[1].Number != 2 || subtasks[0].DependsOn == nil {
+		t.Errorf("unexpected subtasks %+v", subtasks)
+	}
+
+	invalid := map[string][]Subtask{
+		"unknown test":       {{Points: 10, Tests: []string{"7-9"}}},
+		"no tests":           {{Points: 10}},
+		"negative points":    {{Points: -1, Tests: []string{"1"}}},
+		"forward dependency": {{Points: 10, Tests: []string{"1"}, DependsOn: []int32{2}}, {Points: 10, Tests: []string{"2"}}},
+		"self dependency":    {{Points: 10, Tests: []string{"1"}, DependsOn: []int32{1}}},
+	}
+	for name, subtasks := range invalid {
+		if err := ValidateSubtasks(subtasks, sampleTests); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	subtasks := []Subtask{
+		{Number: 1, Points: 20, Tests: []string{"1-2"}},
+		{Number: 2, Points: 30, Tests: []string{"3-4"}},
+		{Number: 3, Points: 50, Tests: []string{"5-6"}, DependsOn: []int32{2}},
+	}
+	verdicts := map[string]string{"01": "AC", "02": "AC", "03": "AC", "04": "TLE", "05": "AC", "06": "AC"}
+
+	total, maxScore, breakdown := Score(subtasks, sampleTests, verdicts)
+	if total != 20 || maxScore != 100 {
+		t.Errorf("score %v/%v, want 20/100", total, maxScore)
+	}
+	// The third subtask passes on its own tests but inherits the failure of the second
+	if breakdown[2].Score != 0 || breakdown[2].Verdict != "TLE" {
+		t.Errorf("unexpected breakdown %+v", breakdown)
+	}
+
+	verdicts["04"] = "AC"
+	delete(verdicts, "06")
+	total, _, _ = Score(subtasks, sampleTests, verdicts)
+	if total != 50 {
+		t.Errorf("tests that did not run should fail their subtask, score %v", total)
+	}
+}
+
+func TestScoreWithoutSubtasks(t *testing.T) {
+	total, maxScore, breakdown := Score(nil, []string{"1", "2"}, map[string]string{"1": "AC", "2": "AC"})
+	if total != MaxScore || maxScore != MaxScore || len(breakdown) != 1 {
+		t.Errorf("unexpected score %v/%v %+v", total, maxScore, breakdown)
+	}
+	total, _, _ = Score(nil, []string{"1", "2"}, map[string]string{"1": "AC", "2": "WA"})
+	if total != 0 {
+		t.Errorf("a failed test should lose the whole problem, score %v", total)
+	}
+}
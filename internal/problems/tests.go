@@ -0,0 +1,156 @@
+package problems
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxTests = 200
+	// MaxTestSetSize bounds the uncompressed size of a test set, the archive itself may claim anything
+	MaxTestSetSize = 512 << 20
+)
+
+var testFileRegex = regexp.MustCompile(`^(\d+)\.(in|out)$`)
+
+// TestCase is one extracted input/expected output pair
+type TestCase struct {
+	Name       string
+	InputPath  string
+	InputSize  int64
+	OutputPath string
+	OutputSize int64
+}
+
+type testPair struct {
+	number int
+	name   string
+	input  *zip.File
+	output *zip.File
+}
+
+// indexArchive pairs the NN.in and NN.out files of an archive, sorted by test number
+func indexArchive(archive *zip.Reader) ([]testPair, error) {
+	pairs := make(map[int]*testPair)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") || strings.HasPrefix(path.Base(file.Name), ".") {
+			continue
+		}
+		match := testFileRegex.FindStringSubmatch(path.Base(file.Name))
+		if match == nil {
+			return nil, fmt.Errorf("unexpected file %q, tests are named NN.in and NN.out", file.Name)
+		}
+		number, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid test number in %q", file.Name)
+		}
+
+		pair, ok := pairs[number]
+		if !ok {
+			pair = &testPair{number: number, name: match[1]}
+			pairs[number] = pair
+		}
+		if len(match[1]) > len(pair.name) {
+			pair.name = match[1]
+		}
+		target := &pair.input
+		if match[2] == "out" {
+			target = &pair.output
+		}
+		if *target != nil {
+			return nil, fmt.Errorf("test %v has more than one .%v file", number, match[2])
+		}
+		*target = file
+	}
+
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("archive contains no tests")
+	}
+	if len(pairs) > MaxTests {
+		return nil, fmt.Errorf("archive contains %v tests, at most %v are allowed", len(pairs), MaxTests)
+	}
+
+	sorted := make([]testPair, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair.input == nil {
+			return nil, fmt.Errorf("test %v is missing its .in file", pair.number)
+		}
+		if pair.output == nil {
+			return nil, fmt.Errorf("test %v is missing its .out file", pair.number)
+		}
+		sorted = append(sorted, *pair)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].number < sorted[j].number })
+	return sorted, nil
+}
+
+func extractFile(file *zip.File, dst string, budget *int64) (int64, error) {
+	src, err := file.Open()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %q: %v", file.Name, err)
+	}
+	defer src.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %q: %v", dst, err)
+	}
+	defer out.Close()
+
+	// Read one byte past the budget to tell a full budget from an overflow
+	written, err := io.Copy(out, io.LimitReader(src, *budget+1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to extract %q: %v", file.Name, err)
+	}
+	if written > *budget {
+		return 0, fmt.Errorf("test set is larger than %v bytes", MaxTestSetSize)
+	}
+	*budget -= written
+	return written, nil
+}
+
+// ExtractTests checks that every test in a zip archive has both its input and expected output, then
+// writes them to dir as <name>.in and <name>.out. Nothing is written unless the archive is complete.
+func ExtractTests(r io.ReaderAt, size int64, dir string) ([]TestCase, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %v", err)
+	}
+	pairs, err := indexArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test directory: %v", err)
+	}
+
+	budget := int64(MaxTestSetSize)
+	tests := make([]TestCase, 0, len(pairs))
+	for _, pair := range pairs {
+		test := TestCase{
+			Name:       pair.name,
+			InputPath:  filepath.Join(dir, pair.name+".in"),
+			OutputPath: filepath.Join(dir, pair.name+".out"),
+		}
+		test.InputSize, err = extractFile(pair.input, test.InputPath, &budget)
+		if err != nil {
+			return nil, err
+		}
+		test.OutputSize, err = extractFile(pair.output, test.OutputPath, &budget)
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
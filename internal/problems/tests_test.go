@@ -0,0 +1,81 @@
+package problems
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func buildZip(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = f.Write([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestExtractTests(t *testing.T) {
+	archive := buildZip(t, map[string]string{
+		"tests/10.in":  "10\n",
+		"tests/10.out": "100\n",
+		"tests/2.in":   "2\n",
+		"tests/2.out":  "4\n",
+		"tests/":       "",
+		"__MACOSX/x":   "junk",
+	})
+	dir := t.TempDir()
+	tests, err := ExtractTests(archive, archive.Size(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) != 2 || tests[0].Name != "2" || tests[1].Name != "10" {
+		t.Fatalf("unexpected tests: %+v", tests)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "10.out"))
+	if err != nil || string(content) != "100\n" || tests[1].OutputSize != 4 {
+		t.Errorf("unexpected output file %q (%v)", content, err)
+	}
+}
+
+func TestExtractTestsRejectsIncompleteSets(t *testing.T) {
+	cases := map[string]map[string]string{
+		"missing output": {"01.in": "1", "01.out": "1", "02.in": "2"},
+		"missing input":  {"01.out": "1"},
+		"stray file":     {"01.in": "1", "01.out": "1", "solution.cpp": ""},
+		"duplicate":      {"a/01.in": "1", "b/01.in": "1", "01.out": "1"},
+		"empty":          {},
+	}
+	for name, files := range cases {
+		dir := filepath.Join(t.TempDir(), "tests")
+		archive := buildZip(t, files)
+		_, err := ExtractTests(archive, archive.Size(), dir)
+		if err == nil {
+			t.Errorf("%v: expected an error", name)
+			continue
+		}
+		if _, statErr := os.Stat(dir); !os.IsNotExist(statErr) {
+			t.Errorf("%v: nothing should be written for an invalid archive", name)
+		}
+	}
+
+	_, err := ExtractTests(strings.NewReader("not a zip"), 9, t.TempDir())
+	if err == nil {
+		t.Error("expected an error for a non-zip upload")
+	}
+}
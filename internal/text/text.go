@@ -0,0 +1,11 @@
+package text
+
+import "strings"
+
+var diacritics = strings.NewReplacer("ă", "a", "â", "a", "î", "i", "ș", "s", "ş", "s", "ț", "t", "ţ", "t")
+
+// StripDiacritics replaces the lowercase Romanian letters with diacritics by their plain ASCII
+// counterparts, both the comma and the older cedilla forms of ș and ț. Callers lowercase first.
+func StripDiacritics(s string) string {
+	return diacritics.Replace(s)
+}
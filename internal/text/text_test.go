@@ -0,0 +1,9 @@
+package text
+
+import "testing"
+
+func TestStripDiacritics(t *testing.T) {
+	if res := StripDiacritics("învățăm structuri şi ţiruri"); res != "invatam structuri si tiruri" {
+		t.Errorf("unexpected result %q", res)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/judge"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+/*
+===========================================
+
+	Judge
+
+===========================================
+*/
+
+// ProblemSubmission pairs a source with the limits and hidden tests of a problem
+func (cfg *ApiCfg) ProblemSubmission(ctx context.Context, problem database.Problem, language string, source []byte) (judge.Submission, error) {
+	tests, err := cfg.db.GetProblemTests(ctx, problem.ID)
+	if err != nil {
+		return judge.Submission{}, fmt.Errorf("failed to retrieve tests: %v", err)
+	}
+	if len(tests) == 0 {
+		return judge.Submission{}, fmt.Errorf("problem %v has no tests", problem.Slug)
+	}
+
+	sub := judge.Submission{
+		Language: language,
+		Source:   source,
+		Limits:   judge.Limits{TimeMs: problem.TimeLimitMs, MemoryMb: problem.MemoryLimitMb},
+		Checker: judge.Checker{
+			Mode:    judge.CheckerMode(problem.CheckerMode),
+			Epsilon: problem.CheckerEpsilon,
+			Source:  []byte(problem.CheckerSource),
+		},
+	}
+	for _, test := range tests {
+		sub.Tests = append(sub.Tests, judge.Test{
+			Name:       test.Name,
+			InputPath:  test.InputPath,
+			OutputPath: test.OutputPath,
+		})
+	}
+	return sub, nil
+}
+
+// JudgeFile runs a local source file against a problem, used from the console to check a test set.
+// The language follows from the file extension
+func (cfg *ApiCfg) JudgeFile(problemKey string, sourcePath string) (judge.Result, error) {
+	ctx := context.Background()
+	lang, ok := cfg.judge.Languages.ByExtension(sourcePath)
+	if !ok {
+		return judge.Result{}, fmt.Errorf("no language is configured for %v", filepath.Base(sourcePath))
+	}
+	problem, err := cfg.GetProblem(ctx, problemKey)
+	if err != nil {
+		return judge.Result{}, fmt.Errorf("failed to retrieve problem: %v", err)
+	}
+	source, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return judge.Result{}, fmt.Errorf("failed to read source: %v", err)
+	}
+	sub, err := cfg.ProblemSubmission(ctx, problem, lang.ID, source)
+	if err != nil {
+		return judge.Result{}, err
+	}
+	return cfg.judge.Evaluate(ctx, sub, judge.Hooks{}), nil
+}
+
+type LanguageResponse struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Extension      string   `json:"extension"`
+	Compile        []string `json:"compile"`
+	Run            []string `json:"run"`
+	TimeMultiplier float64  `json:"time_multiplier"`
+}
+
+// GetLanguagesHandler lists the languages submissions may be written in, in the order the picker shows them
+func (cfg *ApiCfg) GetLanguagesHandler(w http.ResponseWriter, r *http.Request) {
+	languages := []LanguageResponse{}
+	for _, lang := range cfg.judge.Languages.List() {
+		languages = append(languages, LanguageResponse{
+			ID:             lang.ID,
+			Name:           lang.Name,
+			Extension:      lang.Extension,
+			Compile:        append([]string{}, lang.Compile...),
+			Run:            lang.Run,
+			TimeMultiplier: lang.TimeMultiplier,
+		})
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, languages)
+}
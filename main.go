@@ -2,11 +2,19 @@ package main
 
 import (
 	"Codium/internal/database"
+	"Codium/internal/judge"
+	"Codium/internal/markdown"
 	"database/sql"
 	"log"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -16,6 +24,7 @@ type ApiCfg struct {
 	logger               log.Logger
 	dbUrl                string
 	db                   *database.Queries
+	dbConn               *sql.DB
 	dbLoaded             bool
 	secret               string
 	adminDefaultPassword string
@@ -25,6 +34,29 @@ type ApiCfg struct {
 	smtpUser             string
 	smtpPassword         string
 	websiteUrl           string
+	renderer             *markdown.Renderer
+	lessonScanInterval   time.Duration
+	lessonScanMu         sync.Mutex
+	lessonFingerprint    string
+	problemTestsMu       sync.Mutex
+	judge                *judge.Judge
+	judgeWorkers         int
+	judgeWake            chan struct{}
+	submissionEvents     *SubmissionNotifier
+	runLimiter           *RunLimiter
+	runSlots             chan struct{}
+}
+
+// LessonFileGuard keeps lesson sources off the static file server, they are served through /api/lessons
+// which knows whether a lesson is published
+func LessonFileGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(path.Clean("/"+r.URL.Path), "/Lectii/") && strings.HasSuffix(strings.ToLower(r.URL.Path), ".md") {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
 /*
@@ -55,9 +87,11 @@ func main() {
 		}
 
 		cfg = &ApiCfg{
-			logger:   *log.New(logFile, "[API] ", log.LstdFlags),
-			dbLoaded: false,
-			running:  true,
+			logger:           *log.New(logFile, "[API] ", log.LstdFlags),
+			dbLoaded:         false,
+			running:          true,
+			renderer:         markdown.NewRenderer(),
+			submissionEvents: NewSubmissionNotifier(),
 		}
 
 		// Clear the file on startup
@@ -82,6 +116,47 @@ func main() {
 		cfg.smtpUser = os.Getenv("SMTP_USER")
 		cfg.smtpPassword = os.Getenv("SMTP_PASSWORD")
 		cfg.websiteUrl = os.Getenv("WEBSITE_URL")
+		cfg.lessonScanInterval = 10 * time.Second // Default lesson polling interval
+		if seconds, err := strconv.Atoi(os.Getenv("LESSON_SCAN_INTERVAL")); err == nil && seconds > 0 {
+			cfg.lessonScanInterval = time.Duration(seconds) * time.Second
+		}
+		judgeCompiler := os.Getenv("JUDGE_CXX")
+		if judgeCompiler == "" {
+			judgeCompiler = "g++"
+		}
+		// Isolation needs root and Linux namespaces, it can only be turned off explicitly
+		judgeIsolate := os.Getenv("JUDGE_ISOLATE") != "false"
+		if judgeIsolate {
+			if err := judge.CheckIsolation(); err != nil {
+				cfg.logger.Fatal("Error setting up the judge: ", err)
+			}
+		}
+		cfg.judge = judge.New(judgeCompiler, judgeIsolate)
+		cfg.judge.CheckerDir = "data/checkers"
+		// Custom checkers are usually written against testlib.h, which lives here
+		cfg.judge.IncludeDirs = []string{"data/testlib"}
+		if dir := os.Getenv("JUDGE_TESTLIB_DIR"); dir != "" {
+			cfg.judge.IncludeDirs = []string{dir}
+		}
+		// Without a config file the judge offers C++, C and Python
+		if path := os.Getenv("JUDGE_LANGUAGES"); path != "" {
+			languages, err := judge.LoadLanguages(path)
+			if err != nil {
+				cfg.logger.Fatal("Error loading judge languages: ", err)
+			}
+			cfg.judge.Languages = languages
+		}
+		cfg.judgeWorkers = runtime.NumCPU() // Default judge worker count
+		if workers, err := strconv.Atoi(os.Getenv("JUDGE_WORKERS")); err == nil && workers > 0 {
+			cfg.judgeWorkers = workers
+		}
+		cfg.judgeWake = make(chan struct{}, 1)
+		runsPerMinute := 10 // Default scratch runs per user per minute
+		if runs, err := strconv.Atoi(os.Getenv("RUN_RATE_LIMIT")); err == nil && runs > 0 {
+			runsPerMinute = runs
+		}
+		cfg.runLimiter = NewRunLimiter(runsPerMinute, runRateWindow)
+		cfg.runSlots = make(chan struct{}, max(cfg.judgeWorkers/2, 1))
 	}
 
 	if cfg.secret == "" {
@@ -101,6 +176,7 @@ func main() {
 		}
 
 		cfg.db = database.New(db)
+		cfg.dbConn = db
 		cfg.dbLoaded = true
 		cfg.logger.Print("Successfully connected to the database!")
 	} else {
@@ -110,7 +186,7 @@ func main() {
 	// Serve static files from the "App" directory at the "/app/" URL path
 	{
 		mux := http.NewServeMux()
-		mux.Handle("/app/", http.StripPrefix("/app/", http.FileServer(http.Dir("./App/"))))
+		mux.Handle("/app/", http.StripPrefix("/app/", LessonFileGuard(http.FileServer(http.Dir("./App/")))))
 		mux.Handle("POST /api/create_user", http.HandlerFunc(cfg.CreateUserHandler))
 		mux.Handle("POST /admin/reset", http.HandlerFunc(cfg.ResetHandler))
 		mux.Handle("POST /api/login", http.HandlerFunc(cfg.LoginHandler))
@@ -122,6 +198,53 @@ func main() {
 		mux.Handle("PUT /api/users", http.HandlerFunc(cfg.UpdateUserDisambiguationHandler))
 		mux.Handle("GET /api/email/{userID}", http.HandlerFunc(cfg.ValidateEmailHandler))
 		mux.Handle("DELETE /api/users/{userID}", http.HandlerFunc(cfg.DeleteUserHandler))
+		mux.Handle("GET /api/lessons", http.HandlerFunc(cfg.GetLessonsHandler))
+		mux.Handle("GET /api/lessons/search", http.HandlerFunc(cfg.SearchLessonsHandler))
+		mux.Handle("GET /api/lessons/next", http.HandlerFunc(cfg.GetNextLessonsHandler))
+		mux.Handle("GET /api/lessons/{lessonID}", http.HandlerFunc(cfg.GetLessonHandler))
+		mux.Handle("GET /api/lessons/{lessonID}/html", http.HandlerFunc(cfg.GetLessonHtmlHandler))
+		mux.Handle("GET /api/lessons/{lessonID}/revisions", http.HandlerFunc(cfg.GetLessonRevisionsHandler))
+		mux.Handle("GET /api/lessons/{lessonID}/revisions/diff", http.HandlerFunc(cfg.GetLessonRevisionDiffHandler))
+		mux.Handle("POST /api/lessons/{lessonID}/revisions/{revisionID}/rollback", http.HandlerFunc(cfg.RollbackLessonHandler))
+		mux.Handle("POST /api/lessons/{lessonID}/status", http.HandlerFunc(cfg.ChangeLessonStatusHandler))
+		mux.Handle("GET /api/lessons/{lessonID}/reviews", http.HandlerFunc(cfg.GetLessonReviewsHandler))
+		mux.Handle("PUT /api/lessons/{lessonID}/progress", http.HandlerFunc(cfg.UpdateLessonProgressHandler))
+		mux.Handle("GET /api/lessons/{lessonID}/progress", http.HandlerFunc(cfg.GetLessonProgressHandler))
+		mux.Handle("GET /api/users/{userID}/progress", http.HandlerFunc(cfg.GetUserProgressHandler))
+		mux.Handle("GET /api/lessons/{lessonID}/prerequisites", http.HandlerFunc(cfg.GetLessonPrerequisitesHandler))
+		mux.Handle("PUT /api/lessons/{lessonID}/prerequisites", http.HandlerFunc(cfg.UpdateLessonPrerequisitesHandler))
+		mux.Handle("GET /api/problems", http.HandlerFunc(cfg.GetProblemsHandler))
+		mux.Handle("POST /api/problems", http.HandlerFunc(cfg.CreateProblemHandler))
+		mux.Handle("POST /api/problems/polygon", http.HandlerFunc(cfg.ImportPolygonPackageHandler))
+		mux.Handle("GET /api/problems/{problemID}", http.HandlerFunc(cfg.GetProblemHandler))
+		mux.Handle("PUT /api/problems/{problemID}", http.HandlerFunc(cfg.UpdateProblemHandler))
+		mux.Handle("DELETE /api/problems/{problemID}", http.HandlerFunc(cfg.DeleteProblemHandler))
+		mux.Handle("GET /api/problems/{problemID}/tests", http.HandlerFunc(cfg.GetProblemTestsHandler))
+		mux.Handle("PUT /api/problems/{problemID}/tests", http.HandlerFunc(cfg.UploadProblemTestsHandler))
+		mux.Handle("GET /api/problems/{problemID}/subtasks", http.HandlerFunc(cfg.GetProblemSubtasksHandler))
+		mux.Handle("PUT /api/problems/{problemID}/subtasks", http.HandlerFunc(cfg.UpdateProblemSubtasksHandler))
+		mux.Handle("GET /api/languages", http.HandlerFunc(cfg.GetLanguagesHandler))
+		mux.Handle("POST /api/run", http.HandlerFunc(cfg.RunHandler))
+		mux.Handle("POST /api/problems/{problemID}/submissions", http.HandlerFunc(cfg.CreateSubmissionHandler))
+		mux.Handle("GET /api/submissions/{submissionID}", http.HandlerFunc(cfg.GetSubmissionHandler))
+		mux.Handle("GET /api/submissions/{submissionID}/events", http.HandlerFunc(cfg.SubmissionEventsHandler))
+		mux.Handle("GET /api/users/{userID}/submissions", http.HandlerFunc(cfg.GetUserSubmissionsHandler))
+		mux.Handle("POST /api/problems/{problemID}/rejudge", http.HandlerFunc(cfg.RejudgeProblemHandler))
+		mux.Handle("GET /api/problems/{problemID}/plagiarism", http.HandlerFunc(cfg.CheckPlagiarismHandler))
+		mux.Handle("POST /api/submissions/{submissionID}/rejudge", http.HandlerFunc(cfg.RejudgeSubmissionHandler))
+		mux.Handle("GET /api/rejudges/{rejudgeID}", http.HandlerFunc(cfg.GetRejudgeHandler))
+		mux.Handle("GET /api/contests", http.HandlerFunc(cfg.GetContestsHandler))
+		mux.Handle("POST /api/contests", http.HandlerFunc(cfg.CreateContestHandler))
+		mux.Handle("GET /api/contests/{contestID}", http.HandlerFunc(cfg.GetContestHandler))
+		mux.Handle("PUT /api/contests/{contestID}", http.HandlerFunc(cfg.UpdateContestHandler))
+		mux.Handle("DELETE /api/contests/{contestID}", http.HandlerFunc(cfg.DeleteContestHandler))
+		mux.Handle("POST /api/contests/{contestID}/registration", http.HandlerFunc(cfg.RegisterContestHandler))
+		mux.Handle("DELETE /api/contests/{contestID}/registration", http.HandlerFunc(cfg.UnregisterContestHandler))
+		mux.Handle("POST /api/contests/{contestID}/problems/{problemID}/submissions", http.HandlerFunc(cfg.CreateContestSubmissionHandler))
+		mux.Handle("GET /api/contests/{contestID}/scoreboard", http.HandlerFunc(cfg.GetContestScoreboardHandler))
+		mux.Handle("POST /api/contests/{contestID}/unfreeze", http.HandlerFunc(cfg.UnfreezeContestHandler))
+		mux.Handle("POST /api/contests/{contestID}/virtual", http.HandlerFunc(cfg.StartVirtualParticipationHandler))
+		mux.Handle("GET /api/contests/{contestID}/virtual", http.HandlerFunc(cfg.GetVirtualParticipationHandler))
 
 		// Start the HTTP server
 		server := &http.Server{
@@ -130,6 +253,10 @@ func main() {
 		}
 
 		cfg.StartConsole()
+		cfg.StartLessonWatcher(cfg.lessonScanInterval)
+		if cfg.dbLoaded {
+			cfg.StartJudgeWorkers(cfg.judgeWorkers)
+		}
 		err = server.ListenAndServe()
 		if err != nil {
 			cfg.logger.Fatal(err)
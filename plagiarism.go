@@ -0,0 +1,182 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/judge"
+	"Codium/internal/plagiarism"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Plagiarism
+
+===========================================
+*/
+
+const (
+	defaultPlagiarismSimilarity = 0.5
+	defaultPlagiarismPairs      = 50
+	maxPlagiarismPairs          = 200
+)
+
+type PlagiarismSubmissionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Language  string    `json:"language"`
+	Verdict   string    `json:"verdict"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PlagiarismReport struct {
+	ProblemID uuid.UUID     `json:"problem_id"`
+	ContestID uuid.NullUUID `json:"contest_id"`
+	Compared  int           `json:"compared"`
+	// Pairs refer to submissions by ID, the sources of every submission in a pair are in Submissions
+	Pairs       []plagiarism.Pair                       `json:"pairs"`
+	Submissions map[string]PlagiarismSubmissionResponse `json:"submissions"`
+}
+
+// plagiarismLanguage picks the tokenizer of a judge language, configured languages other than Python
+// are assumed to have C-like syntax
+func plagiarismLanguage(language string) plagiarism.Language {
+	if language == judge.LanguagePython {
+		return plagiarism.LanguagePython
+	}
+	return plagiarism.LanguageC
+}
+
+// CheckPlagiarism compares the latest submission of every user to a problem, restricted to a contest
+// when contestID is set, and ranks the suspicious pairs
+func (cfg *ApiCfg) CheckPlagiarism(ctx context.Context, problem database.Problem, contestID uuid.NullUUID, opts plagiarism.Options) (PlagiarismReport, error) {
+	rows, err := cfg.db.GetLatestProblemSubmissions(ctx, database.GetLatestProblemSubmissionsParams{
+		ProblemID: problem.ID,
+		ContestID: contestID,
+	})
+	if err != nil {
+		return PlagiarismReport{}, fmt.Errorf("failed to retrieve submissions: %v", err)
+	}
+
+	docs := make([]plagiarism.Document, len(rows))
+	byID := make(map[string]database.GetLatestProblemSubmissionsRow, len(rows))
+	for i, row := range rows {
+		docs[i] = plagiarism.Document{
+			ID:       row.ID.String(),
+			Author:   row.UserID.String(),
+			Language: plagiarismLanguage(row.Language),
+			Source:   row.Source,
+		}
+		byID[row.ID.String()] = row
+	}
+	pairs := plagiarism.Analyze(docs, opts)
+
+	res := PlagiarismReport{
+		ProblemID:   problem.ID,
+		ContestID:   contestID,
+		Compared:    len(rows),
+		Pairs:       pairs,
+		Submissions: make(map[string]PlagiarismSubmissionResponse),
+	}
+	for _, pair := range pairs {
+		for _, id := range []string{pair.A, pair.B} {
+			row := byID[id]
+			res.Submissions[id] = PlagiarismSubmissionResponse{
+				ID:        row.ID,
+				UserID:    row.UserID,
+				Username:  row.Username,
+				Language:  row.Language,
+				Verdict:   row.Verdict,
+				Source:    row.Source,
+				CreatedAt: row.CreatedAt,
+			}
+		}
+	}
+	cfg.logger.Printf("Plagiarism check of problem %v compared %d submissions, %d suspicious pairs", problem.ID, len(rows), len(pairs))
+	return res, nil
+}
+
+// CheckPlagiarismHandler ranks the pairs of similar submissions to a problem, for teachers and admins.
+// Query parameters: contest, min_similarity between 0 and 1 and limit
+func (cfg *ApiCfg) CheckPlagiarismHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !user.IsAdmin && !user.IsTeacher {
+		cfg.logger.Printf("Unauthorized plagiarism check by user without teacher rights: %v", user.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	problemID, err := uuid.Parse(r.PathValue("problemID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid problem ID: %v", err)
+		http.Error(w, "Invalid problem ID", http.StatusBadRequest)
+		return
+	}
+
+	opts := plagiarism.Options{MinSimilarity: defaultPlagiarismSimilarity, Limit: defaultPlagiarismPairs}
+	query := r.URL.Query()
+	if value := query.Get("min_similarity"); value != "" {
+		opts.MinSimilarity, err = strconv.ParseFloat(value, 64)
+		if err != nil || opts.MinSimilarity < 0 || opts.MinSimilarity > 1 {
+			http.Error(w, "Invalid min_similarity", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		opts.Limit, err = strconv.Atoi(value)
+		if err != nil || opts.Limit < 1 || opts.Limit > maxPlagiarismPairs {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	contestID := uuid.NullUUID{}
+	if value := query.Get("contest"); value != "" {
+		contestID.UUID, err = uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid contest ID", http.StatusBadRequest)
+			return
+		}
+		contestID.Valid = true
+	}
+
+	problem, err := cfg.db.GetProblemByID(r.Context(), problemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", problemID)
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.CheckPlagiarism(r.Context(), problem, contestID, opts)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/judge"
+	"Codium/internal/problems"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Polygon Import
+
+===========================================
+*/
+
+var ErrInvalidPolygonPackage = errors.New("invalid Polygon package")
+
+type PolygonImportResponse struct {
+	Problem     ProblemResponse    `json:"problem"`
+	Tests       int                `json:"tests"`
+	Subtasks    []problems.Subtask `json:"subtasks"`
+	Unsupported []string           `json:"unsupported"`
+}
+
+// validatePolygonTests runs the package's validator on every test input. A validator that does not
+// compile is reported instead of failing the import, the tests are then taken as they are. Any other
+// failure to build it fails the import
+func (cfg *ApiCfg) validatePolygonTests(ctx context.Context, pkg *problems.PolygonPackage) error {
+	if len(pkg.Validator) == 0 {
+		return nil
+	}
+	validator := &judge.Validator{Source: pkg.Validator}
+	output, err := cfg.judge.PrepareValidator(ctx, validator)
+	if err != nil && output == "" {
+		return fmt.Errorf("failed to build validator: %v", err)
+	}
+	if err != nil {
+		pkg.Unsupported = append(pkg.Unsupported, "validator does not compile, tests were not validated")
+		return nil
+	}
+
+	for i, name := range pkg.TestNames() {
+		input, err := pkg.OpenInput(i)
+		if err != nil {
+			return fmt.Errorf("%w: failed to open test %v: %v", ErrInvalidPolygonPackage, name, err)
+		}
+		err = validator.Validate(ctx, input)
+		input.Close()
+		if err != nil {
+			return fmt.Errorf("%w: test %v: %v", ErrInvalidPolygonPackage, name, err)
+		}
+	}
+	return nil
+}
+
+// ImportPolygonPackage creates a problem with its tests, checker and subtasks from a Polygon package.
+// Nothing is kept when a step fails, the package's report of unsupported features is returned with the problem
+func (cfg *ApiCfg) ImportPolygonPackage(ctx context.Context, archive io.ReaderAt, size int64, owner uuid.UUID) (database.Problem, *problems.PolygonPackage, error) {
+	pkg, err := problems.ReadPolygonPackage(archive, size)
+	if err != nil {
+		return database.Problem{}, nil, fmt.Errorf("%w: %v", ErrInvalidPolygonPackage, err)
+	}
+
+	if pkg.Spec.CheckerMode == judge.CheckerCustom {
+		output, err := cfg.judge.PrepareChecker(ctx, &judge.Checker{Mode: pkg.Spec.CheckerMode, Source: []byte(pkg.Spec.CheckerSource)})
+		if err != nil {
+			return database.Problem{}, nil, fmt.Errorf("%w: invalid checker: %v", ErrInvalidPolygonPackage, strings.TrimSpace(fmt.Sprintf("%v\n%v", err, output)))
+		}
+	}
+	err = cfg.validatePolygonTests(ctx, pkg)
+	if err != nil {
+		return database.Problem{}, nil, err
+	}
+
+	// Tests go through the same path as an uploaded archive, written to disk first as it may be large
+	tests, err := os.CreateTemp("", "polygon-tests-*.zip")
+	if err != nil {
+		return database.Problem{}, nil, fmt.Errorf("failed to create test archive: %v", err)
+	}
+	defer os.Remove(tests.Name())
+	defer tests.Close()
+	err = pkg.WriteTests(tests)
+	if err != nil {
+		return database.Problem{}, nil, fmt.Errorf("%w: %v", ErrInvalidPolygonPackage, err)
+	}
+	testsSize, err := tests.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return database.Problem{}, nil, fmt.Errorf("failed to read test archive: %v", err)
+	}
+
+	problem, err := cfg.SaveProblem(ctx, nil, pkg.Spec, uuid.NullUUID{UUID: owner, Valid: true})
+	if err != nil {
+		return database.Problem{}, nil, err
+	}
+	imported := false
+	defer func() {
+		if imported {
+			return
+		}
+		stored, err := cfg.db.GetProblemTests(ctx, problem.ID)
+		if err != nil {
+			cfg.logger.Printf("Failed to retrieve tests of problem %v: %v", problem.ID, err)
+		}
+		err = cfg.db.DeleteProblem(ctx, problem.ID)
+		if err != nil {
+			cfg.logger.Printf("Failed to remove partially imported problem %v: %v", problem.ID, err)
+		}
+		cfg.RemoveProblemData(ctx, problem.ID, stored)
+	}()
+
+	err = cfg.ReplaceProblemTests(ctx, problem.ID, tests, testsSize, owner)
+	if err != nil {
+		if errors.Is(err, ErrInvalidTestArchive) {
+			return database.Problem{}, nil, fmt.Errorf("%w: %v", ErrInvalidPolygonPackage, err)
+		}
+		return database.Problem{}, nil, err
+	}
+	err = cfg.ReplaceProblemSubtasks(ctx, problem.ID, pkg.Subtasks)
+	if err != nil {
+		return database.Problem{}, nil, err
+	}
+
+	imported = true
+	return problem, pkg, nil
+}
+
+func (cfg *ApiCfg) ImportPolygonPackageHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized Polygon import by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 128<<20)
+	err = r.ParseMultipartForm(32 << 20) // Bigger packages spill to a temporary file
+	if err != nil {
+		cfg.logger.Printf("Error parsing multipart form: %v", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	file, handler, err := r.FormFile("file")
+	if err != nil {
+		cfg.logger.Printf("Error retrieving the file: %v", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	defer func(file multipart.File) {
+		err := file.Close()
+		if err != nil {
+			cfg.logger.Printf("Error closing the file: %v", err)
+		}
+	}(file)
+
+	cfg.logger.Printf("Received Polygon import: %v (%v bytes)", handler.Filename, handler.Size)
+
+	problem, pkg, err := cfg.ImportPolygonPackage(r.Context(), file, handler.Size, adminUser.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to import %v: %v", handler.Filename, err)
+		switch {
+		case errors.Is(err, ErrInvalidPolygonPackage):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrProblemSlugTaken):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	detail, err := cfg.problemDetail(r.Context(), problem)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.logger.Printf("Problem %v imported from Polygon by admin %v, %v unsupported features", problem.ID, adminUser.ID, len(pkg.Unsupported))
+	cfg.RespondWithJSON(w, http.StatusCreated, PolygonImportResponse{
+		Problem:     detail,
+		Tests:       len(pkg.TestNames()),
+		Subtasks:    pkg.Subtasks,
+		Unsupported: pkg.Unsupported,
+	})
+}
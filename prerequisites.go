@@ -0,0 +1,353 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/lessons"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+===========================================
+
+	Lesson Prerequisites
+
+===========================================
+*/
+
+var ErrPrerequisiteCycle = errors.New("prerequisites would create a cycle")
+
+func formatCycle(cycle []int32) string {
+	parts := make([]string, len(cycle))
+	for i, lesson := range cycle {
+		parts[i] = strconv.Itoa(int(lesson))
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// LessonPrerequisiteGraph maps every lesson to the lessons it requires
+func (cfg *ApiCfg) LessonPrerequisiteGraph(ctx context.Context) (map[int32][]int32, error) {
+	edges, err := cfg.db.GetAllLessonPrerequisites(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve lesson prerequisites: %v", err)
+	}
+	graph := make(map[int32][]int32)
+	for _, edge := range edges {
+		graph[edge.LessonID] = append(graph[edge.LessonID], edge.PrerequisiteID)
+	}
+	return graph, nil
+}
+
+// SetLessonPrerequisites replaces the prerequisites of a lesson, refusing changes that would create a cycle.
+// Callers hold lessonScanMu so the graph cannot change between the check and the update.
+func (cfg *ApiCfg) SetLessonPrerequisites(ctx context.Context, lessonID int32, prerequisites []int32) error {
+	prerequisites = slices.Clone(prerequisites)
+	slices.Sort(prerequisites)
+	prerequisites = slices.Compact(prerequisites)
+
+	graph, err := cfg.LessonPrerequisiteGraph(ctx)
+	if err != nil {
+		return err
+	}
+	graph[lessonID] = prerequisites
+	if cycle := lessons.FindCycle(graph); cycle != nil {
+		return fmt.Errorf("%w: %v", ErrPrerequisiteCycle, formatCycle(cycle))
+	}
+
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.DeleteLessonPrerequisites(ctx, lessonID)
+	if err != nil {
+		return fmt.Errorf("failed to clear lesson prerequisites: %v", err)
+	}
+	for _, prerequisite := range prerequisites {
+		err = qtx.AddLessonPrerequisite(ctx, database.AddLessonPrerequisiteParams{
+			LessonID:       lessonID,
+			PrerequisiteID: prerequisite,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add prerequisite %v: %v", prerequisite, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// syncFrontMatterPrerequisites applies the prerequisites declared in lesson front matter, lessons without
+// a prerequisites key keep the ones set through the API. Callers hold lessonScanMu.
+func (cfg *ApiCfg) syncFrontMatterPrerequisites(ctx context.Context, entries []lessons.Entry, found map[int32]bool) {
+	for _, entry := range entries {
+		if entry.Meta.Prerequisites == nil {
+			continue
+		}
+
+		var wanted []int32
+		for _, prerequisite := range entry.Meta.Prerequisites {
+			if !found[prerequisite] {
+				cfg.logger.Printf("Lesson %v requires unknown lesson %v, ignoring it", entry.Meta.ID, prerequisite)
+				continue
+			}
+			wanted = append(wanted, prerequisite)
+		}
+		slices.Sort(wanted)
+		wanted = slices.Compact(wanted)
+
+		current, err := cfg.db.GetLessonPrerequisites(ctx, entry.Meta.ID)
+		if err != nil {
+			cfg.logger.Printf("Failed to retrieve prerequisites of lesson %v: %v", entry.Meta.ID, err)
+			continue
+		}
+		if slices.Equal(current, wanted) {
+			continue
+		}
+
+		err = cfg.SetLessonPrerequisites(ctx, entry.Meta.ID, wanted)
+		if err != nil {
+			cfg.logger.Printf("Failed to update prerequisites of lesson %v: %v", entry.Meta.ID, err)
+			continue
+		}
+		cfg.logger.Printf("Lesson %v prerequisites set to %v", entry.Meta.ID, wanted)
+	}
+}
+
+func (cfg *ApiCfg) GetLessonPrerequisitesHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !cfg.CanViewLesson(r, lesson) {
+		cfg.logger.Printf("Lesson %v is not visible to the requester", lessonID)
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		return
+	}
+
+	prerequisites, err := cfg.db.GetLessonPrerequisites(r.Context(), lesson.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve lesson prerequisites: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if prerequisites == nil {
+		prerequisites = []int32{}
+	}
+
+	type response struct {
+		LessonID      int32   `json:"lesson_id"`
+		Prerequisites []int32 `json:"prerequisites"`
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, response{LessonID: lesson.ID, Prerequisites: prerequisites})
+}
+
+func (cfg *ApiCfg) UpdateLessonPrerequisitesHandler(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		Prerequisites []int32 `json:"prerequisites"`
+	}
+
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized prerequisites update by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var p params
+	err = decoder.Decode(&p)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	// Every lesson involved has to exist
+	for _, id := range append([]int32{int32(lessonID)}, p.Prerequisites...) {
+		_, err = cfg.db.GetLessonByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				cfg.logger.Printf("Lesson not found: %v", id)
+				http.Error(w, fmt.Sprintf("Lesson %v not found", id), http.StatusNotFound)
+				return
+			}
+			cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	cfg.logger.Printf("Received prerequisites update for lesson %v: %v", lessonID, p.Prerequisites)
+
+	cfg.lessonScanMu.Lock()
+	err = cfg.SetLessonPrerequisites(r.Context(), int32(lessonID), p.Prerequisites)
+	cfg.lessonScanMu.Unlock()
+	if err != nil {
+		if errors.Is(err, ErrPrerequisiteCycle) {
+			cfg.logger.Printf("Rejected prerequisites for lesson %v: %v", lessonID, err)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		cfg.logger.Printf("Failed to update lesson prerequisites: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	prerequisites, err := cfg.db.GetLessonPrerequisites(r.Context(), int32(lessonID))
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve lesson prerequisites: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if prerequisites == nil {
+		prerequisites = []int32{}
+	}
+
+	type response struct {
+		LessonID      int32   `json:"lesson_id"`
+		Prerequisites []int32 `json:"prerequisites"`
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, response{LessonID: int32(lessonID), Prerequisites: prerequisites})
+}
+
+// GetNextLessonsHandler recommends the published lessons the user can start, lessons already in progress come first
+func (cfg *ApiCfg) GetNextLessonsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := 5
+	if r.URL.Query().Get("limit") != "" {
+		limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 || limit > 50 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cfg.logger.Printf("Received next lessons request for user ID: %v", user.ID)
+
+	published, err := cfg.db.GetLessonsByStatus(r.Context(), string(lessons.StatusPublished))
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve lessons: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	graph, err := cfg.LessonPrerequisiteGraph(r.Context())
+	if err != nil {
+		cfg.logger.Printf("%v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	completedIDs, err := cfg.db.GetCompletedLessonIDs(r.Context(), user.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve completed lessons: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	startedIDs, err := cfg.db.GetStartedLessonIDs(r.Context(), user.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve started lessons: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	completed := make(map[int32]bool)
+	for _, id := range completedIDs {
+		completed[id] = true
+	}
+	started := make(map[int32]bool)
+	for _, id := range startedIDs {
+		started[id] = true
+	}
+
+	byID := make(map[int32]database.Lesson)
+	available := make([]int32, 0, len(published))
+	for _, lesson := range published {
+		byID[lesson.ID] = lesson
+		available = append(available, lesson.ID)
+	}
+
+	unlocked := lessons.Unlocked(available, graph, completed)
+	slices.SortStableFunc(unlocked, func(a, b int32) int {
+		if started[a] != started[b] {
+			if started[a] {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+	if len(unlocked) > limit {
+		unlocked = unlocked[:limit]
+	}
+
+	type nextLesson struct {
+		LessonResponse
+		Started bool `json:"started"`
+	}
+	res := make([]nextLesson, 0, len(unlocked))
+	for _, id := range unlocked {
+		res = append(res, nextLesson{
+			LessonResponse: LessonToResponse(byID[id], false),
+			Started:        started[id],
+		})
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
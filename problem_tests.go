@@ -0,0 +1,293 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/problems"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Problem Tests
+
+===========================================
+*/
+
+var ErrInvalidTestArchive = errors.New("invalid test archive")
+
+// Hidden tests live outside App/ so the static file server never sees them
+const problemDataRoot = "data/problems"
+
+func problemTestsDir(problemID uuid.UUID) string {
+	return filepath.Join(problemDataRoot, problemID.String(), "tests")
+}
+
+// ReplaceProblemTests extracts a zipped test set and swaps it in for the current tests of a problem
+func (cfg *ApiCfg) ReplaceProblemTests(ctx context.Context, problemID uuid.UUID, archive io.ReaderAt, size int64, uploader uuid.UUID) error {
+	cfg.problemTestsMu.Lock()
+	defer cfg.problemTestsMu.Unlock()
+
+	// Every upload gets its own directory, the previous set stays usable until the new one is committed
+	setDir := filepath.Join(problemTestsDir(problemID), uuid.New().String())
+	tests, err := problems.ExtractTests(archive, size, setDir)
+	if err != nil {
+		os.RemoveAll(setDir)
+		return fmt.Errorf("%w: %v", ErrInvalidTestArchive, err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			os.RemoveAll(setDir)
+		}
+	}()
+
+	previous, err := cfg.db.GetProblemTests(ctx, problemID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve current tests: %v", err)
+	}
+
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.DeleteProblemTests(ctx, problemID)
+	if err != nil {
+		return fmt.Errorf("failed to clear tests: %v", err)
+	}
+	for _, test := range previous {
+		for _, fileID := range []uuid.UUID{test.InputFileID, test.OutputFileID} {
+			err = qtx.DeleteFile(ctx, fileID)
+			if err != nil {
+				return fmt.Errorf("failed to remove test file %v: %v", fileID, err)
+			}
+		}
+	}
+
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+	for i, test := range tests {
+		inputFile, err := qtx.CreateFile(ctx, database.CreateFileParams{
+			ID:         uuid.New(),
+			UserID:     uuid.NullUUID{UUID: uploader, Valid: true},
+			Filename:   filepath.Base(test.InputPath),
+			Filepath:   test.InputPath,
+			Filesize:   test.InputSize,
+			UploadedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record input of test %v: %v", test.Name, err)
+		}
+		outputFile, err := qtx.CreateFile(ctx, database.CreateFileParams{
+			ID:         uuid.New(),
+			UserID:     uuid.NullUUID{UUID: uploader, Valid: true},
+			Filename:   filepath.Base(test.OutputPath),
+			Filepath:   test.OutputPath,
+			Filesize:   test.OutputSize,
+			UploadedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record output of test %v: %v", test.Name, err)
+		}
+		err = qtx.AddProblemTest(ctx, database.AddProblemTestParams{
+			ProblemID:    problemID,
+			Position:     int32(i + 1),
+			Name:         test.Name,
+			InputFileID:  inputFile.ID,
+			OutputFileID: outputFile.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record test %v: %v", test.Name, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit tests: %v", err)
+	}
+	committed = true
+
+	// Older sets are no longer referenced
+	dirs, err := os.ReadDir(problemTestsDir(problemID))
+	if err != nil {
+		cfg.logger.Printf("Failed to list test sets of problem %v: %v", problemID, err)
+		return nil
+	}
+	for _, dir := range dirs {
+		if dir.Name() == filepath.Base(setDir) {
+			continue
+		}
+		err = os.RemoveAll(filepath.Join(problemTestsDir(problemID), dir.Name()))
+		if err != nil {
+			cfg.logger.Printf("Failed to remove old test set %v: %v", dir.Name(), err)
+		}
+	}
+	return nil
+}
+
+// RemoveProblemData drops the stored tests of a problem, used once the problem itself is deleted
+func (cfg *ApiCfg) RemoveProblemData(ctx context.Context, problemID uuid.UUID, tests []database.GetProblemTestsRow) {
+	cfg.problemTestsMu.Lock()
+	defer cfg.problemTestsMu.Unlock()
+
+	for _, test := range tests {
+		for _, fileID := range []uuid.UUID{test.InputFileID, test.OutputFileID} {
+			err := cfg.db.DeleteFile(ctx, fileID)
+			if err != nil {
+				cfg.logger.Printf("Failed to remove test file %v: %v", fileID, err)
+			}
+		}
+	}
+	err := os.RemoveAll(filepath.Join(problemDataRoot, problemID.String()))
+	if err != nil {
+		cfg.logger.Printf("Failed to remove data of problem %v: %v", problemID, err)
+	}
+}
+
+type ProblemTestResponse struct {
+	Position   int32  `json:"position"`
+	Name       string `json:"name"`
+	InputSize  int64  `json:"input_size"`
+	OutputSize int64  `json:"output_size"`
+}
+
+func (cfg *ApiCfg) problemTestsResponse(ctx context.Context, problemID uuid.UUID) ([]ProblemTestResponse, error) {
+	tests, err := cfg.db.GetProblemTests(ctx, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tests: %v", err)
+	}
+	res := make([]ProblemTestResponse, 0, len(tests))
+	for _, test := range tests {
+		res = append(res, ProblemTestResponse{
+			Position:   test.Position,
+			Name:       test.Name,
+			InputSize:  test.InputSize,
+			OutputSize: test.OutputSize,
+		})
+	}
+	return res, nil
+}
+
+// adminProblemFromRequest authenticates an admin and loads the problem named in the path, writing the error response on failure
+func (cfg *ApiCfg) adminProblemFromRequest(w http.ResponseWriter, r *http.Request, action string) (database.User, database.Problem, bool) {
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return database.User{}, database.Problem{}, false
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized %v by non-admin user: %v", action, adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return database.User{}, database.Problem{}, false
+	}
+
+	problemID, err := uuid.Parse(r.PathValue("problemID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid problem ID: %v", err)
+		http.Error(w, "Invalid problem ID", http.StatusBadRequest)
+		return database.User{}, database.Problem{}, false
+	}
+	problem, err := cfg.db.GetProblemByID(r.Context(), problemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", problemID)
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return database.User{}, database.Problem{}, false
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return database.User{}, database.Problem{}, false
+	}
+	return adminUser, problem, true
+}
+
+func (cfg *ApiCfg) UploadProblemTestsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, problem, ok := cfg.adminProblemFromRequest(w, r, "test upload")
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 128<<20)
+	err := r.ParseMultipartForm(32 << 20) // Bigger archives spill to a temporary file
+	if err != nil {
+		cfg.logger.Printf("Error parsing multipart form: %v", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	file, handler, err := r.FormFile("file")
+	if err != nil {
+		cfg.logger.Printf("Error retrieving the file: %v", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	defer func(file multipart.File) {
+		err := file.Close()
+		if err != nil {
+			cfg.logger.Printf("Error closing the file: %v", err)
+		}
+	}(file)
+
+	cfg.logger.Printf("Received test upload for problem %v: %v (%v bytes)", problem.ID, handler.Filename, handler.Size)
+
+	err = cfg.ReplaceProblemTests(r.Context(), problem.ID, file, handler.Size, adminUser.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to store tests of problem %v: %v", problem.ID, err)
+		if errors.Is(err, ErrInvalidTestArchive) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.problemTestsResponse(r.Context(), problem.ID)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.logger.Printf("Problem %v now has %v tests", problem.ID, len(res))
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) GetProblemTestsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	_, problem, ok := cfg.adminProblemFromRequest(w, r, "test listing")
+	if !ok {
+		return
+	}
+
+	res, err := cfg.problemTestsResponse(r.Context(), problem.ID)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/judge"
+	"Codium/internal/problems"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Problem Bank
+
+===========================================
+*/
+
+var ErrProblemSlugTaken = errors.New("slug is already used by another problem")
+
+type ProblemResponse struct {
+	ID             uuid.UUID          `json:"id"`
+	Slug           string             `json:"slug"`
+	Title          string             `json:"title"`
+	Difficulty     string             `json:"difficulty"`
+	Tags           []string           `json:"tags"`
+	TimeLimitMs    int32              `json:"time_limit_ms"`
+	MemoryLimitMb  int32              `json:"memory_limit_mb"`
+	AuthorID       uuid.NullUUID      `json:"author_id"`
+	CheckerMode    string             `json:"checker_mode"`
+	CheckerEpsilon float64            `json:"checker_epsilon"`
+	Statement      string             `json:"statement,omitempty"`
+	StatementHtml  string             `json:"statement_html,omitempty"`
+	InputFormat    string             `json:"input_format,omitempty"`
+	OutputFormat   string             `json:"output_format,omitempty"`
+	Samples        []problems.Sample  `json:"samples,omitempty"`
+	Subtasks       []problems.Subtask `json:"subtasks,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+func ProblemToResponse(problem database.Problem) ProblemResponse {
+	return ProblemResponse{
+		ID:             problem.ID,
+		Slug:           problem.Slug,
+		Title:          problem.Title,
+		Difficulty:     problem.Difficulty,
+		Tags:           problem.Tags,
+		TimeLimitMs:    problem.TimeLimitMs,
+		MemoryLimitMb:  problem.MemoryLimitMb,
+		AuthorID:       problem.AuthorID,
+		CheckerMode:    problem.CheckerMode,
+		CheckerEpsilon: problem.CheckerEpsilon,
+		CreatedAt:      problem.CreatedAt,
+		UpdatedAt:      problem.UpdatedAt,
+	}
+}
+
+// GetProblem looks a problem up by its ID or, failing that, by its slug
+func (cfg *ApiCfg) GetProblem(ctx context.Context, key string) (database.Problem, error) {
+	id, err := uuid.Parse(key)
+	if err == nil {
+		return cfg.db.GetProblemByID(ctx, id)
+	}
+	return cfg.db.GetProblemBySlug(ctx, key)
+}
+
+// checkProblemVisible hides the problems of contests that have not started yet from students,
+// reporting them as sql.ErrNoRows
+func (cfg *ApiCfg) checkProblemVisible(ctx context.Context, problem database.Problem) error {
+	hidden, err := cfg.db.IsProblemInUpcomingContest(ctx, database.IsProblemInUpcomingContestParams{
+		ProblemID: problem.ID,
+		StartsAt:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check contests of problem: %v", err)
+	}
+	if hidden {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SaveProblem creates a problem when existing is nil or replaces it otherwise, samples included
+func (cfg *ApiCfg) SaveProblem(ctx context.Context, existing *database.Problem, spec problems.Spec, authorID uuid.NullUUID) (database.Problem, error) {
+	other, err := cfg.db.GetProblemBySlug(ctx, spec.Slug)
+	if err == nil && (existing == nil || other.ID != existing.ID) {
+		return database.Problem{}, ErrProblemSlugTaken
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return database.Problem{}, fmt.Errorf("failed to check slug: %v", err)
+	}
+
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Problem{}, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	var problem database.Problem
+	if existing == nil {
+		problem, err = qtx.CreateProblem(ctx, database.CreateProblemParams{
+			ID:             uuid.New(),
+			Slug:           spec.Slug,
+			Title:          spec.Title,
+			Statement:      spec.Statement,
+			InputFormat:    spec.InputFormat,
+			OutputFormat:   spec.OutputFormat,
+			TimeLimitMs:    spec.TimeLimitMs,
+			MemoryLimitMb:  spec.MemoryLimitMb,
+			Difficulty:     string(spec.Difficulty),
+			Tags:           spec.Tags,
+			AuthorID:       authorID,
+			CheckerMode:    string(spec.CheckerMode),
+			CheckerEpsilon: spec.CheckerEpsilon,
+			CheckerSource:  spec.CheckerSource,
+			CreatedAt:      time.Now(),
+		})
+	} else {
+		problem, err = qtx.UpdateProblem(ctx, database.UpdateProblemParams{
+			ID:             existing.ID,
+			Slug:           spec.Slug,
+			Title:          spec.Title,
+			Statement:      spec.Statement,
+			InputFormat:    spec.InputFormat,
+			OutputFormat:   spec.OutputFormat,
+			TimeLimitMs:    spec.TimeLimitMs,
+			MemoryLimitMb:  spec.MemoryLimitMb,
+			Difficulty:     string(spec.Difficulty),
+			Tags:           spec.Tags,
+			CheckerMode:    string(spec.CheckerMode),
+			CheckerEpsilon: spec.CheckerEpsilon,
+			CheckerSource:  spec.CheckerSource,
+			UpdatedAt:      time.Now(),
+		})
+	}
+	if err != nil {
+		return database.Problem{}, fmt.Errorf("failed to store problem: %v", err)
+	}
+
+	err = qtx.DeleteProblemSamples(ctx, problem.ID)
+	if err != nil {
+		return database.Problem{}, fmt.Errorf("failed to clear samples: %v", err)
+	}
+	for i, sample := range spec.Samples {
+		err = qtx.AddProblemSample(ctx, database.AddProblemSampleParams{
+			ProblemID:   problem.ID,
+			Position:    int32(i + 1),
+			Input:       sample.Input,
+			Output:      sample.Output,
+			Explanation: sample.Explanation,
+		})
+		if err != nil {
+			return database.Problem{}, fmt.Errorf("failed to store sample %v: %v", i+1, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return database.Problem{}, fmt.Errorf("failed to commit problem: %v", err)
+	}
+	return problem, nil
+}
+
+// checkProblemChecker compiles a custom checker before it is saved, writing the compiler output back on failure
+func (cfg *ApiCfg) checkProblemChecker(w http.ResponseWriter, r *http.Request, spec problems.Spec) bool {
+	if spec.CheckerMode != judge.CheckerCustom {
+		return true
+	}
+	output, err := cfg.judge.PrepareChecker(r.Context(), &judge.Checker{Mode: spec.CheckerMode, Source: []byte(spec.CheckerSource)})
+	if err != nil {
+		cfg.logger.Printf("Rejected checker of problem %v: %v", spec.Slug, err)
+		http.Error(w, strings.TrimSpace(fmt.Sprintf("Invalid checker: %v\n%v", err, output)), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// problemDetail builds the full response of a problem, statement rendered and samples included
+func (cfg *ApiCfg) problemDetail(ctx context.Context, problem database.Problem) (ProblemResponse, error) {
+	res := ProblemToResponse(problem)
+	res.Statement = problem.Statement
+	res.InputFormat = problem.InputFormat
+	res.OutputFormat = problem.OutputFormat
+
+	rendered, err := cfg.renderer.Render([]byte(problem.Statement))
+	if err != nil {
+		return ProblemResponse{}, fmt.Errorf("failed to render statement: %v", err)
+	}
+	res.StatementHtml = rendered.HTML
+
+	samples, err := cfg.db.GetProblemSamples(ctx, problem.ID)
+	if err != nil {
+		return ProblemResponse{}, fmt.Errorf("failed to retrieve samples: %v", err)
+	}
+	res.Samples = make([]problems.Sample, 0, len(samples))
+	for _, sample := range samples {
+		res.Samples = append(res.Samples, problems.Sample{
+			Input:       sample.Input,
+			Output:      sample.Output,
+			Explanation: sample.Explanation,
+		})
+	}
+	res.Subtasks, err = cfg.ProblemSubtasks(ctx, problem.ID)
+	if err != nil {
+		return ProblemResponse{}, err
+	}
+	return res, nil
+}
+
+func (cfg *ApiCfg) GetProblemsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	limit, offset := 50, 0
+	var err error
+	if q.Get("limit") != "" {
+		limit, err = strconv.Atoi(q.Get("limit"))
+		if err != nil || limit <= 0 || limit > 200 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	if q.Get("offset") != "" {
+		offset, err = strconv.Atoi(q.Get("offset"))
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+
+	params := database.GetProblemsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}
+	if q.Get("difficulty") != "" {
+		if !problems.ValidDifficulty(problems.Difficulty(q.Get("difficulty"))) {
+			http.Error(w, "Invalid difficulty", http.StatusBadRequest)
+			return
+		}
+		params.Difficulty = sql.NullString{String: q.Get("difficulty"), Valid: true}
+	}
+	if q.Get("tag") != "" {
+		params.Tag = sql.NullString{String: q.Get("tag"), Valid: true}
+	}
+	if user, err := cfg.AuthenticateUser(r); err != nil || !user.IsAdmin {
+		params.VisibleAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	cfg.logger.Printf("Received problems request, difficulty=%q tag=%q", q.Get("difficulty"), q.Get("tag"))
+
+	list, err := cfg.db.GetProblems(r.Context(), params)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve problems: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]ProblemResponse, 0, len(list))
+	for _, problem := range list {
+		res = append(res, ProblemToResponse(problem))
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) GetProblemHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	key := r.PathValue("problemID")
+	cfg.logger.Printf("Received problem request for: %v", key)
+
+	problem, err := cfg.GetProblem(r.Context(), key)
+	if err == nil {
+		if user, authErr := cfg.AuthenticateUser(r); authErr != nil || !user.IsAdmin {
+			err = cfg.checkProblemVisible(r.Context(), problem)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", key)
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.problemDetail(r.Context(), problem)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) CreateProblemHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized problem creation by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var spec problems.Spec
+	err = decoder.Decode(&spec)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	err = spec.Normalize()
+	if err != nil {
+		cfg.logger.Printf("Invalid problem: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg.logger.Printf("Received problem creation request: %v", spec.Slug)
+
+	if !cfg.checkProblemChecker(w, r, spec) {
+		return
+	}
+
+	problem, err := cfg.SaveProblem(r.Context(), nil, spec, uuid.NullUUID{UUID: adminUser.ID, Valid: true})
+	if err != nil {
+		if errors.Is(err, ErrProblemSlugTaken) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		cfg.logger.Printf("Failed to create problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.problemDetail(r.Context(), problem)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusCreated, res)
+}
+
+func (cfg *ApiCfg) UpdateProblemHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized problem update by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	problemID, err := uuid.Parse(r.PathValue("problemID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid problem ID: %v", err)
+		http.Error(w, "Invalid problem ID", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := cfg.db.GetProblemByID(r.Context(), problemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", problemID)
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var spec problems.Spec
+	err = decoder.Decode(&spec)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	err = spec.Normalize()
+	if err != nil {
+		cfg.logger.Printf("Invalid problem: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg.logger.Printf("Received problem update request for: %v", problemID)
+
+	if !cfg.checkProblemChecker(w, r, spec) {
+		return
+	}
+
+	problem, err := cfg.SaveProblem(r.Context(), &existing, spec, existing.AuthorID)
+	if err != nil {
+		if errors.Is(err, ErrProblemSlugTaken) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		cfg.logger.Printf("Failed to update problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.problemDetail(r.Context(), problem)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) DeleteProblemHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized problem deletion by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	problemID, err := uuid.Parse(r.PathValue("problemID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid problem ID: %v", err)
+		http.Error(w, "Invalid problem ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = cfg.db.GetProblemByID(r.Context(), problemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", problemID)
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	tests, err := cfg.db.GetProblemTests(r.Context(), problemID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve problem tests: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err = cfg.db.DeleteProblem(r.Context(), problemID)
+	if err != nil {
+		cfg.logger.Printf("Failed to delete problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RemoveProblemData(r.Context(), problemID, tests)
+
+	cfg.logger.Printf("Problem %v deleted by admin %v", problemID, adminUser.ID)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Problem deleted successfully."))
+	if err != nil {
+		cfg.logger.Printf("Failed to write response: %v", err)
+	}
+}
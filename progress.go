@@ -0,0 +1,254 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Lesson Progress
+
+===========================================
+*/
+
+type LessonProgressResponse struct {
+	LessonID    int32      `json:"lesson_id"`
+	Title       string     `json:"title,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	LastSection string     `json:"last_section"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type GradeProgressResponse struct {
+	Grade     string                   `json:"grade"`
+	Total     int64                    `json:"total"`
+	Started   int64                    `json:"started"`
+	Completed int64                    `json:"completed"`
+	Lessons   []LessonProgressResponse `json:"lessons"`
+}
+
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// UpdateLessonProgressHandler marks a lesson as started, remembers the last section read and optionally completes it
+func (cfg *ApiCfg) UpdateLessonProgressHandler(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		Section   string `json:"section"`
+		Completed bool   `json:"completed"`
+	}
+
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var p params
+	err = decoder.Decode(&p)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	p.Section = strings.TrimPrefix(strings.TrimSpace(p.Section), "#")
+	if len(p.Section) > 200 {
+		http.Error(w, "Section anchor is too long", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !cfg.CanViewLesson(r, lesson) {
+		cfg.logger.Printf("Lesson %v is not visible to the requester", lessonID)
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		return
+	}
+
+	now := time.Now()
+	completedAt := sql.NullTime{}
+	if p.Completed {
+		completedAt = sql.NullTime{Time: now, Valid: true}
+	}
+
+	progress, err := cfg.db.UpsertLessonProgress(r.Context(), database.UpsertLessonProgressParams{
+		UserID:      user.ID,
+		LessonID:    lesson.ID,
+		StartedAt:   now,
+		CompletedAt: completedAt,
+		LastSection: p.Section,
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to update lesson progress: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.RespondWithJSON(w, http.StatusOK, LessonProgressResponse{
+		LessonID:    progress.LessonID,
+		Title:       lesson.Title,
+		StartedAt:   progress.StartedAt,
+		CompletedAt: nullTimeToPtr(progress.CompletedAt),
+		LastSection: progress.LastSection,
+		UpdatedAt:   progress.UpdatedAt,
+	})
+}
+
+func (cfg *ApiCfg) GetLessonProgressHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	progress, err := cfg.db.GetLessonProgress(r.Context(), database.GetLessonProgressParams{
+		UserID:   user.ID,
+		LessonID: int32(lessonID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Lesson not started", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson progress: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.RespondWithJSON(w, http.StatusOK, LessonProgressResponse{
+		LessonID:    progress.LessonID,
+		StartedAt:   progress.StartedAt,
+		CompletedAt: nullTimeToPtr(progress.CompletedAt),
+		LastSection: progress.LastSection,
+		UpdatedAt:   progress.UpdatedAt,
+	})
+}
+
+// GetUserProgressHandler summarizes a user's progress per grade, visible to the user, teachers and admins
+func (cfg *ApiCfg) GetUserProgressHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	requestingUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid UUID format: %v", err)
+		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if requestingUser.ID != userID && !requestingUser.IsAdmin && !requestingUser.IsTeacher {
+		cfg.logger.Printf("Unauthorized progress request by user: %v", requestingUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	cfg.logger.Printf("Received progress summary request for user ID: %v", userID)
+
+	summary, err := cfg.db.GetUserProgressSummary(r.Context(), userID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve progress summary: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	progress, err := cfg.db.GetUserProgress(r.Context(), userID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve lesson progress: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	grades := make([]GradeProgressResponse, 0, len(summary))
+	index := make(map[string]int)
+	for _, row := range summary {
+		index[row.Grade] = len(grades)
+		grades = append(grades, GradeProgressResponse{
+			Grade:     row.Grade,
+			Total:     row.Total,
+			Started:   row.Started,
+			Completed: row.Completed,
+			Lessons:   []LessonProgressResponse{},
+		})
+	}
+	for _, row := range progress {
+		i, ok := index[row.Grade]
+		if !ok {
+			continue
+		}
+		grades[i].Lessons = append(grades[i].Lessons, LessonProgressResponse{
+			LessonID:    row.LessonID,
+			Title:       row.Title,
+			StartedAt:   row.StartedAt,
+			CompletedAt: nullTimeToPtr(row.CompletedAt),
+			LastSection: row.LastSection,
+			UpdatedAt:   row.UpdatedAt,
+		})
+	}
+
+	type response struct {
+		UserID uuid.UUID               `json:"user_id"`
+		Grades []GradeProgressResponse `json:"grades"`
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, response{UserID: userID, Grades: grades})
+}
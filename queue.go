@@ -0,0 +1,188 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/judge"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Judge Queue
+
+===========================================
+*/
+
+const (
+	judgePollInterval = time.Second
+	// Running jobs are touched this often, a job nobody touched for judgeStaleAfter belonged to a dead worker
+	judgeHeartbeatInterval = 30 * time.Second
+	judgeStaleAfter        = 2 * time.Minute
+	judgeMaxAttempts       = 3
+)
+
+// EnqueueSubmission schedules a submission for judging, q may be bound to the caller's transaction
+func (cfg *ApiCfg) EnqueueSubmission(ctx context.Context, q *database.Queries, submissionID uuid.UUID) error {
+	err := q.MarkSubmissionPending(ctx, submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to mark submission as pending: %v", err)
+	}
+	_, err = q.EnqueueJudgeJob(ctx, database.EnqueueJudgeJobParams{
+		ID:           uuid.New(),
+		SubmissionID: submissionID,
+		RunAfter:     time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue submission: %v", err)
+	}
+	return nil
+}
+
+// WakeJudgeWorkers lets an idle worker pick up new jobs without waiting for its next poll
+func (cfg *ApiCfg) WakeJudgeWorkers() {
+	select {
+	case cfg.judgeWake <- struct{}{}:
+	default:
+	}
+}
+
+// StartJudgeWorkers recovers jobs abandoned by a previous run and starts count workers
+func (cfg *ApiCfg) StartJudgeWorkers(count int) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "codium"
+	}
+	instance := fmt.Sprintf("%s-%d", hostname, os.Getpid())
+
+	cfg.logger.Printf("Starting %d judge workers as %v", count, instance)
+	cfg.requeueStaleJudgeJobs()
+	go func() {
+		for cfg.running {
+			time.Sleep(judgeStaleAfter / 2)
+			cfg.requeueStaleJudgeJobs()
+		}
+	}()
+
+	for i := 1; i <= count; i++ {
+		go cfg.judgeWorker(fmt.Sprintf("%s/%d", instance, i))
+	}
+}
+
+// requeueStaleJudgeJobs puts jobs whose worker stopped sending heartbeats back in the queue
+func (cfg *ApiCfg) requeueStaleJudgeJobs() {
+	ctx := context.Background()
+	now := time.Now()
+	submissions, err := cfg.db.RequeueStaleJudgeJobs(ctx, database.RequeueStaleJudgeJobsParams{
+		RunAfter: now,
+		LockedAt: sql.NullTime{Time: now.Add(-judgeStaleAfter), Valid: true},
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to requeue stale judge jobs: %v", err)
+		return
+	}
+	for _, submissionID := range submissions {
+		cfg.logger.Printf("Requeued submission %v, its worker stopped responding", submissionID)
+		err = cfg.db.MarkSubmissionPending(ctx, submissionID)
+		if err != nil {
+			cfg.logger.Printf("Failed to mark submission %v as pending: %v", submissionID, err)
+		}
+		cfg.submissionEvents.Notify(submissionID)
+	}
+	if len(submissions) > 0 {
+		cfg.WakeJudgeWorkers()
+	}
+}
+
+func (cfg *ApiCfg) judgeWorker(name string) {
+	for cfg.running {
+		job, err := cfg.db.ClaimJudgeJob(context.Background(), database.ClaimJudgeJobParams{
+			LockedBy: sql.NullString{String: name, Valid: true},
+			LockedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				cfg.logger.Printf("Judge worker %v failed to claim a job: %v", name, err)
+			}
+			select {
+			case <-cfg.judgeWake:
+			case <-time.After(judgePollInterval):
+			}
+			continue
+		}
+		cfg.processJudgeJob(name, job)
+	}
+}
+
+func (cfg *ApiCfg) processJudgeJob(worker string, job database.JudgeJob) {
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(judgeHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := cfg.db.HeartbeatJudgeJob(ctx, database.HeartbeatJudgeJobParams{
+					ID:       job.ID,
+					LockedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				})
+				if err != nil {
+					cfg.logger.Printf("Failed to send heartbeat for judge job %v: %v", job.ID, err)
+				}
+			}
+		}
+	}()
+
+	lastAttempt := job.Attempts >= judgeMaxAttempts
+	err := cfg.JudgeSubmission(ctx, job.SubmissionID, lastAttempt)
+	close(done)
+
+	switch {
+	case err == nil:
+		err = cfg.db.CompleteJudgeJob(ctx, database.CompleteJudgeJobParams{ID: job.ID, UpdatedAt: time.Now()})
+	case !lastAttempt:
+		// Back off so a struggling database or judge host gets time to recover
+		delay := time.Duration(job.Attempts*job.Attempts) * 10 * time.Second
+		cfg.logger.Printf("Judge worker %v failed on submission %v (attempt %d), retrying in %v: %v", worker, job.SubmissionID, job.Attempts, delay, err)
+		err = cfg.db.RetryJudgeJob(ctx, database.RetryJudgeJobParams{
+			ID:        job.ID,
+			LastError: err.Error(),
+			RunAfter:  time.Now().Add(delay),
+			UpdatedAt: time.Now(),
+		})
+		if err == nil {
+			err = cfg.db.MarkSubmissionPending(ctx, job.SubmissionID)
+		}
+	default:
+		cfg.logger.Printf("Judge worker %v gave up on submission %v after %d attempts: %v", worker, job.SubmissionID, job.Attempts, err)
+		failure := err.Error()
+		err = cfg.db.FailJudgeJob(ctx, database.FailJudgeJobParams{ID: job.ID, LastError: failure, UpdatedAt: time.Now()})
+		if err == nil {
+			// Leave the student with a verdict instead of a submission that runs forever
+			_, err = cfg.db.FinishSubmission(ctx, database.FinishSubmissionParams{
+				ID:            job.SubmissionID,
+				Verdict:       string(judge.VerdictInternalError),
+				CompileOutput: "judging failed, please submit again later",
+				JudgedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				err = nil
+			}
+		}
+	}
+	if err != nil {
+		cfg.logger.Printf("Failed to update judge job %v: %v", job.ID, err)
+	}
+	cfg.submissionEvents.Notify(job.SubmissionID)
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Rejudge
+
+===========================================
+*/
+
+// ErrNothingToRejudge is returned when every submission asked for is still waiting for a judge
+var ErrNothingToRejudge = errors.New("no submissions to rejudge")
+
+type RejudgeSubmissionResponse struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	OldVerdict   string    `json:"old_verdict"`
+	OldScore     int32     `json:"old_score"`
+	Status       string    `json:"status"`
+	Verdict      string    `json:"verdict"`
+	Score        int32     `json:"score"`
+	Changed      bool      `json:"changed"`
+}
+
+type RejudgeResponse struct {
+	ID          uuid.UUID     `json:"id"`
+	ProblemID   uuid.UUID     `json:"problem_id"`
+	Scope       string        `json:"scope"`
+	RequestedBy uuid.NullUUID `json:"requested_by"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Total       int           `json:"total"`
+	Pending     int           `json:"pending"`
+	// Changed counts the judged submissions whose verdict or score differ from before the rejudge
+	Changed     int                         `json:"changed"`
+	Submissions []RejudgeSubmissionResponse `json:"submissions"`
+}
+
+// startRejudge records the current verdicts of the submissions picked by add and queues them again
+func (cfg *ApiCfg) startRejudge(ctx context.Context, problemID uuid.UUID, scope string, requestedBy uuid.NullUUID, add func(q *database.Queries, rejudgeID uuid.UUID) ([]uuid.UUID, error)) (database.Rejudge, int, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Rejudge{}, 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	rejudge, err := qtx.CreateRejudge(ctx, database.CreateRejudgeParams{
+		ID:          uuid.New(),
+		ProblemID:   problemID,
+		Scope:       scope,
+		RequestedBy: requestedBy,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return database.Rejudge{}, 0, fmt.Errorf("failed to create rejudge: %v", err)
+	}
+	submissionIDs, err := add(qtx, rejudge.ID)
+	if err != nil {
+		return database.Rejudge{}, 0, fmt.Errorf("failed to record verdicts: %v", err)
+	}
+	if len(submissionIDs) == 0 {
+		return database.Rejudge{}, 0, ErrNothingToRejudge
+	}
+	for _, submissionID := range submissionIDs {
+		// Finished submissions are not marked pending by EnqueueSubmission
+		err = qtx.ResetSubmissionStatus(ctx, submissionID)
+		if err != nil {
+			return database.Rejudge{}, 0, fmt.Errorf("failed to reset submission %v: %v", submissionID, err)
+		}
+		err = cfg.EnqueueSubmission(ctx, qtx, submissionID)
+		if err != nil {
+			return database.Rejudge{}, 0, fmt.Errorf("submission %v: %v", submissionID, err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return database.Rejudge{}, 0, fmt.Errorf("failed to commit rejudge: %v", err)
+	}
+
+	cfg.logger.Printf("Rejudge %v queued %d submissions of problem %v", rejudge.ID, len(submissionIDs), problemID)
+	cfg.WakeJudgeWorkers()
+	return rejudge, len(submissionIDs), nil
+}
+
+// RejudgeProblem queues every submission of a problem again, those already waiting for a judge are skipped
+func (cfg *ApiCfg) RejudgeProblem(ctx context.Context, problem database.Problem, requestedBy uuid.NullUUID) (database.Rejudge, int, error) {
+	return cfg.startRejudge(ctx, problem.ID, "problem", requestedBy, func(q *database.Queries, rejudgeID uuid.UUID) ([]uuid.UUID, error) {
+		return q.AddProblemRejudgeSubmissions(ctx, database.AddProblemRejudgeSubmissionsParams{
+			RejudgeID: rejudgeID,
+			ProblemID: problem.ID,
+		})
+	})
+}
+
+// RejudgeSubmission queues a single submission again
+func (cfg *ApiCfg) RejudgeSubmission(ctx context.Context, submission database.Submission, requestedBy uuid.NullUUID) (database.Rejudge, error) {
+	rejudge, _, err := cfg.startRejudge(ctx, submission.ProblemID, "submission", requestedBy, func(q *database.Queries, rejudgeID uuid.UUID) ([]uuid.UUID, error) {
+		return q.AddRejudgeSubmission(ctx, database.AddRejudgeSubmissionParams{
+			RejudgeID: rejudgeID,
+			ID:        submission.ID,
+		})
+	})
+	return rejudge, err
+}
+
+// RejudgeReport compares the verdicts recorded by a rejudge with the current ones
+func (cfg *ApiCfg) RejudgeReport(ctx context.Context, rejudge database.Rejudge) (RejudgeResponse, error) {
+	rows, err := cfg.db.GetRejudgeSubmissions(ctx, rejudge.ID)
+	if err != nil {
+		return RejudgeResponse{}, fmt.Errorf("failed to retrieve rejudged submissions: %v", err)
+	}
+
+	res := RejudgeResponse{
+		ID:          rejudge.ID,
+		ProblemID:   rejudge.ProblemID,
+		Scope:       rejudge.Scope,
+		RequestedBy: rejudge.RequestedBy,
+		CreatedAt:   rejudge.CreatedAt,
+		Total:       len(rows),
+		Submissions: make([]RejudgeSubmissionResponse, 0, len(rows)),
+	}
+	for _, row := range rows {
+		submission := RejudgeSubmissionResponse{
+			SubmissionID: row.SubmissionID,
+			UserID:       row.UserID,
+			OldVerdict:   row.OldVerdict,
+			OldScore:     row.OldScore,
+			Status:       row.Status,
+			Verdict:      row.Verdict,
+			Score:        row.Score,
+		}
+		if row.Status != "done" {
+			res.Pending++
+		} else if row.Verdict != row.OldVerdict || row.Score != row.OldScore {
+			submission.Changed = true
+			res.Changed++
+		}
+		res.Submissions = append(res.Submissions, submission)
+	}
+	return res, nil
+}
+
+// respondRejudge sends the report of a rejudge that was just queued
+func (cfg *ApiCfg) respondRejudge(w http.ResponseWriter, r *http.Request, rejudge database.Rejudge, err error) {
+	if err != nil {
+		if errors.Is(err, ErrNothingToRejudge) {
+			http.Error(w, "Submissions are already waiting for a judge", http.StatusConflict)
+			return
+		}
+		cfg.logger.Printf("Failed to rejudge: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.RejudgeReport(r.Context(), rejudge)
+	if err != nil {
+		cfg.logger.Printf("Rejudge %v: %v", rejudge.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusAccepted, res)
+}
+
+func (cfg *ApiCfg) RejudgeProblemHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized problem rejudge by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	key := r.PathValue("problemID")
+	problem, err := cfg.GetProblem(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", key)
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	rejudge, _, err := cfg.RejudgeProblem(r.Context(), problem, uuid.NullUUID{UUID: adminUser.ID, Valid: true})
+	cfg.respondRejudge(w, r, rejudge, err)
+}
+
+func (cfg *ApiCfg) RejudgeSubmissionHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized submission rejudge by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	submissionID, err := uuid.Parse(r.PathValue("submissionID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid submission ID: %v", err)
+		http.Error(w, "Invalid submission ID", http.StatusBadRequest)
+		return
+	}
+
+	submission, err := cfg.db.GetSubmissionByID(r.Context(), submissionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Submission not found: %v", submissionID)
+			http.Error(w, "Submission not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve submission: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	rejudge, err := cfg.RejudgeSubmission(r.Context(), submission, uuid.NullUUID{UUID: adminUser.ID, Valid: true})
+	cfg.respondRejudge(w, r, rejudge, err)
+}
+
+// GetRejudgeHandler reports the progress of a rejudge and how many verdicts it changed
+func (cfg *ApiCfg) GetRejudgeHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized rejudge access by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	rejudgeID, err := uuid.Parse(r.PathValue("rejudgeID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid rejudge ID: %v", err)
+		http.Error(w, "Invalid rejudge ID", http.StatusBadRequest)
+		return
+	}
+
+	rejudge, err := cfg.db.GetRejudgeByID(r.Context(), rejudgeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Rejudge not found: %v", rejudgeID)
+			http.Error(w, "Rejudge not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve rejudge: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.RejudgeReport(r.Context(), rejudge)
+	if err != nil {
+		cfg.logger.Printf("Rejudge %v: %v", rejudge.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
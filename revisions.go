@@ -0,0 +1,325 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/diff"
+	"Codium/internal/lessons"
+	"Codium/internal/markdown"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Lesson Revisions
+
+===========================================
+*/
+
+// RecordLessonRevision stores content as a new revision of the lesson, unless it is identical to the latest one
+func (cfg *ApiCfg) RecordLessonRevision(ctx context.Context, lessonID int32, authorID uuid.NullUUID, content []byte) error {
+	hash := markdown.Hash(content)
+	latest, err := cfg.db.GetLatestLessonRevision(ctx, lessonID)
+	if err == nil && latest.ContentHash == hash {
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to retrieve latest revision: %v", err)
+	}
+
+	_, err = cfg.db.CreateLessonRevision(ctx, database.CreateLessonRevisionParams{
+		ID:          uuid.New(),
+		LessonID:    lessonID,
+		AuthorID:    authorID,
+		Content:     string(content),
+		ContentHash: hash,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create revision: %v", err)
+	}
+	return nil
+}
+
+// RollbackLesson restores the lesson file and catalog entry to the content of a previous revision.
+// The rollback is recorded as a new revision authored by the admin.
+func (cfg *ApiCfg) RollbackLesson(ctx context.Context, lessonID int32, revisionID uuid.UUID, admin database.User) (database.Lesson, error) {
+	// Keep the watcher from recording the rewritten file as an anonymous revision
+	cfg.lessonScanMu.Lock()
+	defer cfg.lessonScanMu.Unlock()
+
+	revision, err := cfg.db.GetLessonRevisionByID(ctx, revisionID)
+	if err != nil {
+		return database.Lesson{}, err
+	}
+	if revision.LessonID != lessonID {
+		return database.Lesson{}, sql.ErrNoRows
+	}
+
+	lesson, err := cfg.db.GetLessonByID(ctx, lessonID)
+	if err != nil {
+		return database.Lesson{}, err
+	}
+
+	meta, body, err := lessons.Parse(lesson.Filepath, []byte(revision.Content))
+	if err != nil {
+		return database.Lesson{}, fmt.Errorf("failed to parse revision: %v", err)
+	}
+	if meta.ID != lessonID {
+		return database.Lesson{}, fmt.Errorf("revision declares lesson %v instead of %v", meta.ID, lessonID)
+	}
+
+	err = os.WriteFile(lesson.Filepath, []byte(revision.Content), 0644)
+	if err != nil {
+		return database.Lesson{}, fmt.Errorf("failed to write lesson file: %v", err)
+	}
+
+	lesson, err = cfg.StoreLesson(ctx, meta, lesson.Filepath, body, lessons.Status(lesson.Status), lesson.AuthorID)
+	if err != nil {
+		return database.Lesson{}, fmt.Errorf("failed to update lesson: %v", err)
+	}
+
+	err = cfg.RecordLessonRevision(ctx, lessonID, uuid.NullUUID{UUID: admin.ID, Valid: true}, []byte(revision.Content))
+	if err != nil {
+		return database.Lesson{}, err
+	}
+
+	cfg.logger.Printf("Lesson %v rolled back to revision %v by %v", lessonID, revisionID, admin.ID)
+	return lesson, nil
+}
+
+type LessonRevisionResponse struct {
+	ID          uuid.UUID     `json:"id"`
+	LessonID    int32         `json:"lesson_id"`
+	AuthorID    uuid.NullUUID `json:"author_id"`
+	ContentHash string        `json:"content_hash"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+func (cfg *ApiCfg) GetLessonRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !cfg.CanViewLesson(r, lesson) {
+		cfg.logger.Printf("Lesson %v is not visible to the requester", lessonID)
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		return
+	}
+
+	limit, offset := 50, 0
+	q := r.URL.Query()
+	if q.Get("limit") != "" {
+		limit, err = strconv.Atoi(q.Get("limit"))
+		if err != nil || limit <= 0 || limit > 200 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	if q.Get("offset") != "" {
+		offset, err = strconv.Atoi(q.Get("offset"))
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cfg.logger.Printf("Received list revisions request for lesson ID: %v", lessonID)
+
+	revisions, err := cfg.db.GetLessonRevisions(r.Context(), database.GetLessonRevisionsParams{
+		LessonID: int32(lessonID),
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve revisions: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]LessonRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		res = append(res, LessonRevisionResponse{
+			ID:          revision.ID,
+			LessonID:    revision.LessonID,
+			AuthorID:    revision.AuthorID,
+			ContentHash: revision.ContentHash,
+			CreatedAt:   revision.CreatedAt,
+		})
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// GetLessonRevisionDiffHandler compares the "from" revision with the "to" revision, or with the latest one when "to" is missing
+func (cfg *ApiCfg) GetLessonRevisionDiffHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !cfg.CanViewLesson(r, lesson) {
+		cfg.logger.Printf("Lesson %v is not visible to the requester", lessonID)
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		return
+	}
+
+	q := r.URL.Query()
+	fromID, err := uuid.Parse(q.Get("from"))
+	if err != nil {
+		cfg.logger.Printf("Invalid from revision: %v", err)
+		http.Error(w, "Invalid from revision", http.StatusBadRequest)
+		return
+	}
+
+	from, err := cfg.db.GetLessonRevisionByID(r.Context(), fromID)
+	if err == nil && from.LessonID != int32(lessonID) {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve revision: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var to database.LessonRevision
+	if q.Get("to") != "" {
+		toID, err := uuid.Parse(q.Get("to"))
+		if err != nil {
+			cfg.logger.Printf("Invalid to revision: %v", err)
+			http.Error(w, "Invalid to revision", http.StatusBadRequest)
+			return
+		}
+		to, err = cfg.db.GetLessonRevisionByID(r.Context(), toID)
+	} else {
+		to, err = cfg.db.GetLatestLessonRevision(r.Context(), int32(lessonID))
+	}
+	if err == nil && to.LessonID != int32(lessonID) {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve revision: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.logger.Printf("Received diff request for lesson %v: %v..%v", lessonID, from.ID, to.ID)
+
+	unified, err := diff.Unified(from.ID.String(), to.ID.String(), from.Content, to.Content, 3)
+	if err != nil {
+		cfg.logger.Printf("Refused diff of lesson %v: %v", lessonID, err)
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte(unified))
+	if err != nil {
+		cfg.logger.Printf("Failed to write response: %v", err)
+		return
+	}
+}
+
+func (cfg *ApiCfg) RollbackLessonHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	adminUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !adminUser.IsAdmin {
+		cfg.logger.Printf("Unauthorized rollback attempt by non-admin user: %v", adminUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+	revisionID, err := uuid.Parse(r.PathValue("revisionID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid UUID format: %v", err)
+		http.Error(w, "Invalid revision ID format", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := cfg.RollbackLesson(r.Context(), int32(lessonID), revisionID, adminUser)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to roll back lesson %v: %v", lessonID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.RespondWithJSON(w, http.StatusOK, LessonToResponse(lesson, true))
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"Codium/internal/judge"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Scratch Runs
+
+===========================================
+*/
+
+const (
+	maxRunInputBytes = 1 << 20
+	// Limits of a run that is not tied to a problem
+	runTimeLimitMs   = 2000
+	runMemoryLimitMb = 256
+	runRateWindow    = time.Minute
+)
+
+// RunLimiter allows each user a number of scratch runs per window and one run at a time
+type RunLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	history map[uuid.UUID][]time.Time
+	active  map[uuid.UUID]bool
+}
+
+func NewRunLimiter(limit int, window time.Duration) *RunLimiter {
+	return &RunLimiter{
+		limit:   limit,
+		window:  window,
+		history: make(map[uuid.UUID][]time.Time),
+		active:  make(map[uuid.UUID]bool),
+	}
+}
+
+// Acquire reserves a run for the user, if refused it reports how long to wait before trying again.
+// A successful Acquire must be followed by Release once the run finished
+func (l *RunLimiter) Acquire(userID uuid.UUID, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.history[userID][:0]
+	for _, t := range l.history[userID] {
+		if now.Sub(t) < l.window {
+			recent = append(recent, t)
+		}
+	}
+	l.history[userID] = recent
+
+	if l.active[userID] {
+		return false, time.Second
+	}
+	if len(recent) >= l.limit {
+		return false, recent[0].Add(l.window).Sub(now)
+	}
+	l.history[userID] = append(recent, now)
+	l.active[userID] = true
+	return true, 0
+}
+
+func (l *RunLimiter) Release(userID uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.active, userID)
+}
+
+// RunHandler compiles and runs code once on input the student provides, nothing is stored.
+// Passing a problem borrows its time and memory limits
+func (cfg *ApiCfg) RunHandler(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		Language string `json:"language"`
+		Source   string `json:"source"`
+		Stdin    string `json:"stdin"`
+		Problem  string `json:"problem"`
+	}
+
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Runs hand the raw output back, without the jail a program could print any server file
+	if !cfg.judge.Isolate {
+		cfg.logger.Printf("Rejected run of user %v, the judge is not isolated", user.ID)
+		http.Error(w, "Running code is disabled on this server", http.StatusServiceUnavailable)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 2*(maxSourceBytes+maxRunInputBytes))
+	decoder := json.NewDecoder(r.Body)
+	var p params
+	err = decoder.Decode(&p)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if _, ok := cfg.judge.Languages.Get(p.Language); !ok {
+		http.Error(w, fmt.Sprintf("Unsupported language %q", p.Language), http.StatusBadRequest)
+		return
+	}
+	if len(p.Source) == 0 || len(p.Source) > maxSourceBytes {
+		http.Error(w, fmt.Sprintf("Source must be between 1 and %v bytes", maxSourceBytes), http.StatusBadRequest)
+		return
+	}
+	if len(p.Stdin) > maxRunInputBytes {
+		http.Error(w, fmt.Sprintf("Input must be at most %v bytes", maxRunInputBytes), http.StatusBadRequest)
+		return
+	}
+
+	limits := judge.Limits{TimeMs: runTimeLimitMs, MemoryMb: runMemoryLimitMb}
+	if p.Problem != "" {
+		problem, err := cfg.GetProblem(r.Context(), p.Problem)
+		if err == nil && !user.IsAdmin {
+			err = cfg.checkProblemVisible(r.Context(), problem)
+		}
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Problem not found", http.StatusNotFound)
+				return
+			}
+			cfg.logger.Printf("Failed to retrieve problem: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		limits = judge.Limits{TimeMs: problem.TimeLimitMs, MemoryMb: problem.MemoryLimitMb}
+	}
+
+	ok, retryAfter := cfg.runLimiter.Acquire(user.ID, time.Now())
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		http.Error(w, "Too many runs, try again later", http.StatusTooManyRequests)
+		return
+	}
+	defer cfg.runLimiter.Release(user.ID)
+
+	// Runs share a fixed number of slots so they cannot crowd out the judge workers
+	select {
+	case cfg.runSlots <- struct{}{}:
+		defer func() { <-cfg.runSlots }()
+	case <-r.Context().Done():
+		return
+	}
+
+	cfg.logger.Printf("User %v runs %v code on custom input", user.ID, p.Language)
+	result := cfg.judge.Execute(r.Context(), p.Language, []byte(p.Source), []byte(p.Stdin), limits)
+	if result.Verdict == judge.VerdictInternalError {
+		cfg.logger.Printf("Scratch run by user %v failed: %v", user.ID, result.Message)
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, result)
+}
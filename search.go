@@ -0,0 +1,98 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+===========================================
+
+	Lesson Search
+
+===========================================
+*/
+
+// Postgres marks matches with these, they are swapped for <mark> tags once the snippet is HTML escaped
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var searchHeadlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \""
+
+type LessonSearchResult struct {
+	ID      int32   `json:"id"`
+	Grade   string  `json:"grade"`
+	Title   string  `json:"title"`
+	Slug    string  `json:"slug"`
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func highlightSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, highlightStart, "<mark>")
+	return strings.ReplaceAll(snippet, highlightStop, "</mark>")
+}
+
+func (cfg *ApiCfg) SearchLessonsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		cfg.logger.Printf("Missing search query")
+		http.Error(w, "Missing search query", http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(query) > 200 {
+		http.Error(w, "Search query is too long", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+	if q.Get("limit") != "" {
+		var err error
+		limit, err = strconv.Atoi(q.Get("limit"))
+		if err != nil || limit <= 0 || limit > 100 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cfg.logger.Printf("Received lesson search request: %q", query)
+
+	rows, err := cfg.db.SearchLessons(r.Context(), database.SearchLessonsParams{
+		Query:           query,
+		HeadlineOptions: searchHeadlineOptions,
+		MaxResults:      int32(limit),
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to search lessons: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]LessonSearchResult, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, LessonSearchResult{
+			ID:      row.ID,
+			Grade:   row.Grade,
+			Title:   row.Title,
+			Slug:    row.Slug,
+			Rank:    row.Rank,
+			Snippet: highlightSnippet(row.Snippet),
+		})
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
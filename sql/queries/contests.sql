@@ -0,0 +1,113 @@
+-- name: CreateContest :one
+INSERT INTO contests (id, slug, title, description, scoring, starts_at, ends_at, freeze_minutes, author_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+RETURNING *;
+
+-- name: UpdateContest :one
+UPDATE contests
+SET slug = $2,
+    title = $3,
+    description = $4,
+    scoring = $5,
+    starts_at = $6,
+    ends_at = $7,
+    freeze_minutes = $8,
+    updated_at = $9
+WHERE id = $1
+RETURNING *;
+
+-- name: GetContestByID :one
+SELECT * FROM contests
+WHERE id = $1;
+
+-- name: GetContestBySlug :one
+SELECT * FROM contests
+WHERE slug = $1;
+
+-- name: GetContests :many
+SELECT * FROM contests
+ORDER BY starts_at DESC, id
+LIMIT $1 OFFSET $2;
+
+-- name: DeleteContest :exec
+DELETE FROM contests
+WHERE id = $1;
+
+-- name: UnfreezeContest :one
+UPDATE contests
+SET unfrozen_at = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: AddContestProblem :exec
+INSERT INTO contest_problems (contest_id, problem_id, label, position)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteContestProblems :exec
+DELETE FROM contest_problems
+WHERE contest_id = $1;
+
+-- name: GetContestProblems :many
+SELECT contest_problems.label, contest_problems.position, problems.id, problems.slug, problems.title,
+    problems.time_limit_ms, problems.memory_limit_mb
+FROM contest_problems
+JOIN problems ON problems.id = contest_problems.problem_id
+WHERE contest_problems.contest_id = $1
+ORDER BY contest_problems.position;
+
+-- name: IsProblemInUpcomingContest :one
+SELECT EXISTS (
+    SELECT 1 FROM contest_problems
+    JOIN contests ON contests.id = contest_problems.contest_id
+    WHERE contest_problems.problem_id = $1 AND contests.starts_at > $2
+);
+
+-- name: RegisterForContest :exec
+INSERT INTO contest_registrations (contest_id, user_id, registered_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id, user_id) DO NOTHING;
+
+-- name: UnregisterFromContest :execrows
+DELETE FROM contest_registrations
+WHERE contest_id = $1 AND user_id = $2;
+
+-- name: IsRegisteredForContest :one
+SELECT EXISTS (
+    SELECT 1 FROM contest_registrations
+    WHERE contest_id = $1 AND user_id = $2
+);
+
+-- name: GetContestParticipants :many
+SELECT contest_registrations.user_id, users.username, contest_registrations.registered_at
+FROM contest_registrations
+JOIN users ON users.id = contest_registrations.user_id
+WHERE contest_registrations.contest_id = $1
+ORDER BY users.username;
+
+-- name: GetContestAttempts :many
+SELECT user_id, problem_id, status, verdict, score, created_at
+FROM submissions
+WHERE contest_id = $1 AND NOT virtual
+ORDER BY created_at;
+
+-- name: HasContestSubmissions :one
+SELECT EXISTS (
+    SELECT 1 FROM submissions
+    WHERE contest_id = $1 AND user_id = $2 AND NOT virtual
+);
+
+-- name: StartVirtualParticipation :one
+INSERT INTO contest_virtual_participations (contest_id, user_id, started_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (contest_id, user_id) DO NOTHING
+RETURNING *;
+
+-- name: GetVirtualParticipation :one
+SELECT * FROM contest_virtual_participations
+WHERE contest_id = $1 AND user_id = $2;
+
+-- name: GetVirtualAttempts :many
+SELECT user_id, problem_id, status, verdict, score, created_at
+FROM submissions
+WHERE contest_id = $1 AND user_id = $2 AND virtual
+ORDER BY created_at;
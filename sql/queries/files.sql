@@ -11,4 +11,26 @@ WHERE id = $1;
 SELECT * FROM files
 WHERE user_id = $1
 ORDER BY uploaded_at DESC
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+LIMIT $2 OFFSET $3;
+
+-- name: GetPersonalFilesByUserID :many
+-- Files that go away with their uploader, the tests of problems and lessons stay in the catalog
+SELECT * FROM files
+WHERE user_id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM problem_tests
+        WHERE problem_tests.input_file_id = files.id
+            OR problem_tests.output_file_id = files.id
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM lessons
+        WHERE lessons.filepath = files.filepath
+    );
+
+-- name: DeleteFile :exec
+DELETE FROM files
+WHERE id = $1;
+
+-- name: DeleteFilesByPath :exec
+DELETE FROM files
+WHERE filepath = $1;
\ No newline at end of file
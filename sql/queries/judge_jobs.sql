@@ -0,0 +1,42 @@
+-- name: EnqueueJudgeJob :one
+INSERT INTO judge_jobs (id, submission_id, run_after, created_at, updated_at)
+VALUES ($1, $2, $3, $3, $3)
+RETURNING *;
+
+-- name: ClaimJudgeJob :one
+UPDATE judge_jobs
+SET status = 'running', attempts = attempts + 1, locked_by = $1, locked_at = $2, updated_at = $2
+WHERE id = (
+    SELECT id FROM judge_jobs
+    WHERE status = 'queued' AND run_after <= $2
+    ORDER BY run_after, created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: HeartbeatJudgeJob :exec
+UPDATE judge_jobs
+SET locked_at = $2
+WHERE id = $1 AND status = 'running';
+
+-- name: CompleteJudgeJob :exec
+UPDATE judge_jobs
+SET status = 'done', locked_by = NULL, locked_at = NULL, updated_at = $2
+WHERE id = $1;
+
+-- name: RetryJudgeJob :exec
+UPDATE judge_jobs
+SET status = 'queued', last_error = $2, run_after = $3, locked_by = NULL, locked_at = NULL, updated_at = $4
+WHERE id = $1;
+
+-- name: FailJudgeJob :exec
+UPDATE judge_jobs
+SET status = 'failed', last_error = $2, locked_by = NULL, locked_at = NULL, updated_at = $3
+WHERE id = $1;
+
+-- name: RequeueStaleJudgeJobs :many
+UPDATE judge_jobs
+SET status = 'queued', locked_by = NULL, locked_at = NULL, run_after = $1, updated_at = $1
+WHERE status = 'running' AND locked_at < $2
+RETURNING submission_id;
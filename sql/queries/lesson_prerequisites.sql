@@ -0,0 +1,17 @@
+-- name: GetAllLessonPrerequisites :many
+SELECT * FROM lesson_prerequisites
+ORDER BY lesson_id, prerequisite_id;
+
+-- name: GetLessonPrerequisites :many
+SELECT prerequisite_id FROM lesson_prerequisites
+WHERE lesson_id = $1
+ORDER BY prerequisite_id;
+
+-- name: AddLessonPrerequisite :exec
+INSERT INTO lesson_prerequisites (lesson_id, prerequisite_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteLessonPrerequisites :exec
+DELETE FROM lesson_prerequisites
+WHERE lesson_id = $1;
\ No newline at end of file
@@ -0,0 +1,38 @@
+-- name: UpsertLessonProgress :one
+INSERT INTO lesson_progress (user_id, lesson_id, started_at, completed_at, last_section, updated_at)
+VALUES ($1, $2, $3, $4, $5, $3)
+ON CONFLICT (user_id, lesson_id) DO UPDATE
+SET last_section = CASE WHEN EXCLUDED.last_section <> '' THEN EXCLUDED.last_section ELSE lesson_progress.last_section END,
+    completed_at = COALESCE(lesson_progress.completed_at, EXCLUDED.completed_at),
+    updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetLessonProgress :one
+SELECT * FROM lesson_progress
+WHERE user_id = $1 AND lesson_id = $2;
+
+-- name: GetUserProgress :many
+SELECT lesson_progress.*, lessons.grade, lessons.title
+FROM lesson_progress
+JOIN lessons ON lessons.id = lesson_progress.lesson_id
+WHERE lesson_progress.user_id = $1 AND lessons.status = 'published'
+ORDER BY lessons.grade, lessons.id;
+
+-- name: GetUserProgressSummary :many
+SELECT lessons.grade,
+    COUNT(lessons.id) AS total,
+    COUNT(lesson_progress.started_at) AS started,
+    COUNT(lesson_progress.completed_at) AS completed
+FROM lessons
+LEFT JOIN lesson_progress ON lesson_progress.lesson_id = lessons.id AND lesson_progress.user_id = $1
+WHERE lessons.status = 'published'
+GROUP BY lessons.grade
+ORDER BY lessons.grade;
+
+-- name: GetCompletedLessonIDs :many
+SELECT lesson_id FROM lesson_progress
+WHERE user_id = $1 AND completed_at IS NOT NULL;
+
+-- name: GetStartedLessonIDs :many
+SELECT lesson_id FROM lesson_progress
+WHERE user_id = $1 AND completed_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- name: CreateLessonReview :one
+INSERT INTO lesson_reviews (id, lesson_id, reviewer_id, action, from_status, to_status, comment, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetLessonReviews :many
+SELECT * FROM lesson_reviews
+WHERE lesson_id = $1
+ORDER BY created_at DESC;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: CreateLessonRevision :one
+INSERT INTO lesson_revisions (id, lesson_id, author_id, content, content_hash, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetLessonRevisionByID :one
+SELECT * FROM lesson_revisions
+WHERE id = $1;
+
+-- name: GetLatestLessonRevision :one
+SELECT * FROM lesson_revisions
+WHERE lesson_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetLessonRevisions :many
+SELECT id, lesson_id, author_id, content_hash, created_at FROM lesson_revisions
+WHERE lesson_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
\ No newline at end of file
@@ -0,0 +1,51 @@
+-- name: UpsertLesson :one
+INSERT INTO lessons (id, grade, title, slug, filepath, body, tags, author, estimated_minutes, status, author_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+ON CONFLICT (id) DO UPDATE
+SET grade = EXCLUDED.grade,
+    title = EXCLUDED.title,
+    slug = EXCLUDED.slug,
+    filepath = EXCLUDED.filepath,
+    body = EXCLUDED.body,
+    tags = EXCLUDED.tags,
+    author = EXCLUDED.author,
+    estimated_minutes = EXCLUDED.estimated_minutes,
+    updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetLessonByID :one
+SELECT * FROM lessons
+WHERE id = $1;
+
+-- name: GetLessons :many
+SELECT * FROM lessons
+ORDER BY grade, id;
+
+-- name: GetLessonsByStatus :many
+SELECT * FROM lessons
+WHERE status = $1
+ORDER BY grade, id;
+
+-- name: GetLessonsByGradeAndStatus :many
+SELECT * FROM lessons
+WHERE grade = $1 AND status = $2
+ORDER BY id;
+
+-- name: UpdateLessonStatus :one
+UPDATE lessons
+SET status = $2, updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SearchLessons :many
+SELECT id, grade, title, slug,
+    ts_rank(
+        setweight(to_tsvector('codium_ro', title), 'A') || setweight(to_tsvector('codium_ro', body), 'B'),
+        websearch_to_tsquery('codium_ro', sqlc.arg(query))
+    )::real AS rank,
+    ts_headline('codium_ro', body, websearch_to_tsquery('codium_ro', sqlc.arg(query)), sqlc.arg(headline_options))::text AS snippet
+FROM lessons
+WHERE status = 'published'
+    AND (setweight(to_tsvector('codium_ro', title), 'A') || setweight(to_tsvector('codium_ro', body), 'B')) @@ websearch_to_tsquery('codium_ro', sqlc.arg(query))
+ORDER BY rank DESC, id
+LIMIT sqlc.arg(max_results);
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- name: AddProblemSubtask :exec
+INSERT INTO problem_subtasks (problem_id, number, points, tests, depends_on)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetProblemSubtasks :many
+SELECT * FROM problem_subtasks
+WHERE problem_id = $1
+ORDER BY number;
+
+-- name: DeleteProblemSubtasks :exec
+DELETE FROM problem_subtasks
+WHERE problem_id = $1;
@@ -0,0 +1,17 @@
+-- name: AddProblemTest :exec
+INSERT INTO problem_tests (problem_id, position, name, input_file_id, output_file_id)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetProblemTests :many
+SELECT problem_tests.position, problem_tests.name,
+    problem_tests.input_file_id, input_file.filepath AS input_path, input_file.filesize AS input_size,
+    problem_tests.output_file_id, output_file.filepath AS output_path, output_file.filesize AS output_size
+FROM problem_tests
+JOIN files AS input_file ON input_file.id = problem_tests.input_file_id
+JOIN files AS output_file ON output_file.id = problem_tests.output_file_id
+WHERE problem_tests.problem_id = $1
+ORDER BY problem_tests.position;
+
+-- name: DeleteProblemTests :exec
+DELETE FROM problem_tests
+WHERE problem_id = $1;
@@ -0,0 +1,59 @@
+-- name: CreateProblem :one
+INSERT INTO problems (id, slug, title, statement, input_format, output_format, time_limit_ms, memory_limit_mb, difficulty, tags, author_id, checker_mode, checker_epsilon, checker_source, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $15)
+RETURNING *;
+
+-- name: UpdateProblem :one
+UPDATE problems
+SET slug = $2,
+    title = $3,
+    statement = $4,
+    input_format = $5,
+    output_format = $6,
+    time_limit_ms = $7,
+    memory_limit_mb = $8,
+    difficulty = $9,
+    tags = $10,
+    checker_mode = $11,
+    checker_epsilon = $12,
+    checker_source = $13,
+    updated_at = $14
+WHERE id = $1
+RETURNING *;
+
+-- name: GetProblemByID :one
+SELECT * FROM problems
+WHERE id = $1;
+
+-- name: GetProblemBySlug :one
+SELECT * FROM problems
+WHERE slug = $1;
+
+-- name: GetProblems :many
+SELECT * FROM problems
+WHERE (sqlc.narg('difficulty')::text IS NULL OR difficulty = sqlc.narg('difficulty')::text)
+  AND (sqlc.narg('tag')::text IS NULL OR sqlc.narg('tag')::text = ANY(tags))
+  AND (sqlc.narg('visible_at')::timestamptz IS NULL OR NOT EXISTS (
+    SELECT 1 FROM contest_problems
+    JOIN contests ON contests.id = contest_problems.contest_id
+    WHERE contest_problems.problem_id = problems.id AND contests.starts_at > sqlc.narg('visible_at')::timestamptz
+  ))
+ORDER BY created_at, id
+LIMIT $1 OFFSET $2;
+
+-- name: DeleteProblem :exec
+DELETE FROM problems
+WHERE id = $1;
+
+-- name: AddProblemSample :exec
+INSERT INTO problem_samples (problem_id, position, input, output, explanation)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetProblemSamples :many
+SELECT * FROM problem_samples
+WHERE problem_id = $1
+ORDER BY position;
+
+-- name: DeleteProblemSamples :exec
+DELETE FROM problem_samples
+WHERE problem_id = $1;
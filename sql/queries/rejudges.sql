@@ -0,0 +1,40 @@
+-- name: CreateRejudge :one
+INSERT INTO rejudges (id, problem_id, scope, requested_by, created_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetRejudgeByID :one
+SELECT * FROM rejudges
+WHERE id = $1;
+
+-- Submissions that are already waiting for a judge are left alone, they will see the new tests anyway
+
+-- name: AddProblemRejudgeSubmissions :many
+INSERT INTO rejudge_submissions (rejudge_id, submission_id, old_verdict, old_score)
+SELECT $1, submissions.id, submissions.verdict, submissions.score
+FROM submissions
+WHERE submissions.problem_id = $2
+  AND NOT EXISTS (
+    SELECT 1 FROM judge_jobs
+    WHERE judge_jobs.submission_id = submissions.id AND judge_jobs.status IN ('queued', 'running')
+  )
+RETURNING submission_id;
+
+-- name: AddRejudgeSubmission :many
+INSERT INTO rejudge_submissions (rejudge_id, submission_id, old_verdict, old_score)
+SELECT $1, submissions.id, submissions.verdict, submissions.score
+FROM submissions
+WHERE submissions.id = $2
+  AND NOT EXISTS (
+    SELECT 1 FROM judge_jobs
+    WHERE judge_jobs.submission_id = submissions.id AND judge_jobs.status IN ('queued', 'running')
+  )
+RETURNING submission_id;
+
+-- name: GetRejudgeSubmissions :many
+SELECT rejudge_submissions.submission_id, submissions.user_id, rejudge_submissions.old_verdict, rejudge_submissions.old_score,
+    submissions.status, submissions.verdict, submissions.score
+FROM rejudge_submissions
+JOIN submissions ON submissions.id = rejudge_submissions.submission_id
+WHERE rejudge_submissions.rejudge_id = $1
+ORDER BY submissions.created_at;
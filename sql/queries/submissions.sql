@@ -0,0 +1,80 @@
+-- name: CreateSubmission :one
+INSERT INTO submissions (id, user_id, problem_id, language, source, contest_id, virtual, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSubmissionByID :one
+SELECT * FROM submissions
+WHERE id = $1;
+
+-- name: GetUserSubmissions :many
+SELECT submissions.id, submissions.problem_id, problems.slug AS problem_slug, problems.title AS problem_title,
+    submissions.language, submissions.status, submissions.verdict, submissions.score, submissions.max_score,
+    submissions.time_ms, submissions.memory_kb,
+    submissions.created_at, submissions.judged_at
+FROM submissions
+JOIN problems ON problems.id = submissions.problem_id
+WHERE submissions.user_id = $1
+  AND (sqlc.narg('problem_id')::uuid IS NULL OR submissions.problem_id = sqlc.narg('problem_id')::uuid)
+ORDER BY submissions.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: StartSubmission :exec
+UPDATE submissions
+SET status = 'compiling', verdict = '', compile_output = '', time_ms = 0, memory_kb = 0, score = 0, max_score = 0, judged_at = NULL
+WHERE id = $1;
+
+-- name: MarkSubmissionRunning :exec
+UPDATE submissions
+SET status = 'running'
+WHERE id = $1;
+
+-- name: FinishSubmission :one
+UPDATE submissions
+SET status = 'done', verdict = $2, compile_output = $3, time_ms = $4, memory_kb = $5, score = $6, max_score = $7, judged_at = $8
+WHERE id = $1
+RETURNING *;
+
+-- name: AddSubmissionTest :exec
+INSERT INTO submission_tests (submission_id, position, name, verdict, time_ms, memory_kb, message)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetSubmissionTests :many
+SELECT * FROM submission_tests
+WHERE submission_id = $1
+ORDER BY position;
+
+-- name: DeleteSubmissionTests :exec
+DELETE FROM submission_tests
+WHERE submission_id = $1;
+
+-- name: MarkSubmissionPending :exec
+UPDATE submissions
+SET status = 'pending'
+WHERE id = $1 AND status <> 'done';
+
+-- name: AddSubmissionSubtask :exec
+INSERT INTO submission_subtasks (submission_id, number, points, score, verdict)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetSubmissionSubtasks :many
+SELECT * FROM submission_subtasks
+WHERE submission_id = $1
+ORDER BY number;
+
+-- name: DeleteSubmissionSubtasks :exec
+DELETE FROM submission_subtasks
+WHERE submission_id = $1;
+-- name: ResetSubmissionStatus :exec
+UPDATE submissions
+SET status = 'pending'
+WHERE id = $1;
+
+-- name: GetLatestProblemSubmissions :many
+SELECT DISTINCT ON (submissions.user_id) submissions.id, submissions.user_id, users.username, submissions.language,
+    submissions.source, submissions.verdict, submissions.created_at
+FROM submissions
+JOIN users ON users.id = submissions.user_id
+WHERE submissions.problem_id = $1
+  AND (sqlc.narg('contest_id')::uuid IS NULL OR submissions.contest_id = sqlc.narg('contest_id'))
+ORDER BY submissions.user_id, submissions.created_at DESC;
\ No newline at end of file
@@ -56,4 +56,10 @@ WHERE id = $1;
 UPDATE users
 SET email_validated = FALSE, updated_at = $2
 WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserTeacher :one
+UPDATE users
+SET is_teacher = $2, updated_at = $3
+WHERE id = $1
 RETURNING *;
\ No newline at end of file
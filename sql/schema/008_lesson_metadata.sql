@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE lessons
+ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+ADD COLUMN author TEXT NOT NULL DEFAULT '',
+ADD COLUMN estimated_minutes INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE lessons
+DROP COLUMN tags,
+DROP COLUMN author,
+DROP COLUMN estimated_minutes;
\ No newline at end of file
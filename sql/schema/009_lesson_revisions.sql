@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS lesson_revisions (
+    id uuid PRIMARY KEY,
+    lesson_id INTEGER NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    author_id uuid REFERENCES users(id) ON DELETE SET NULL,
+    content TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS lesson_revisions_lesson_idx ON lesson_revisions (lesson_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_revisions;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN is_teacher BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Lessons that already exist on disk stay public
+ALTER TABLE lessons
+ADD COLUMN status TEXT NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+ADD COLUMN author_id uuid REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS lesson_reviews (
+    id uuid PRIMARY KEY,
+    lesson_id INTEGER NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    reviewer_id uuid REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_reviews;
+
+ALTER TABLE lessons
+DROP COLUMN author_id,
+DROP COLUMN status;
+
+ALTER TABLE users
+DROP COLUMN is_teacher;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Romanian stemming on top of unaccent, so "structura" matches "structură"
+CREATE TEXT SEARCH CONFIGURATION codium_ro (COPY = romanian);
+ALTER TEXT SEARCH CONFIGURATION codium_ro
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, romanian_stem;
+
+-- Must match the expression used by the SearchLessons query
+CREATE INDEX IF NOT EXISTS lessons_search_idx ON lessons USING GIN ((
+    setweight(to_tsvector('codium_ro', title), 'A') || setweight(to_tsvector('codium_ro', body), 'B')
+));
+
+-- +goose Down
+DROP INDEX IF EXISTS lessons_search_idx;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS codium_ro;
+DROP EXTENSION IF EXISTS unaccent;
\ No newline at end of file
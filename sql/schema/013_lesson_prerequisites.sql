@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS lesson_prerequisites (
+    lesson_id INTEGER NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    prerequisite_id INTEGER NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    PRIMARY KEY (lesson_id, prerequisite_id),
+    CHECK (lesson_id <> prerequisite_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_prerequisites;
\ No newline at end of file
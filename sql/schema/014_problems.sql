@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS problems (
+    id uuid PRIMARY KEY,
+    slug TEXT UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    statement TEXT NOT NULL DEFAULT '',
+    input_format TEXT NOT NULL DEFAULT '',
+    output_format TEXT NOT NULL DEFAULT '',
+    time_limit_ms INTEGER NOT NULL DEFAULT 1000 CHECK (time_limit_ms > 0),
+    memory_limit_mb INTEGER NOT NULL DEFAULT 256 CHECK (memory_limit_mb > 0),
+    difficulty TEXT NOT NULL DEFAULT 'medium' CHECK (difficulty IN ('easy', 'medium', 'hard')),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    author_id uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS problems_tags_idx ON problems USING GIN (tags);
+
+CREATE TABLE IF NOT EXISTS problem_samples (
+    problem_id uuid NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    input TEXT NOT NULL,
+    output TEXT NOT NULL,
+    explanation TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (problem_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS problem_samples;
+DROP TABLE IF EXISTS problems;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS problem_tests (
+    problem_id uuid NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    input_file_id uuid NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    output_file_id uuid NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    PRIMARY KEY (problem_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS problem_tests;
\ No newline at end of file
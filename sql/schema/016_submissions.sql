@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS submissions (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    problem_id uuid NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    source TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done')),
+    verdict TEXT NOT NULL DEFAULT '',
+    compile_output TEXT NOT NULL DEFAULT '',
+    time_ms BIGINT NOT NULL DEFAULT 0,
+    memory_kb BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    judged_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS submissions_user_idx ON submissions (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS submissions_problem_idx ON submissions (problem_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS submission_tests (
+    submission_id uuid NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    verdict TEXT NOT NULL,
+    time_ms BIGINT NOT NULL,
+    memory_kb BIGINT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (submission_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS submission_tests;
+DROP TABLE IF EXISTS submissions;
\ No newline at end of file
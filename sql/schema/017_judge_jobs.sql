@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS judge_jobs (
+    id uuid PRIMARY KEY,
+    submission_id uuid NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'done', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    run_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_by TEXT,
+    locked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Workers only ever look for queued jobs that are due
+CREATE INDEX IF NOT EXISTS judge_jobs_ready_idx ON judge_jobs (run_after, created_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS judge_jobs_running_idx ON judge_jobs (locked_at) WHERE status = 'running';
+
+-- +goose Down
+DROP TABLE IF EXISTS judge_jobs;
\ No newline at end of file
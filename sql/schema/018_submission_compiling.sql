@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE submissions
+DROP CONSTRAINT IF EXISTS submissions_status_check,
+ADD CONSTRAINT submissions_status_check CHECK (status IN ('pending', 'compiling', 'running', 'done'));
+
+-- +goose Down
+UPDATE submissions SET status = 'running' WHERE status = 'compiling';
+
+ALTER TABLE submissions
+DROP CONSTRAINT IF EXISTS submissions_status_check,
+ADD CONSTRAINT submissions_status_check CHECK (status IN ('pending', 'running', 'done'));
\ No newline at end of file
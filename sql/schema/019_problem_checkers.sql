@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE problems
+ADD COLUMN checker_mode TEXT NOT NULL DEFAULT 'exact' CHECK (checker_mode IN ('exact', 'whitespace', 'float', 'custom')),
+ADD COLUMN checker_epsilon DOUBLE PRECISION NOT NULL DEFAULT 1e-6,
+ADD COLUMN checker_source TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE problems
+DROP COLUMN checker_source,
+DROP COLUMN checker_epsilon,
+DROP COLUMN checker_mode;
\ No newline at end of file
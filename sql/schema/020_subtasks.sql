@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS problem_subtasks (
+    problem_id uuid NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    points INTEGER NOT NULL CHECK (points >= 0),
+    tests TEXT[] NOT NULL,
+    depends_on INTEGER[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (problem_id, number)
+);
+
+ALTER TABLE submissions
+ADD COLUMN score INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN max_score INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS submission_subtasks (
+    submission_id uuid NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    points INTEGER NOT NULL,
+    score INTEGER NOT NULL,
+    verdict TEXT NOT NULL,
+    PRIMARY KEY (submission_id, number)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS submission_subtasks;
+
+ALTER TABLE submissions
+DROP COLUMN max_score,
+DROP COLUMN score;
+
+DROP TABLE IF EXISTS problem_subtasks;
\ No newline at end of file
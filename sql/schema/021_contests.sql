@@ -0,0 +1,49 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS contests (
+    id uuid PRIMARY KEY,
+    slug TEXT UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    scoring TEXT NOT NULL DEFAULT 'icpc' CHECK (scoring IN ('icpc', 'ioi')),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    freeze_minutes INTEGER NOT NULL DEFAULT 0 CHECK (freeze_minutes >= 0),
+    unfrozen_at TIMESTAMP WITH TIME ZONE,
+    author_id uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS contest_problems (
+    contest_id uuid NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    problem_id uuid NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (contest_id, problem_id),
+    UNIQUE (contest_id, label)
+);
+
+CREATE INDEX IF NOT EXISTS contest_problems_problem_idx ON contest_problems (problem_id);
+
+CREATE TABLE IF NOT EXISTS contest_registrations (
+    contest_id uuid NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
+
+ALTER TABLE submissions
+ADD COLUMN contest_id uuid REFERENCES contests(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS submissions_contest_idx ON submissions (contest_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS submissions_contest_idx;
+
+ALTER TABLE submissions
+DROP COLUMN contest_id;
+
+DROP TABLE IF EXISTS contest_registrations;
+DROP TABLE IF EXISTS contest_problems;
+DROP TABLE IF EXISTS contests;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS contest_virtual_participations (
+    contest_id uuid NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
+
+ALTER TABLE submissions
+ADD COLUMN virtual BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE submissions
+DROP COLUMN virtual;
+
+DROP TABLE IF EXISTS contest_virtual_participations;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS rejudges (
+    id uuid PRIMARY KEY,
+    problem_id uuid NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    scope TEXT NOT NULL CHECK (scope IN ('problem', 'submission')),
+    requested_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- The verdicts before the rejudge, the new ones are read from the submissions themselves
+CREATE TABLE IF NOT EXISTS rejudge_submissions (
+    rejudge_id uuid NOT NULL REFERENCES rejudges(id) ON DELETE CASCADE,
+    submission_id uuid NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    old_verdict TEXT NOT NULL,
+    old_score INTEGER NOT NULL,
+    PRIMARY KEY (rejudge_id, submission_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS rejudge_submissions;
+DROP TABLE IF EXISTS rejudges;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Files outlive their uploader, deleting an admin must not take the tests of their problems along
+ALTER TABLE files
+ALTER COLUMN user_id DROP NOT NULL,
+DROP CONSTRAINT IF EXISTS files_user_id_fkey,
+ADD CONSTRAINT files_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+-- +goose Down
+-- Files left without an uploader would have been deleted along with them before
+DELETE FROM files
+WHERE user_id IS NULL;
+
+ALTER TABLE files
+DROP CONSTRAINT IF EXISTS files_user_id_fkey,
+ADD CONSTRAINT files_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+ALTER COLUMN user_id SET NOT NULL;
\ No newline at end of file
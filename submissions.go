@@ -0,0 +1,494 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/judge"
+	"Codium/internal/problems"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Submissions
+
+===========================================
+*/
+
+// maxSourceBytes bounds the size of a submitted source file
+const maxSourceBytes = 64 << 10
+
+type SubmissionTestResponse struct {
+	Position int32  `json:"position"`
+	Name     string `json:"name"`
+	Verdict  string `json:"verdict"`
+	TimeMs   int64  `json:"time_ms"`
+	MemoryKb int64  `json:"memory_kb"`
+	Message  string `json:"message,omitempty"`
+}
+
+type SubmissionResponse struct {
+	ID            uuid.UUID                `json:"id"`
+	UserID        uuid.UUID                `json:"user_id"`
+	ProblemID     uuid.UUID                `json:"problem_id"`
+	ContestID     uuid.NullUUID            `json:"contest_id"`
+	Virtual       bool                     `json:"virtual"`
+	Language      string                   `json:"language"`
+	Status        string                   `json:"status"`
+	Verdict       string                   `json:"verdict"`
+	Score         int32                    `json:"score"`
+	MaxScore      int32                    `json:"max_score"`
+	Subtasks      []problems.SubtaskScore  `json:"subtasks"`
+	CompileOutput string                   `json:"compile_output,omitempty"`
+	TimeMs        int64                    `json:"time_ms"`
+	MemoryKb      int64                    `json:"memory_kb"`
+	Source        string                   `json:"source,omitempty"`
+	Tests         []SubmissionTestResponse `json:"tests"`
+	CreatedAt     time.Time                `json:"created_at"`
+	JudgedAt      *time.Time               `json:"judged_at"`
+}
+
+func SubmissionToResponse(submission database.Submission, tests []database.SubmissionTest, subtasks []database.SubmissionSubtask) SubmissionResponse {
+	res := SubmissionResponse{
+		ID:            submission.ID,
+		UserID:        submission.UserID,
+		ProblemID:     submission.ProblemID,
+		ContestID:     submission.ContestID,
+		Virtual:       submission.Virtual,
+		Language:      submission.Language,
+		Status:        submission.Status,
+		Verdict:       submission.Verdict,
+		Score:         submission.Score,
+		MaxScore:      submission.MaxScore,
+		Subtasks:      make([]problems.SubtaskScore, 0, len(subtasks)),
+		CompileOutput: submission.CompileOutput,
+		TimeMs:        submission.TimeMs,
+		MemoryKb:      submission.MemoryKb,
+		Tests:         make([]SubmissionTestResponse, 0, len(tests)),
+		CreatedAt:     submission.CreatedAt,
+		JudgedAt:      nullTimeToPtr(submission.JudgedAt),
+	}
+	for _, test := range tests {
+		res.Tests = append(res.Tests, SubmissionTestResponse{
+			Position: test.Position,
+			Name:     test.Name,
+			Verdict:  test.Verdict,
+			TimeMs:   test.TimeMs,
+			MemoryKb: test.MemoryKb,
+			Message:  test.Message,
+		})
+	}
+	for _, subtask := range subtasks {
+		res.Subtasks = append(res.Subtasks, problems.SubtaskScore{
+			Number:  subtask.Number,
+			Points:  subtask.Points,
+			Score:   subtask.Score,
+			Verdict: subtask.Verdict,
+		})
+	}
+	return res
+}
+
+// CanViewSubmission reports whether user may see a submission, students only see their own
+func CanViewSubmission(user database.User, submission database.Submission) bool {
+	return submission.UserID == user.ID || user.IsAdmin || user.IsTeacher
+}
+
+var ErrJudgeInfrastructure = errors.New("judge failure")
+
+// JudgeSubmission compiles and runs a stored submission, recording every test as soon as it finishes.
+// A judge failure is returned as ErrJudgeInfrastructure so the job is retried, unless this is the last attempt
+// in which case the submission is recorded with an internal error verdict.
+func (cfg *ApiCfg) JudgeSubmission(ctx context.Context, submissionID uuid.UUID, lastAttempt bool) error {
+	submission, err := cfg.db.GetSubmissionByID(ctx, submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve submission: %v", err)
+	}
+	problem, err := cfg.db.GetProblemByID(ctx, submission.ProblemID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve problem: %v", err)
+	}
+
+	err = cfg.db.StartSubmission(ctx, submission.ID)
+	if err != nil {
+		return fmt.Errorf("failed to mark submission as compiling: %v", err)
+	}
+	err = cfg.db.DeleteSubmissionTests(ctx, submission.ID)
+	if err != nil {
+		return fmt.Errorf("failed to clear previous results: %v", err)
+	}
+	err = cfg.db.DeleteSubmissionSubtasks(ctx, submission.ID)
+	if err != nil {
+		return fmt.Errorf("failed to clear previous scores: %v", err)
+	}
+	cfg.submissionEvents.Notify(submission.ID)
+
+	var result judge.Result
+	sub, err := cfg.ProblemSubmission(ctx, problem, submission.Language, []byte(submission.Source))
+	if err != nil {
+		// The problem cannot be judged yet, the student is not at fault
+		result = judge.Result{Verdict: judge.VerdictInternalError, CompileOutput: err.Error()}
+	} else {
+		position := int32(0)
+		result = cfg.judge.Evaluate(ctx, sub, judge.Hooks{
+			Compiled: func() {
+				err := cfg.db.MarkSubmissionRunning(ctx, submission.ID)
+				if err != nil {
+					cfg.logger.Printf("Failed to mark submission %v as running: %v", submission.ID, err)
+				}
+				cfg.submissionEvents.Notify(submission.ID)
+			},
+			Test: func(test judge.TestResult) {
+				position++
+				err := cfg.db.AddSubmissionTest(ctx, database.AddSubmissionTestParams{
+					SubmissionID: submission.ID,
+					Position:     position,
+					Name:         test.Name,
+					Verdict:      string(test.Verdict),
+					TimeMs:       test.TimeMs,
+					MemoryKb:     test.MemoryKb,
+					Message:      test.Message,
+				})
+				if err != nil {
+					cfg.logger.Printf("Failed to record test %v of submission %v: %v", test.Name, submission.ID, err)
+				}
+				cfg.submissionEvents.Notify(submission.ID)
+			},
+		})
+	}
+
+	if result.Verdict == judge.VerdictInternalError && !lastAttempt {
+		message := result.CompileOutput
+		if len(result.Tests) > 0 {
+			message = result.Tests[len(result.Tests)-1].Message
+		}
+		return fmt.Errorf("%w: %v", ErrJudgeInfrastructure, message)
+	}
+
+	// Scores follow the subtasks as they are at judging time
+	subtasks, err := cfg.ProblemSubtasks(ctx, problem.ID)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(sub.Tests))
+	for _, test := range sub.Tests {
+		names = append(names, test.Name)
+	}
+	verdicts := make(map[string]string)
+	for _, test := range result.Tests {
+		verdicts[test.Name] = string(test.Verdict)
+	}
+	score, maxScore, breakdown := problems.Score(subtasks, names, verdicts)
+	for _, subtask := range breakdown {
+		err = cfg.db.AddSubmissionSubtask(ctx, database.AddSubmissionSubtaskParams{
+			SubmissionID: submission.ID,
+			Number:       subtask.Number,
+			Points:       subtask.Points,
+			Score:        subtask.Score,
+			Verdict:      subtask.Verdict,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record score of subtask %v: %v", subtask.Number, err)
+		}
+	}
+
+	_, err = cfg.db.FinishSubmission(ctx, database.FinishSubmissionParams{
+		ID:            submission.ID,
+		Verdict:       string(result.Verdict),
+		CompileOutput: result.CompileOutput,
+		TimeMs:        result.TimeMs,
+		MemoryKb:      result.MemoryKb,
+		Score:         score,
+		MaxScore:      maxScore,
+		JudgedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record verdict: %v", err)
+	}
+	cfg.submissionEvents.Notify(submission.ID)
+	cfg.logger.Printf("Submission %v judged: %v, %d/%d points", submission.ID, result.Verdict, score, maxScore)
+	return nil
+}
+
+type submissionParams struct {
+	Language string `json:"language"`
+	Source   string `json:"source"`
+}
+
+// decodeSubmission reads and checks a submitted source, writing the error on failure
+func (cfg *ApiCfg) decodeSubmission(w http.ResponseWriter, r *http.Request) (submissionParams, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, 2*maxSourceBytes)
+	decoder := json.NewDecoder(r.Body)
+	var p submissionParams
+	err := decoder.Decode(&p)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return p, false
+	}
+	if _, ok := cfg.judge.Languages.Get(p.Language); !ok {
+		http.Error(w, fmt.Sprintf("Unsupported language %q", p.Language), http.StatusBadRequest)
+		return p, false
+	}
+	if len(p.Source) == 0 || len(p.Source) > maxSourceBytes {
+		http.Error(w, fmt.Sprintf("Source must be between 1 and %v bytes", maxSourceBytes), http.StatusBadRequest)
+		return p, false
+	}
+	return p, true
+}
+
+// Submit stores a submission and queues it for judging, contestID is set for submissions made in a contest
+// and virtual for those made during a virtual participation
+func (cfg *ApiCfg) Submit(ctx context.Context, user database.User, problem database.Problem, p submissionParams, contestID uuid.NullUUID, virtual bool) (database.Submission, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Submission{}, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	submission, err := qtx.CreateSubmission(ctx, database.CreateSubmissionParams{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		ProblemID: problem.ID,
+		Language:  p.Language,
+		Source:    p.Source,
+		ContestID: contestID,
+		Virtual:   virtual,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return database.Submission{}, fmt.Errorf("failed to create submission: %v", err)
+	}
+	err = cfg.EnqueueSubmission(ctx, qtx, submission.ID)
+	if err != nil {
+		return database.Submission{}, fmt.Errorf("submission %v: %v", submission.ID, err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return database.Submission{}, fmt.Errorf("failed to commit submission: %v", err)
+	}
+
+	cfg.logger.Printf("User %v submitted %v for problem %v", user.ID, submission.ID, problem.ID)
+	cfg.WakeJudgeWorkers()
+	return submission, nil
+}
+
+func (cfg *ApiCfg) CreateSubmissionHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	problem, err := cfg.GetProblem(r.Context(), r.PathValue("problemID"))
+	if err == nil && !user.IsAdmin {
+		err = cfg.checkProblemVisible(r.Context(), problem)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", r.PathValue("problemID"))
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p, ok := cfg.decodeSubmission(w, r)
+	if !ok {
+		return
+	}
+
+	submission, err := cfg.Submit(r.Context(), user, problem, p, uuid.NullUUID{}, false)
+	if err != nil {
+		cfg.logger.Printf("Failed to store submission: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := SubmissionToResponse(submission, nil, nil)
+	res.Source = submission.Source
+	cfg.RespondWithJSON(w, http.StatusAccepted, res)
+}
+
+func (cfg *ApiCfg) GetSubmissionHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	submissionID, err := uuid.Parse(r.PathValue("submissionID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid submission ID: %v", err)
+		http.Error(w, "Invalid submission ID", http.StatusBadRequest)
+		return
+	}
+
+	submission, err := cfg.db.GetSubmissionByID(r.Context(), submissionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Submission not found: %v", submissionID)
+			http.Error(w, "Submission not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve submission: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !CanViewSubmission(user, submission) {
+		cfg.logger.Printf("Submission %v is not visible to user %v", submissionID, user.ID)
+		http.Error(w, "Submission not found", http.StatusNotFound)
+		return
+	}
+
+	tests, err := cfg.db.GetSubmissionTests(r.Context(), submission.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve submission tests: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	subtasks, err := cfg.db.GetSubmissionSubtasks(r.Context(), submission.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve submission subtasks: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := SubmissionToResponse(submission, tests, subtasks)
+	res.Source = submission.Source
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
+
+func (cfg *ApiCfg) GetUserSubmissionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	requestingUser, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid UUID format: %v", err)
+		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if requestingUser.ID != userID && !requestingUser.IsAdmin && !requestingUser.IsTeacher {
+		cfg.logger.Printf("Unauthorized submissions request by user: %v", requestingUser.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	q := r.URL.Query()
+	limit, offset := 20, 0
+	if q.Get("limit") != "" {
+		limit, err = strconv.Atoi(q.Get("limit"))
+		if err != nil || limit <= 0 || limit > 100 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	if q.Get("offset") != "" {
+		offset, err = strconv.Atoi(q.Get("offset"))
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+	params := database.GetUserSubmissionsParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}
+	if q.Get("problem") != "" {
+		problem, err := cfg.GetProblem(r.Context(), q.Get("problem"))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Problem not found", http.StatusNotFound)
+				return
+			}
+			cfg.logger.Printf("Failed to retrieve problem: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		params.ProblemID = uuid.NullUUID{UUID: problem.ID, Valid: true}
+	}
+
+	cfg.logger.Printf("Received submission history request for user ID: %v", userID)
+
+	rows, err := cfg.db.GetUserSubmissions(r.Context(), params)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve submissions: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	type submissionSummary struct {
+		ID           uuid.UUID  `json:"id"`
+		ProblemID    uuid.UUID  `json:"problem_id"`
+		ProblemSlug  string     `json:"problem_slug"`
+		ProblemTitle string     `json:"problem_title"`
+		Language     string     `json:"language"`
+		Status       string     `json:"status"`
+		Verdict      string     `json:"verdict"`
+		Score        int32      `json:"score"`
+		MaxScore     int32      `json:"max_score"`
+		TimeMs       int64      `json:"time_ms"`
+		MemoryKb     int64      `json:"memory_kb"`
+		CreatedAt    time.Time  `json:"created_at"`
+		JudgedAt     *time.Time `json:"judged_at"`
+	}
+	res := make([]submissionSummary, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, submissionSummary{
+			ID:           row.ID,
+			ProblemID:    row.ProblemID,
+			ProblemSlug:  row.ProblemSlug,
+			ProblemTitle: row.ProblemTitle,
+			Language:     row.Language,
+			Status:       row.Status,
+			Verdict:      row.Verdict,
+			Score:        row.Score,
+			MaxScore:     row.MaxScore,
+			TimeMs:       row.TimeMs,
+			MemoryKb:     row.MemoryKb,
+			CreatedAt:    row.CreatedAt,
+			JudgedAt:     nullTimeToPtr(row.JudgedAt),
+		})
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
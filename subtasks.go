@@ -0,0 +1,158 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/problems"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Subtasks
+
+===========================================
+*/
+
+// ProblemSubtasks returns the subtasks of a problem, empty when the problem is scored as a whole
+func (cfg *ApiCfg) ProblemSubtasks(ctx context.Context, problemID uuid.UUID) ([]problems.Subtask, error) {
+	rows, err := cfg.db.GetProblemSubtasks(ctx, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve subtasks: %v", err)
+	}
+	subtasks := make([]problems.Subtask, 0, len(rows))
+	for _, row := range rows {
+		subtasks = append(subtasks, problems.Subtask{
+			Number:    row.Number,
+			Points:    row.Points,
+			Tests:     row.Tests,
+			DependsOn: row.DependsOn,
+		})
+	}
+	return subtasks, nil
+}
+
+// ReplaceProblemSubtasks stores validated subtasks in place of the current ones
+func (cfg *ApiCfg) ReplaceProblemSubtasks(ctx context.Context, problemID uuid.UUID, subtasks []problems.Subtask) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.DeleteProblemSubtasks(ctx, problemID)
+	if err != nil {
+		return fmt.Errorf("failed to clear subtasks: %v", err)
+	}
+	for _, subtask := range subtasks {
+		err = qtx.AddProblemSubtask(ctx, database.AddProblemSubtaskParams{
+			ProblemID: problemID,
+			Number:    subtask.Number,
+			Points:    subtask.Points,
+			Tests:     subtask.Tests,
+			DependsOn: subtask.DependsOn,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store subtask %v: %v", subtask.Number, err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit subtasks: %v", err)
+	}
+	return nil
+}
+
+func (cfg *ApiCfg) GetProblemSubtasksHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	problem, err := cfg.GetProblem(r.Context(), r.PathValue("problemID"))
+	if err == nil {
+		if user, authErr := cfg.AuthenticateUser(r); authErr != nil || !user.IsAdmin {
+			err = cfg.checkProblemVisible(r.Context(), problem)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Problem not found: %v", r.PathValue("problemID"))
+			http.Error(w, "Problem not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve problem: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	subtasks, err := cfg.ProblemSubtasks(r.Context(), problem.ID)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, subtasks)
+}
+
+// UpdateProblemSubtasksHandler replaces the subtasks of a problem, an empty list scores the problem as a whole
+func (cfg *ApiCfg) UpdateProblemSubtasksHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	_, problem, ok := cfg.adminProblemFromRequest(w, r, "subtask update")
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var subtasks []problems.Subtask
+	err := decoder.Decode(&subtasks)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	tests, err := cfg.db.GetProblemTests(r.Context(), problem.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve problem tests: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	names := make([]string, 0, len(tests))
+	for _, test := range tests {
+		names = append(names, test.Name)
+	}
+	err = problems.ValidateSubtasks(subtasks, names)
+	if err != nil {
+		cfg.logger.Printf("Invalid subtasks for problem %v: %v", problem.ID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg.logger.Printf("Received subtask update for problem %v: %d subtasks", problem.ID, len(subtasks))
+
+	err = cfg.ReplaceProblemSubtasks(r.Context(), problem.ID, subtasks)
+	if err != nil {
+		cfg.logger.Printf("Problem %v: %v", problem.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.RespondWithJSON(w, http.StatusOK, subtasks)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"Codium/internal/contests"
+	"Codium/internal/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Virtual Participation
+
+===========================================
+*/
+
+type VirtualParticipationResponse struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	StartedAt time.Time `json:"started_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Status    string    `json:"status"`
+	// Rank is where the participant would have placed among the official participants
+	Rank         int                      `json:"rank"`
+	Participants int                      `json:"participants"`
+	Row          contests.Row             `json:"row"`
+	Scoring      string                   `json:"scoring"`
+	Problems     []ContestProblemResponse `json:"problems"`
+	Rows         []contests.Row           `json:"rows"`
+}
+
+// virtualStanding places a virtual participant on the official scoreboard. While their window runs
+// they are compared with the official participants at the same point of the contest
+func (cfg *ApiCfg) virtualStanding(ctx context.Context, contest database.Contest, user database.User, participation database.ContestVirtualParticipation) (VirtualParticipationResponse, error) {
+	now := time.Now()
+	window := ContestWindow(contest)
+	virtualWindow := window.Virtual(participation.StartedAt)
+
+	board, err := cfg.loadContestBoard(ctx, contest, true)
+	if err != nil {
+		return VirtualParticipationResponse{}, err
+	}
+	rows, err := cfg.db.GetVirtualAttempts(ctx, database.GetVirtualAttemptsParams{
+		ContestID: uuid.NullUUID{UUID: contest.ID, Valid: true},
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return VirtualParticipationResponse{}, fmt.Errorf("failed to retrieve virtual submissions: %v", err)
+	}
+
+	elapsed := now.Sub(participation.StartedAt)
+	if now.After(virtualWindow.EndsAt) {
+		elapsed = window.Duration()
+	}
+	attempts := make([]contests.Attempt, 0, len(board.attempts)+len(rows))
+	for _, attempt := range board.attempts {
+		if attempt.CreatedAt.Sub(contest.StartsAt) < elapsed {
+			attempts = append(attempts, attempt)
+		}
+	}
+	for _, row := range rows {
+		attempts = append(attempts, attemptFromRow(database.GetContestAttemptsRow(row)))
+	}
+
+	// The official participant list may already hold the user if they registered and never submitted
+	participants := make([]contests.Participant, 0, len(board.participants)+1)
+	for _, participant := range board.participants {
+		if participant.UserID != user.ID {
+			participants = append(participants, participant)
+		}
+	}
+	participants = append(participants, contests.Participant{
+		UserID:   user.ID,
+		Username: user.Username,
+		StartsAt: participation.StartedAt,
+		Virtual:  true,
+	})
+
+	// Official results stay hidden while the official scoreboard is frozen
+	freezeAfter := time.Duration(0)
+	if window.Frozen(now) {
+		freezeAfter = window.FreezeAfter()
+	}
+
+	res := VirtualParticipationResponse{
+		ContestID:    contest.ID,
+		StartedAt:    virtualWindow.StartsAt,
+		EndsAt:       virtualWindow.EndsAt,
+		Status:       "running",
+		Participants: len(participants),
+		Scoring:      contest.Scoring,
+		Problems:     board.problems,
+		Rows:         contests.Standings(contests.Scoring(contest.Scoring), board.problemIDs, participants, attempts, freezeAfter),
+	}
+	if !virtualWindow.Running(now) {
+		res.Status = "ended"
+	}
+	for _, row := range res.Rows {
+		if row.Virtual {
+			res.Row = row
+			res.Rank = row.Rank
+		}
+	}
+	return res, nil
+}
+
+// StartVirtualParticipationHandler starts a personal run of a contest that has ended,
+// users who already competed in the official round cannot take it again
+func (cfg *ApiCfg) StartVirtualParticipationHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if ContestStatus(contest, time.Now()) != "ended" {
+		http.Error(w, "Virtual participation opens once the contest has ended", http.StatusConflict)
+		return
+	}
+
+	competed, err := cfg.db.HasContestSubmissions(r.Context(), database.HasContestSubmissionsParams{
+		ContestID: uuid.NullUUID{UUID: contest.ID, Valid: true},
+		UserID:    user.ID,
+	})
+	if err != nil {
+		cfg.logger.Printf("Failed to check contest submissions: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if competed {
+		http.Error(w, "You already took part in this contest", http.StatusConflict)
+		return
+	}
+
+	participation, err := cfg.db.StartVirtualParticipation(r.Context(), database.StartVirtualParticipationParams{
+		ContestID: contest.ID,
+		UserID:    user.ID,
+		StartedAt: time.Now(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// The insert skipped an existing row, so a participation was already started
+		http.Error(w, "Virtual participation already started", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		cfg.logger.Printf("Failed to start virtual participation: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.logger.Printf("User %v started a virtual participation in contest %v", user.ID, contest.ID)
+
+	res, err := cfg.virtualStanding(r.Context(), contest, user, participation)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusCreated, res)
+}
+
+// GetVirtualParticipationHandler shows the window of the user's virtual participation and where they place
+func (cfg *ApiCfg) GetVirtualParticipationHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	contest, ok := cfg.contestFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	participation, err := cfg.db.GetVirtualParticipation(r.Context(), database.GetVirtualParticipationParams{ContestID: contest.ID, UserID: user.ID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "No virtual participation", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve virtual participation: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res, err := cfg.virtualStanding(r.Context(), contest, user, participation)
+	if err != nil {
+		cfg.logger.Printf("Contest %v: %v", contest.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/lessons"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const lessonRoot = "App/Lectii"
+const lessonManifest = "App/Lectii/manifest.json"
+
+// StartLessonWatcher polls App/Lectii and rebuilds the lesson catalog whenever a lesson file changes
+func (cfg *ApiCfg) StartLessonWatcher(interval time.Duration) {
+	cfg.logger.Printf("Starting lesson watcher, polling every %v", interval)
+	go func() {
+		for cfg.running {
+			fingerprint, err := lessons.Fingerprint(lessonRoot)
+			if err != nil {
+				cfg.logger.Printf("Failed to check lessons for changes: %v", err)
+			} else if fingerprint != cfg.getLessonFingerprint() {
+				cfg.logger.Print("Lesson files changed, rebuilding the catalog")
+				_, err = cfg.RescanLessons()
+				if err != nil {
+					cfg.logger.Printf("Failed to rebuild the lesson catalog: %v", err)
+				}
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func (cfg *ApiCfg) getLessonFingerprint() string {
+	cfg.lessonScanMu.Lock()
+	defer cfg.lessonScanMu.Unlock()
+	return cfg.lessonFingerprint
+}
+
+// RescanLessons rebuilds the lesson catalog and manifest.json from the files in App/Lectii
+func (cfg *ApiCfg) RescanLessons() (int, error) {
+	cfg.lessonScanMu.Lock()
+	defer cfg.lessonScanMu.Unlock()
+
+	// Taken before scanning, so edits made during the scan trigger another rebuild
+	fingerprint, err := lessons.Fingerprint(lessonRoot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fingerprint lessons: %v", err)
+	}
+
+	entries, problems, err := lessons.Scan(lessonRoot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan lessons: %v", err)
+	}
+	for _, problem := range problems {
+		cfg.logger.Printf("Lesson scan: %v", problem)
+	}
+
+	// The manifest is served statically, so it only lists published lessons
+	published := entries
+	if cfg.dbLoaded {
+		published = nil
+		ctx := context.Background()
+		found := make(map[int32]bool)
+		for _, entry := range entries {
+			// Lessons found on disk are public unless the catalog already says otherwise
+			lesson, err := cfg.StoreLesson(ctx, entry.Meta, entry.Path, entry.Body, lessons.StatusPublished, uuid.NullUUID{})
+			if err != nil {
+				return 0, fmt.Errorf("failed to store lesson %v: %v", entry.Meta.ID, err)
+			}
+			// Edits made directly on disk have no known author
+			err = cfg.RecordLessonRevision(ctx, entry.Meta.ID, uuid.NullUUID{}, entry.Content)
+			if err != nil {
+				return 0, fmt.Errorf("failed to record revision of lesson %v: %v", entry.Meta.ID, err)
+			}
+			found[entry.Meta.ID] = true
+			if lesson.Status == string(lessons.StatusArchived) {
+				lesson, err = cfg.restoreReturnedLesson(ctx, lesson)
+				if err != nil {
+					return 0, err
+				}
+			}
+			if lesson.Status == string(lessons.StatusPublished) {
+				published = append(published, entry)
+			}
+		}
+
+		// Lessons whose file was removed are archived, their revisions, reviews and progress stay. A file that
+		// is still there but failed to parse keeps its lesson as it was until it is fixed.
+		stored, err := cfg.db.GetLessons(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list lessons: %v", err)
+		}
+		for _, lesson := range stored {
+			if found[lesson.ID] || lesson.Status == string(lessons.StatusArchived) {
+				continue
+			}
+			_, err = os.Stat(lesson.Filepath)
+			if !errors.Is(err, os.ErrNotExist) {
+				cfg.logger.Printf("Lesson %v was not read from %v, keeping it until the file is fixed", lesson.ID, lesson.Filepath)
+				continue
+			}
+			err = cfg.archiveRemovedLesson(ctx, lesson)
+			if err != nil {
+				return 0, err
+			}
+			cfg.logger.Printf("Archived lesson %v, its file no longer exists", lesson.ID)
+		}
+
+		cfg.syncFrontMatterPrerequisites(ctx, entries, found)
+	}
+
+	err = lessons.WriteManifest(lessonManifest, published)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write lesson manifest: %v", err)
+	}
+
+	cfg.lessonFingerprint = fingerprint
+	cfg.logger.Printf("Lesson catalog rebuilt: %d lessons, %d problems", len(entries), len(problems))
+	return len(entries), nil
+}
+
+// removedLessonComment marks the reviews of lessons archived because their file was deleted
+const removedLessonComment = "lesson file was removed"
+
+// archiveRemovedLesson hides a lesson whose file was deleted, restoring it brings it back as a draft
+func (cfg *ApiCfg) archiveRemovedLesson(ctx context.Context, lesson database.Lesson) error {
+	_, err := cfg.changeLessonStatus(ctx, lesson, lessons.ActionArchive, lessons.StatusArchived, uuid.NullUUID{}, removedLessonComment)
+	if err != nil {
+		return fmt.Errorf("failed to archive lesson %v: %v", lesson.ID, err)
+	}
+	return nil
+}
+
+// restoreReturnedLesson turns a lesson archived by archiveRemovedLesson back into a draft once its file is
+// there again. Lessons an admin archived stay archived.
+func (cfg *ApiCfg) restoreReturnedLesson(ctx context.Context, lesson database.Lesson) (database.Lesson, error) {
+	reviews, err := cfg.db.GetLessonReviews(ctx, lesson.ID)
+	if err != nil {
+		return lesson, fmt.Errorf("failed to retrieve reviews of lesson %v: %v", lesson.ID, err)
+	}
+	if len(reviews) == 0 || reviews[0].ReviewerID.Valid || reviews[0].Comment != removedLessonComment {
+		return lesson, nil
+	}
+
+	restored, err := cfg.changeLessonStatus(ctx, lesson, lessons.ActionRestore, lessons.StatusDraft, uuid.NullUUID{}, "lesson file was restored")
+	if err != nil {
+		return lesson, fmt.Errorf("failed to restore lesson %v: %v", lesson.ID, err)
+	}
+	cfg.logger.Printf("Restored lesson %v as a draft, its file is back", lesson.ID)
+	return restored, nil
+}
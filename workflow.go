@@ -0,0 +1,236 @@
+package main
+
+import (
+	"Codium/internal/database"
+	"Codium/internal/lessons"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+===========================================
+
+	Lesson Workflow
+
+===========================================
+*/
+
+type LessonReviewResponse struct {
+	ID         uuid.UUID     `json:"id"`
+	LessonID   int32         `json:"lesson_id"`
+	ReviewerID uuid.NullUUID `json:"reviewer_id"`
+	Action     string        `json:"action"`
+	FromStatus string        `json:"from_status"`
+	ToStatus   string        `json:"to_status"`
+	Comment    string        `json:"comment"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// ChangeLessonStatusHandler moves a lesson through draft -> in_review -> published -> archived.
+// Authors submit their drafts, admins review, publish and archive.
+func (cfg *ApiCfg) ChangeLessonStatusHandler(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		Action  string `json:"action"`
+		Comment string `json:"comment"`
+	}
+
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	var p params
+	err = decoder.Decode(&p)
+	if err != nil {
+		cfg.logger.Printf("Invalid request body: %v", err)
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	action := lessons.Action(p.Action)
+	p.Comment = strings.TrimSpace(p.Comment)
+
+	cfg.logger.Printf("Received %v request for lesson %v from user %v", action, lessonID, user.ID)
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	isAuthor := lesson.AuthorID.Valid && lesson.AuthorID.UUID == user.ID
+	if !user.IsAdmin && !isAuthor {
+		cfg.logger.Printf("Lesson %v is not visible to user %v", lessonID, user.ID)
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		return
+	}
+	if lessons.IsReviewAction(action) && !user.IsAdmin {
+		cfg.logger.Printf("Unauthorized review attempt by non-admin user: %v", user.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if action == lessons.ActionReject && p.Comment == "" {
+		http.Error(w, "A comment is required when rejecting a lesson", http.StatusBadRequest)
+		return
+	}
+
+	from := lessons.Status(lesson.Status)
+	to, err := lessons.Transition(from, action)
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson transition: %v", err)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	updated, err := cfg.changeLessonStatus(r.Context(), lesson, action, to, uuid.NullUUID{UUID: user.ID, Valid: true}, p.Comment)
+	if err != nil {
+		cfg.logger.Printf("Lesson %v: %v", lesson.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.logger.Printf("Lesson %v moved from %v to %v by %v", lesson.ID, from, to, user.ID)
+
+	// The manifest only lists published lessons
+	if from == lessons.StatusPublished || to == lessons.StatusPublished {
+		go func() {
+			_, err := cfg.RescanLessons()
+			if err != nil {
+				cfg.logger.Printf("Failed to rebuild the lesson catalog: %v", err)
+			}
+		}()
+	}
+
+	cfg.RespondWithJSON(w, http.StatusOK, LessonToResponse(updated, false))
+}
+
+// changeLessonStatus moves a lesson to a new status and records the review in the same transaction,
+// the history never misses a change nor lists one that did not happen
+func (cfg *ApiCfg) changeLessonStatus(ctx context.Context, lesson database.Lesson, action lessons.Action, to lessons.Status, reviewer uuid.NullUUID, comment string) (database.Lesson, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return lesson, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	updated, err := qtx.UpdateLessonStatus(ctx, database.UpdateLessonStatusParams{
+		ID:        lesson.ID,
+		Status:    string(to),
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return lesson, fmt.Errorf("failed to update lesson status: %v", err)
+	}
+
+	_, err = qtx.CreateLessonReview(ctx, database.CreateLessonReviewParams{
+		ID:         uuid.New(),
+		LessonID:   lesson.ID,
+		ReviewerID: reviewer,
+		Action:     string(action),
+		FromStatus: lesson.Status,
+		ToStatus:   string(to),
+		Comment:    comment,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return lesson, fmt.Errorf("failed to record lesson review: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return lesson, fmt.Errorf("failed to commit lesson status: %v", err)
+	}
+	return updated, nil
+}
+
+func (cfg *ApiCfg) GetLessonReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	// Check if database is connected
+	if !cfg.dbLoaded {
+		cfg.logger.Println("Database not connected")
+		http.Error(w, "Database not connected", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := cfg.AuthenticateUser(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := strconv.Atoi(r.PathValue("lessonID"))
+	if err != nil {
+		cfg.logger.Printf("Invalid lesson ID: %v", err)
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := cfg.db.GetLessonByID(r.Context(), int32(lessonID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.logger.Printf("Lesson not found: %v", lessonID)
+			http.Error(w, "Lesson not found", http.StatusNotFound)
+			return
+		}
+		cfg.logger.Printf("Failed to retrieve lesson: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !user.IsAdmin && (!lesson.AuthorID.Valid || lesson.AuthorID.UUID != user.ID) {
+		cfg.logger.Printf("Unauthorized review history request by user: %v", user.ID)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	reviews, err := cfg.db.GetLessonReviews(r.Context(), lesson.ID)
+	if err != nil {
+		cfg.logger.Printf("Failed to retrieve lesson reviews: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]LessonReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		res = append(res, LessonReviewResponse{
+			ID:         review.ID,
+			LessonID:   review.LessonID,
+			ReviewerID: review.ReviewerID,
+			Action:     review.Action,
+			FromStatus: review.FromStatus,
+			ToStatus:   review.ToStatus,
+			Comment:    review.Comment,
+			CreatedAt:  review.CreatedAt,
+		})
+	}
+	cfg.RespondWithJSON(w, http.StatusOK, res)
+}